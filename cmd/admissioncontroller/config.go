@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"go.uber.org/zap/zapcore"
@@ -25,11 +27,14 @@ type runtimeConfiguration struct {
 	CertSecretName string
 	// EnableCertRotation sets if cert rotation should be enabled.
 	EnableCertRotation bool
+	// StateStore sets where the original replicas of scaled down workloads are kept.
+	StateStore string
 }
 
 func getDefaultConfig() *runtimeConfiguration {
 	return &runtimeConfiguration{
 		CommonRuntimeConfiguration: *util.GetDefaultConfig(),
+		StateStore:                 kubernetes.StateStoreAnnotation,
 	}
 }
 
@@ -59,6 +64,12 @@ func (c *runtimeConfiguration) parseConfigFlags() {
 		"go-kube-downscaler-webhook",
 		"secret name containing the TLS certs for the webhook (default: go-kube-downscaler-webhook)",
 	)
+	flag.StringVar(
+		&c.StateStore,
+		"state-store",
+		kubernetes.StateStoreAnnotation,
+		"where to keep the original replicas of scaled down workloads: annotation, configmap or crd (default: annotation)",
+	)
 }
 
 //nolint:nonamedreturns //required for function clarity
@@ -98,6 +109,13 @@ func initComponent() (runtimeConfig *runtimeConfiguration, scopeDefault, scopeCl
 		os.Exit(1)
 	}
 
+	if runtimeConfig.CustomResourcesConfig != "" {
+		if err = scalable.LoadCustomResources(runtimeConfig.CustomResourcesConfig); err != nil {
+			slog.Error("failed to load custom resources", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	slog.Debug(
 		"finished getting startup runtimeConfig",
 		"envScope", scopeEnv,
@@ -12,6 +12,7 @@ import (
 
 	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
 	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes/admission"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/audit"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +35,7 @@ type serverConfig struct {
 	config               *runtimeConfiguration
 	includedResourcesSet map[string]struct{}
 	admissionMetrics     *metrics.AdmissionMetrics
+	auditSink            audit.Sink
 }
 
 const (
@@ -53,7 +55,7 @@ func main() {
 	scheme := apimachineryruntime.NewScheme()
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
-	client, err := kubernetes.NewClient(config.Kubeconfig, config.DryRun, config.Qps, config.Burst)
+	client, err := kubernetes.NewClient(config.Kubeconfig, config.DryRun, config.Qps, config.Burst, config.StateStore)
 	if err != nil {
 		slog.Error("failed to create new Kubernetes client", "error", err)
 		os.Exit(1)
@@ -61,7 +63,7 @@ func main() {
 
 	// Create a second client that is not in dry-run mode, for cert rotation which should always be performed
 	// even when other operations are in dry-run mode
-	clientNoDryRun, err := kubernetes.NewClient(config.Kubeconfig, false, config.Qps, config.Burst)
+	clientNoDryRun, err := kubernetes.NewClient(config.Kubeconfig, false, config.Qps, config.Burst, kubernetes.StateStoreAnnotation)
 	if err != nil {
 		slog.Error("failed to create new Kubernetes client", "error", err)
 		os.Exit(1)
@@ -69,6 +71,16 @@ func main() {
 
 	admissionMetrics, bindAddress := initAdmissionMetrics(config)
 
+	auditSink, err := audit.NewSink(config.AuditSink, config.AuditFileMaxSize, config.AuditFileMaxBackups)
+	if err != nil {
+		slog.Error("failed to create audit sink", "error", err)
+		os.Exit(1)
+	}
+
+	// admission requests must be answered within the webhook timeout, so records are written in the background
+	auditSink = audit.NewBufferedSink(auditSink)
+	defer auditSink.Close()
+
 	includedResourcesSet := toSet(config.IncludeResources)
 
 	serverConfig := &serverConfig{
@@ -80,6 +92,7 @@ func main() {
 		config:               config,
 		includedResourcesSet: includedResourcesSet,
 		admissionMetrics:     admissionMetrics,
+		auditSink:            auditSink,
 	}
 
 	opts := setupControllerRuntimeLogEncoding(config)
@@ -182,6 +195,7 @@ func (s *serverConfig) serveValidateWorkloads(writer http.ResponseWriter, reques
 		s.includedResourcesSet,
 		s.config.MetricsEnabled,
 		s.admissionMetrics,
+		s.auditSink,
 	)
 	admissionHandler.HandleWorkloadMutation(ctx, writer, request)
 
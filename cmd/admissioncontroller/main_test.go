@@ -39,6 +39,16 @@ func (m *MockClient) GetScaledObjects(namespace string, ctx context.Context) ([]
 	return args.Get(0).([]scalable.Workload), args.Error(1)
 }
 
+func (m *MockClient) GetHorizontalPodAutoscalers(namespace string, ctx context.Context) ([]scalable.Workload, error) {
+	args := m.Called(namespace, ctx)
+	return args.Get(0).([]scalable.Workload), args.Error(1)
+}
+
+func (m *MockClient) GetPauseMode(ctx context.Context) (values.PauseMode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(values.PauseMode), args.Error(1)
+}
+
 type mockCertManager struct {
 	Ready chan struct{}
 }
@@ -204,8 +214,10 @@ func TestServeValidateWorkloads(t *testing.T) {
 			t.Parallel()
 
 			mockKubeClient := &MockClient{}
+			mockKubeClient.On("GetPauseMode", mock.Anything).Return(values.PauseModeNone, nil)
 			mockKubeClient.On("GetNamespaceScope", "default", mock.Anything).Return(values.NewScope(), nil)
 			mockKubeClient.On("GetScaledObjects", "default", mock.Anything).Return([]scalable.Workload{}, nil)
+			mockKubeClient.On("GetHorizontalPodAutoscalers", "default", mock.Anything).Return([]scalable.Workload{}, nil)
 
 			serverConfiguration := &serverConfig{
 				client:               mockKubeClient,
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// adminAPI serves the endpoints to trigger scans and scale workloads manually.
+type adminAPI struct {
+	client  kubernetes.Client
+	trigger *scanTrigger
+	health  *healthStatus
+	token   []byte
+	config  *runtimeConfiguration
+}
+
+// adminAPIResponse is the json body returned by all admin api endpoints.
+type adminAPIResponse struct {
+	Message string `json:"message"`
+}
+
+// serveAdminAPI starts the admin api server for the downscaler.
+func serveAdminAPI(client kubernetes.Client, trigger *scanTrigger, health *healthStatus, config *runtimeConfiguration) {
+	token, err := os.ReadFile(config.AdminAPITokenFile)
+	if err != nil {
+		slog.Error("failed to read admin api token file", "error", err)
+		os.Exit(1)
+	}
+
+	token = []byte(strings.TrimSpace(string(token)))
+	if len(token) == 0 {
+		slog.Error("admin api token file is empty")
+		os.Exit(1)
+	}
+
+	api := &adminAPI{client: client, trigger: trigger, health: health, token: token, config: config}
+
+	server := &http.Server{
+		Addr:         ":8082",
+		Handler:      api.newHandler(),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	slog.Info("serving admin api on :8082")
+
+	err = server.ListenAndServe()
+	if err != nil {
+		slog.Error("failed to start admin api server", "error", err)
+		os.Exit(1)
+	}
+}
+
+// newHandler creates the handler with all admin api endpoints.
+func (a *adminAPI) newHandler() http.Handler {
+	serveMux := http.NewServeMux()
+
+	serveMux.HandleFunc("POST /api/v1/scan", a.authenticate(a.handleScan))
+	serveMux.HandleFunc("POST /api/v1/namespaces/{namespace}/scan", a.authenticate(a.handleScanNamespace))
+	serveMux.HandleFunc("POST /api/v1/acknowledge", a.authenticate(a.handleAcknowledge))
+	serveMux.HandleFunc("POST /api/v1/pause", a.authenticate(a.handlePause))
+	serveMux.HandleFunc("POST /api/v1/resume", a.authenticate(a.handleResume))
+	serveMux.HandleFunc("POST /api/v1/namespaces/{namespace}/scale", a.authenticate(a.handleScaleNamespace))
+	serveMux.HandleFunc("POST /api/v1/namespaces/{namespace}/acknowledge", a.authenticate(a.handleAcknowledgeNamespace))
+	serveMux.HandleFunc("POST /api/v1/namespaces/{namespace}/{resourceType}/{name}/scale", a.authenticate(a.handleScaleWorkload))
+
+	return serveMux
+}
+
+// authenticate only calls the next handler if the request has the admin api token as its bearer token.
+func (a *adminAPI) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), a.token) != 1 {
+			writeAdminAPIResponse(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+// handleScan requests an immediate scan of all namespaces.
+func (a *adminAPI) handleScan(w http.ResponseWriter, _ *http.Request) {
+	if !a.health.isScanning() {
+		a.writeNotScanningResponse(w)
+		return
+	}
+
+	slog.Info("admin api requested a scan of all namespaces")
+	a.trigger.requestScan("")
+
+	writeAdminAPIResponse(w, http.StatusAccepted, "scan of all namespaces requested")
+}
+
+// handleScanNamespace requests an immediate scan of a single namespace.
+func (a *adminAPI) handleScanNamespace(w http.ResponseWriter, req *http.Request) {
+	namespace := req.PathValue("namespace")
+	if !a.isNamespaceIncluded(namespace) {
+		writeAdminAPIResponse(w, http.StatusNotFound, fmt.Sprintf("namespace %q is not included in the downscaler", namespace))
+		return
+	}
+
+	if !a.health.isScanning() {
+		a.writeNotScanningResponse(w)
+		return
+	}
+
+	slog.Info("admin api requested a scan of a namespace", "namespace", namespace)
+	a.trigger.requestScan(namespace)
+
+	writeAdminAPIResponse(w, http.StatusAccepted, fmt.Sprintf("scan of namespace %q requested", namespace))
+}
+
+// writeNotScanningResponse rejects a scan request received by an instance which doesn't scan, e.g. because it isn't the leader.
+// Scan requests are only kept in memory, so they would never be picked up.
+func (a *adminAPI) writeNotScanningResponse(w http.ResponseWriter) {
+	leader := a.health.getLeader()
+	if leader == "" {
+		writeAdminAPIResponse(w, http.StatusServiceUnavailable, "this instance isn't scanning, no leader is elected yet")
+		return
+	}
+
+	message := fmt.Sprintf("this instance isn't scanning, send the request to the leader %q", leader)
+	writeAdminAPIResponse(w, http.StatusServiceUnavailable, message)
+}
+
+// handleAcknowledge lets the circuit breaker accept downscaling any amount of workloads for a duration
+// by annotating the downscaler's namespace.
+func (a *adminAPI) handleAcknowledge(w http.ResponseWriter, req *http.Request) {
+	duration, err := getDurationFromQuery(req)
+	if err != nil {
+		writeAdminAPIResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	err = a.client.AcknowledgeMassDownscaling(time.Now().Add(duration), ctx)
+	if err != nil {
+		slog.Error("failed to record acknowledgement", "error", err)
+		writeAdminAPIResponse(w, http.StatusInternalServerError, "failed to record acknowledgement")
+
+		return
+	}
+
+	slog.Info("admin api acknowledged mass downscaling", "duration", duration.String())
+	a.trigger.requestScan("")
+
+	writeAdminAPIResponse(w, http.StatusAccepted, "mass downscaling acknowledged for "+duration.String())
+}
+
+// handlePause pauses the downscaler with the mode from the mode query parameter.
+func (a *adminAPI) handlePause(w http.ResponseWriter, req *http.Request) {
+	modeString := req.URL.Query().Get("mode")
+
+	mode, err := values.ParsePauseMode(modeString)
+	if err != nil || !mode.IsPaused() {
+		err = newInvalidQueryParameterError("mode", "has to be either freeze or upscale-all", modeString)
+		writeAdminAPIResponse(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	a.setPauseMode(w, req, mode)
+}
+
+// handleResume resumes the paused downscaler.
+func (a *adminAPI) handleResume(w http.ResponseWriter, req *http.Request) {
+	a.setPauseMode(w, req, values.PauseModeNone)
+}
+
+// setPauseMode records the pause mode on the downscaler's namespace and requests a scan of all namespaces to apply it.
+func (a *adminAPI) setPauseMode(w http.ResponseWriter, req *http.Request, mode values.PauseMode) {
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	err := a.client.SetPauseMode(mode, ctx)
+	if err != nil {
+		slog.Error("failed to set pause mode", "error", err, "mode", mode.String())
+		writeAdminAPIResponse(w, http.StatusInternalServerError, "failed to set pause mode")
+
+		return
+	}
+
+	slog.Info("admin api set pause mode", "mode", mode.String())
+	a.trigger.requestScan("")
+
+	writeAdminAPIResponse(w, http.StatusAccepted, "pause mode set to "+mode.String())
+}
+
+// handleAcknowledgeNamespace lets the circuit breaker accept downscaling the workloads of a namespace by annotating the namespace.
+func (a *adminAPI) handleAcknowledgeNamespace(w http.ResponseWriter, req *http.Request) {
+	namespace := req.PathValue("namespace")
+	if !a.isNamespaceIncluded(namespace) {
+		writeAdminAPIResponse(w, http.StatusNotFound, fmt.Sprintf("namespace %q is not included in the downscaler", namespace))
+		return
+	}
+
+	duration, err := getDurationFromQuery(req)
+	if err != nil {
+		writeAdminAPIResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	err = a.client.AnnotateNamespace(namespace, values.GetAcknowledgeAnnotations(time.Now().Add(duration)), ctx)
+	if err != nil {
+		slog.Error("failed to record acknowledgement on namespace", "error", err, "namespace", namespace)
+		writeAdminAPIResponse(w, http.StatusInternalServerError, "failed to record acknowledgement on namespace")
+
+		return
+	}
+
+	slog.Info("admin api acknowledged mass downscaling of a namespace", "namespace", namespace, "duration", duration.String())
+	a.trigger.requestScan(namespace)
+
+	writeAdminAPIResponse(w, http.StatusAccepted, fmt.Sprintf("mass downscaling of namespace %q acknowledged", namespace))
+}
+
+// handleScaleNamespace forces all workloads of a namespace up or down for a duration by annotating the namespace.
+func (a *adminAPI) handleScaleNamespace(w http.ResponseWriter, req *http.Request) {
+	namespace := req.PathValue("namespace")
+	if !a.isNamespaceIncluded(namespace) {
+		writeAdminAPIResponse(w, http.StatusNotFound, fmt.Sprintf("namespace %q is not included in the downscaler", namespace))
+		return
+	}
+
+	annotations, err := getManualScalingAnnotationsFromQuery(req)
+	if err != nil {
+		writeAdminAPIResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	err = a.client.AnnotateNamespace(namespace, annotations, ctx)
+	if err != nil {
+		slog.Error("failed to record manual scaling on namespace", "error", err, "namespace", namespace)
+		writeAdminAPIResponse(w, http.StatusInternalServerError, "failed to record manual scaling on namespace")
+
+		return
+	}
+
+	slog.Info("admin api requested manual scaling of a namespace", "namespace", namespace, "annotations", annotations)
+	a.trigger.requestScan(namespace)
+
+	writeAdminAPIResponse(w, http.StatusAccepted, fmt.Sprintf("manual scaling of namespace %q recorded", namespace))
+}
+
+// handleScaleWorkload forces a single workload up or down for a duration by annotating the workload.
+func (a *adminAPI) handleScaleWorkload(w http.ResponseWriter, req *http.Request) {
+	namespace := req.PathValue("namespace")
+	resourceType := strings.ToLower(req.PathValue("resourceType"))
+	name := req.PathValue("name")
+
+	if !a.isNamespaceIncluded(namespace) {
+		writeAdminAPIResponse(w, http.StatusNotFound, fmt.Sprintf("namespace %q is not included in the downscaler", namespace))
+		return
+	}
+
+	if !slices.ContainsFunc(a.config.IncludeResources, func(r string) bool { return strings.EqualFold(r, resourceType) }) {
+		writeAdminAPIResponse(w, http.StatusNotFound, fmt.Sprintf("resource type %q is not included in the downscaler", resourceType))
+		return
+	}
+
+	annotations, err := getManualScalingAnnotationsFromQuery(req)
+	if err != nil {
+		writeAdminAPIResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	err = a.client.AnnotateWorkload(resourceType, namespace, name, annotations, ctx)
+	if err != nil {
+		var notFoundErr *kubernetes.WorkloadNotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeAdminAPIResponse(w, http.StatusNotFound, notFoundErr.Error())
+			return
+		}
+
+		slog.Error("failed to record manual scaling on workload", "error", err, "workload", name, "namespace", namespace)
+		writeAdminAPIResponse(w, http.StatusInternalServerError, "failed to record manual scaling on workload")
+
+		return
+	}
+
+	slog.Info("admin api requested manual scaling of a workload", "workload", name, "namespace", namespace, "annotations", annotations)
+	a.trigger.requestScan(namespace)
+
+	writeAdminAPIResponse(w, http.StatusAccepted, fmt.Sprintf("manual scaling of %s %q recorded", resourceType, name))
+}
+
+// isNamespaceIncluded checks if the namespace is scanned by the downscaler.
+func (a *adminAPI) isNamespaceIncluded(namespace string) bool {
+	if a.config.IncludeNamespaces != nil && !slices.Contains(a.config.IncludeNamespaces, namespace) {
+		return false
+	}
+
+	return !a.config.ExcludeNamespaces.CheckMatchesAny(namespace)
+}
+
+// getManualScalingAnnotationsFromQuery gets the manual scaling annotations from the direction and duration query parameters.
+func getManualScalingAnnotationsFromQuery(req *http.Request) (map[string]string, error) {
+	var scaling values.Scaling
+
+	switch direction := req.URL.Query().Get("direction"); direction {
+	case "up":
+		scaling = values.ScalingUp
+	case "down":
+		scaling = values.ScalingDown
+	default:
+		return nil, newInvalidQueryParameterError("direction", "has to be either up or down", direction)
+	}
+
+	duration, err := getDurationFromQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return values.GetManualScalingAnnotations(scaling, time.Now().Add(duration)), nil
+}
+
+// getDurationFromQuery gets the positive duration from the duration query parameter.
+func getDurationFromQuery(req *http.Request) (time.Duration, error) {
+	durationString := req.URL.Query().Get("duration")
+
+	duration, err := time.ParseDuration(durationString)
+	if err != nil || duration <= 0 {
+		return 0, newInvalidQueryParameterError("duration", "has to be a positive duration, e.g. 1h30m", durationString)
+	}
+
+	return duration, nil
+}
+
+// writeAdminAPIResponse writes the message as a json response with the status code.
+func writeAdminAPIResponse(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	err := json.NewEncoder(w).Encode(adminAPIResponse{Message: message})
+	if err != nil {
+		slog.Error("failed to write admin api response", "error", err)
+	}
+}
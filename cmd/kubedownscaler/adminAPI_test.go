@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *MockClient) AnnotateNamespace(namespace string, annotations map[string]string, ctx context.Context) error {
+	args := m.Called(namespace, annotations, ctx)
+	return args.Error(0)
+}
+
+func (m *MockClient) AnnotateWorkload(resourceType, namespace, name string, annotations map[string]string, ctx context.Context) error {
+	args := m.Called(resourceType, namespace, name, annotations, ctx)
+	return args.Error(0)
+}
+
+func (m *MockClient) AcknowledgeMassDownscaling(until time.Time, ctx context.Context) error {
+	args := m.Called(until, ctx)
+	return args.Error(0)
+}
+
+func TestAdminAPI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		setupMock      func(mockClient *MockClient)
+		notScanning    bool
+		wantStatusCode int
+		wantRequested  []string
+	}{
+		{
+			name:           "rejects requests without a valid token",
+			path:           "/api/v1/scan",
+			token:          "wrong-token",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "requests a full scan",
+			path:           "/api/v1/scan",
+			token:          "test-token",
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  nil,
+		},
+		{
+			name:           "rejects scans on instances which aren't scanning",
+			path:           "/api/v1/scan",
+			token:          "test-token",
+			notScanning:    true,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "rejects namespace scans on instances which aren't scanning",
+			path:           "/api/v1/namespaces/test-namespace/scan",
+			token:          "test-token",
+			notScanning:    true,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "requests a namespace scan",
+			path:           "/api/v1/namespaces/test-namespace/scan",
+			token:          "test-token",
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  []string{"test-namespace"},
+		},
+		{
+			name:           "rejects excluded namespaces",
+			path:           "/api/v1/namespaces/kube-system/scan",
+			token:          "test-token",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "rejects invalid directions",
+			path:           "/api/v1/namespaces/test-namespace/scale?direction=sideways&duration=1h",
+			token:          "test-token",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "records manual scaling on the namespace",
+			path:  "/api/v1/namespaces/test-namespace/scale?direction=up&duration=1h",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("AnnotateNamespace", "test-namespace", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  []string{"test-namespace"},
+		},
+		{
+			name:  "records manual scaling on the workload",
+			path:  "/api/v1/namespaces/test-namespace/Deployments/test-deployment/scale?direction=down&duration=30m",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("AnnotateWorkload", "deployments", "test-namespace", "test-deployment", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  []string{"test-namespace"},
+		},
+		{
+			name:  "records the acknowledgement on the downscaler's namespace",
+			path:  "/api/v1/acknowledge?duration=1h",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("AcknowledgeMassDownscaling", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  nil,
+		},
+		{
+			name:  "fails if the acknowledgement can't be recorded",
+			path:  "/api/v1/acknowledge?duration=1h",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("AcknowledgeMassDownscaling", mock.Anything, mock.Anything).Return(errors.New("forbidden"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "records the acknowledgement on the namespace",
+			path:  "/api/v1/namespaces/test-namespace/acknowledge?duration=1h",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("AnnotateNamespace", "test-namespace", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  []string{"test-namespace"},
+		},
+		{
+			name:           "rejects acknowledgements without a duration",
+			path:           "/api/v1/acknowledge",
+			token:          "test-token",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "pauses the downscaler",
+			path:  "/api/v1/pause?mode=upscale-all",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("SetPauseMode", values.PauseModeUpscaleAll, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  nil,
+		},
+		{
+			name:           "rejects invalid pause modes",
+			path:           "/api/v1/pause?mode=none",
+			token:          "test-token",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "resumes the downscaler",
+			path:  "/api/v1/resume",
+			token: "test-token",
+			setupMock: func(mockClient *MockClient) {
+				mockClient.On("SetPauseMode", values.PauseModeNone, mock.Anything).Return(nil)
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantRequested:  nil,
+		},
+		{
+			name:           "rejects resource types which aren't included",
+			path:           "/api/v1/namespaces/test-namespace/statefulsets/test-statefulset/scale?direction=up&duration=1h",
+			token:          "test-token",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(MockClient)
+			if test.setupMock != nil {
+				test.setupMock(mockClient)
+			}
+
+			config := getDefaultConfig()
+
+			health := newHealthStatus(true, time.Minute)
+			health.setLeader("leader")
+
+			if !test.notScanning {
+				health.startScanning()
+			}
+
+			trigger := newScanTrigger()
+			api := &adminAPI{
+				client:  mockClient,
+				trigger: trigger,
+				health:  health,
+				token:   []byte("test-token"),
+				config:  config,
+			}
+
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+test.token)
+
+			recorder := httptest.NewRecorder()
+			api.newHandler().ServeHTTP(recorder, req)
+
+			require.Equal(t, test.wantStatusCode, recorder.Code)
+			mockClient.AssertExpectations(t)
+
+			if test.wantStatusCode != http.StatusAccepted {
+				require.Empty(t, trigger.notified())
+				return
+			}
+
+			namespaces, isFullScan := trigger.take(config.IncludeNamespaces)
+			require.Equal(t, test.wantRequested == nil, isFullScan)
+			require.Equal(t, test.wantRequested, namespaces)
+		})
+	}
+}
+
+func TestScanTrigger(t *testing.T) {
+	t.Parallel()
+
+	trigger := newScanTrigger()
+	trigger.requestScan("ns2")
+	trigger.requestScan("ns1")
+	trigger.requestScan("ns2")
+
+	require.Len(t, trigger.notified(), 1)
+
+	namespaces, isFullScan := trigger.take(nil)
+	require.False(t, isFullScan)
+	require.Equal(t, []string{"ns1", "ns2"}, namespaces)
+
+	trigger.requestScan("ns1")
+	trigger.requestScan("")
+
+	namespaces, isFullScan = trigger.take([]string{"ns1", "ns3"})
+	require.True(t, isFullScan)
+	require.Equal(t, []string{"ns1", "ns3"}, namespaces)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/audit"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// scanAuditor records the scaling decisions of a scan cycle in the audit sink.
+// A nil scanAuditor doesn't record anything.
+type scanAuditor struct {
+	sink    audit.Sink
+	cycleID string
+	dryRun  bool
+}
+
+// newScanAuditor creates a new scanAuditor with a new cycle id.
+func newScanAuditor(sink audit.Sink, dryRun bool) *scanAuditor {
+	return &scanAuditor{
+		sink:    sink,
+		cycleID: string(uuid.NewUUID()),
+		dryRun:  dryRun,
+	}
+}
+
+// auditedState is the state of a workload which is compared to find out if a scaling changed the workload.
+type auditedState struct {
+	replicas   string
+	scaledDown bool
+}
+
+// getAuditedState gets the audited state of the workload.
+func getAuditedState(workload scalable.Workload) auditedState {
+	state := auditedState{scaledDown: scalable.IsScaledDown(workload)}
+
+	if replicas, ok := scalable.GetReplicas(workload); ok {
+		state.replicas = replicas.String()
+	}
+
+	return state
+}
+
+// getDecidingScope gets the name of the scope deciding the scaling.
+// Workloads scaled up while the downscaler is paused aren't scaled by their scopes, so they don't have a deciding scope.
+func getDecidingScope(scopes values.Scopes) string {
+	if scopes[values.ScopeWorkload] == nil {
+		return ""
+	}
+
+	scopeID, found := scopes.GetScalingScope()
+	if !found {
+		return ""
+	}
+
+	return scopeID.Name()
+}
+
+// record writes a record of the scaling to the audit sink if the scaling changed the workload.
+// Failing to write the record doesn't fail the scaling, as the workload was already scaled.
+func (s *scanAuditor) record(
+	workload scalable.Workload,
+	action audit.Action,
+	before auditedState,
+	scopes values.Scopes,
+	ctx context.Context,
+) {
+	if s == nil {
+		return
+	}
+
+	after := getAuditedState(workload)
+	if after == before {
+		return
+	}
+
+	err := s.sink.Write(audit.Record{
+		Time:        time.Now(),
+		CycleID:     s.cycleID,
+		Source:      audit.SourceScan,
+		Kind:        workload.GroupVersionKind().Kind,
+		Namespace:   workload.GetNamespace(),
+		Name:        workload.GetName(),
+		Action:      action,
+		OldReplicas: before.replicas,
+		NewReplicas: after.replicas,
+		Scope:       getDecidingScope(scopes),
+		DryRun:      s.dryRun,
+	}, ctx)
+	if err != nil {
+		slog.Error("failed to write audit record", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+	}
+}
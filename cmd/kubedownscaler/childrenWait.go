@@ -0,0 +1,51 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// childrenWait keeps track of since when workloads are waiting for their children to be scaled up before them.
+// Workloads are scaled up anyway once they waited longer than the timeout, so failing children can't keep them scaled down.
+type childrenWait struct {
+	mutex   sync.Mutex
+	since   map[types.UID]time.Time
+	timeout time.Duration
+}
+
+// newChildrenWait creates a new childrenWait.
+func newChildrenWait(timeout time.Duration) *childrenWait {
+	return &childrenWait{
+		since:   make(map[types.UID]time.Time),
+		timeout: timeout,
+	}
+}
+
+// hasTimedOut records that the workload is waiting for its children and returns if it has waited longer than the timeout.
+func (c *childrenWait) hasTimedOut(workload scalable.Workload) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	since, exists := c.since[workload.GetUID()]
+	if !exists {
+		c.since[workload.GetUID()] = time.Now()
+		return false
+	}
+
+	return time.Since(since) > c.timeout
+}
+
+// finish stops tracking the wait of the workload.
+func (c *childrenWait) finish(workload scalable.Workload) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.since, workload.GetUID())
+}
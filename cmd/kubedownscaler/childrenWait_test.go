@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildrenWait(t *testing.T) {
+	t.Parallel()
+
+	workload, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"test-deployment","namespace":"test-namespace","uid":"test-uid"}}`,
+	))
+	require.NoError(t, err)
+
+	waits := newChildrenWait(time.Minute)
+
+	require.False(t, waits.hasTimedOut(workload), "the first wait mustn't time out")
+	require.False(t, waits.hasTimedOut(workload), "the wait mustn't time out before the timeout passed")
+
+	waits.since[workload.GetUID()] = time.Now().Add(-2 * time.Minute)
+	require.True(t, waits.hasTimedOut(workload), "the wait should time out after the timeout passed")
+
+	waits.finish(workload)
+	require.False(t, waits.hasTimedOut(workload), "a finished wait should start over")
+}
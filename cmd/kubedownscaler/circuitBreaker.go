@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// circuitBreakerThreshold is the amount of workloads allowed to be scaled down at once, either absolute or as a percentage.
+type circuitBreakerThreshold struct {
+	enabled    bool
+	value      int
+	percentage bool
+}
+
+func (c *circuitBreakerThreshold) Set(value string) error {
+	numberString, isPercentage := strings.CutSuffix(value, "%")
+
+	number, err := strconv.Atoi(numberString)
+	if err != nil || number <= 0 || (isPercentage && number > 100) {
+		return newInvalidCircuitBreakerThresholdError(value)
+	}
+
+	*c = circuitBreakerThreshold{enabled: true, value: number, percentage: isPercentage}
+
+	return nil
+}
+
+func (c *circuitBreakerThreshold) String() string {
+	if !c.enabled {
+		return "disabled"
+	}
+
+	if c.percentage {
+		return fmt.Sprintf("%d%%", c.value)
+	}
+
+	return strconv.Itoa(c.value)
+}
+
+// isExceeded checks if scaling down the amount of workloads out of the total workloads exceeds the threshold.
+func (c *circuitBreakerThreshold) isExceeded(amount, total int) bool {
+	if !c.enabled || amount == 0 {
+		return false
+	}
+
+	if c.percentage {
+		return amount*100 > c.value*total
+	}
+
+	return amount > c.value
+}
+
+// circuitBreaker holds back scan cycles which would unexpectedly scale down too many workloads at once, e.g. due to a typo.
+type circuitBreaker struct {
+	threshold      circuitBreakerThreshold
+	scheduleWindow time.Duration
+}
+
+// newCircuitBreaker creates a new circuitBreaker from the runtime configuration.
+func newCircuitBreaker(config *runtimeConfiguration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold:      config.CircuitBreakerThreshold,
+		scheduleWindow: config.CircuitBreakerScheduleWindow,
+	}
+}
+
+// isAcknowledged checks if mass downscaling of all workloads is currently acknowledged on the downscaler's namespace.
+// If the acknowledgement can't be fetched it is treated as missing, so the circuit breaker keeps protecting the workloads.
+func isAcknowledged(client kubernetes.Client, ctx context.Context) bool {
+	acknowledgedUntil, err := client.GetMassDownscalingAcknowledgement(ctx)
+	if err != nil {
+		slog.Error("failed to get acknowledgement of mass downscaling, treating it as not acknowledged", "error", err)
+		return false
+	}
+
+	return acknowledgedUntil.After(time.Now())
+}
+
+// check checks if the scan cycle has to be aborted, because too many of the workloads would unexpectedly be scaled down at once.
+// When the circuit breaker trips an event is added to the namespaces of the held back workloads.
+func (c *circuitBreaker) check(
+	workloads []scalable.Workload,
+	client kubernetes.Client,
+	ctx context.Context,
+	scopeDefault, scopeCli, scopeEnv *values.Scope,
+	namespaceScopes map[string]*values.Scope,
+	config *runtimeConfiguration,
+) bool {
+	if !c.threshold.enabled {
+		return false
+	}
+
+	if isAcknowledged(client, ctx) {
+		slog.Debug("mass downscaling is acknowledged, skipping circuit breaker")
+		return false
+	}
+
+	unexpectedDownscales, downscalable := getUnexpectedDownscales(
+		workloads,
+		scopeDefault, scopeCli, scopeEnv,
+		namespaceScopes,
+		c.scheduleWindow,
+		config,
+		ctx,
+	)
+	if !c.threshold.isExceeded(len(unexpectedDownscales), downscalable) {
+		return false
+	}
+
+	slog.Error(
+		"circuit breaker tripped, aborting scan cycle",
+		"pendingDownscales", len(unexpectedDownscales),
+		"workloads", downscalable,
+		"threshold", c.threshold.String(),
+	)
+
+	namespaceDownscales := make(map[string]int)
+	for _, workload := range unexpectedDownscales {
+		namespaceDownscales[workload.GetNamespace()]++
+	}
+
+	for _, namespace := range slices.Sorted(maps.Keys(namespaceDownscales)) {
+		kubernetes.NewResourceLoggerForNamespace(client, namespace).ErrorCircuitBreakerTripped(
+			fmt.Sprintf(
+				"scan cycle aborted: %d of %d running workloads would have been scaled down at once outside of a schedule boundary, "+
+					"%d of them in this namespace, exceeding the threshold of %s. "+
+					"Acknowledge the downscaling through the admin api or an annotation if it is intended",
+				len(unexpectedDownscales), downscalable, namespaceDownscales[namespace], c.threshold.String(),
+			),
+			ctx,
+		)
+	}
+
+	return true
+}
+
+// getUnexpectedDownscales gets the workloads which would be scaled down in this scan cycle,
+// without a schedule boundary within the window or an acknowledgement explaining it.
+// Manually requested and forced scalings are intended and therefore never unexpected.
+// It also returns the amount of workloads which aren't scaled down yet, which the threshold is relative to.
+// Workloads which are already scaled down, including the ones held back by a manual override, can't be scaled down at once.
+func getUnexpectedDownscales(
+	workloads []scalable.Workload,
+	scopeDefault, scopeCli, scopeEnv *values.Scope,
+	namespaceScopes map[string]*values.Scope,
+	scheduleWindow time.Duration,
+	config *runtimeConfiguration,
+	ctx context.Context,
+) ([]scalable.Workload, int) {
+	var unexpectedDownscales []scalable.Workload
+
+	downscalable := 0
+
+	for _, workload := range workloads {
+		if scalable.IsScaledDown(workload) {
+			continue
+		}
+
+		downscalable++
+
+		// invalid annotations are reported when the workload is scanned
+		scopes, err := getWorkloadScopes(workload, discardResourceLogger{}, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes)
+		if err != nil {
+			continue
+		}
+
+		isInGracePeriod, err := scopes.IsInGracePeriod(
+			config.TimeAnnotation,
+			workload.GetAnnotations(),
+			workload.GetCreationTimestamp().Time,
+			discardResourceLogger{},
+			ctx,
+		)
+		if err != nil || isInGracePeriod {
+			continue
+		}
+
+		excluded := scopes.GetExcluded(scopes)
+		upscaleOnExclusion := scopes.GetUpscaleExcluded()
+
+		if excluded || getCurrentScaling(workload, excluded, upscaleOnExclusion, &scopes) != values.ScalingDown {
+			continue
+		}
+
+		if scopes.IsScalingForced() || scopes.IsMassDownscalingAcknowledged() || scopes.HasScalingChangedWithin(scheduleWindow) {
+			continue
+		}
+
+		// workloads held back by the minimum state duration aren't scaled down in this scan cycle
+		inMinStateDuration, err := isInMinStateDuration(workload, values.ScalingDown, scopes)
+		if err != nil || inMinStateDuration {
+			continue
+		}
+
+		unexpectedDownscales = append(unexpectedDownscales, workload)
+	}
+
+	return unexpectedDownscales, downscalable
+}
+
+// discardResourceLogger is a resource logger which doesn't add any events.
+type discardResourceLogger struct{}
+
+func (discardResourceLogger) ErrorInvalidAnnotation(string, string, context.Context) {}
+
+func (discardResourceLogger) ErrorIncompatibleFields(string, context.Context) {}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *MockClient) GetMassDownscalingAcknowledgement(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func TestCircuitBreakerThreshold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		threshold    string
+		amount       int
+		total        int
+		wantErr      bool
+		wantExceeded bool
+	}{
+		{
+			name:         "absolute threshold not exceeded",
+			threshold:    "5",
+			amount:       5,
+			total:        100,
+			wantExceeded: false,
+		},
+		{
+			name:         "absolute threshold exceeded",
+			threshold:    "5",
+			amount:       6,
+			total:        100,
+			wantExceeded: true,
+		},
+		{
+			name:         "percentage threshold not exceeded",
+			threshold:    "30%",
+			amount:       3,
+			total:        10,
+			wantExceeded: false,
+		},
+		{
+			name:         "percentage threshold exceeded",
+			threshold:    "30%",
+			amount:       4,
+			total:        10,
+			wantExceeded: true,
+		},
+		{
+			name:      "percentage above 100",
+			threshold: "120%",
+			wantErr:   true,
+		},
+		{
+			name:      "negative threshold",
+			threshold: "-3",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var threshold circuitBreakerThreshold
+
+			err := threshold.Set(test.threshold)
+			if test.wantErr {
+				var invalidThresholdErr *InvalidCircuitBreakerThresholdError
+				require.ErrorAs(t, err, &invalidThresholdErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.threshold, threshold.String())
+			require.Equal(t, test.wantExceeded, threshold.isExceeded(test.amount, test.total))
+		})
+	}
+}
+
+func TestCircuitBreakerThreshold_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var threshold circuitBreakerThreshold
+
+	require.False(t, threshold.isExceeded(100, 100))
+}
+
+func TestGetUnexpectedDownscales(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	acknowledgedUntil := now.Add(time.Hour)
+	recentTransition := now.Add(-5 * time.Minute).UTC().Format(time.RFC3339)
+	recentDowntime := fmt.Sprintf("%s - %s", now.Add(-5*time.Minute).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339))
+
+	tests := []struct {
+		name             string
+		annotations      string
+		namespaceScope   *values.Scope
+		wantUnexpected   bool
+		wantDownscalable int
+	}{
+		{
+			name:             "downscaling without a schedule boundary",
+			annotations:      `"downscaler/downtime":"always"`,
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   true,
+			wantDownscalable: 1,
+		},
+		{
+			name:             "downscaling right after a schedule boundary",
+			annotations:      fmt.Sprintf(`"downscaler/downtime":%q`, recentDowntime),
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 1,
+		},
+		{
+			name:             "already scaled down",
+			annotations:      `"downscaler/downtime":"always","downscaler/original-replicas":"3"`,
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 0,
+		},
+		{
+			name:             "forced downscaling",
+			annotations:      `"downscaler/force-downtime":"true"`,
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 1,
+		},
+		{
+			name:             "acknowledged on the namespace",
+			annotations:      `"downscaler/downtime":"always"`,
+			namespaceScope:   &values.Scope{AcknowledgedUntil: &acknowledgedUntil},
+			wantUnexpected:   false,
+			wantDownscalable: 1,
+		},
+		{
+			name: "held back by the minimum state duration",
+			annotations: `"downscaler/downtime":"always","downscaler/min-state-duration":"1h",` +
+				fmt.Sprintf(`"downscaler/last-transition":%q`, recentTransition),
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 1,
+		},
+		{
+			name: "held back by a manual override",
+			annotations: `"downscaler/downtime":"always","downscaler/manual-override-duration":"1h",` +
+				`"downscaler/original-replicas":"3","downscaler/applied-replicas":"0",` +
+				fmt.Sprintf(`"downscaler/manual-override-since":%q`, recentTransition),
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 0,
+		},
+		{
+			name:             "upscaling",
+			annotations:      `"downscaler/uptime":"always"`,
+			namespaceScope:   values.NewScope(),
+			wantUnexpected:   false,
+			wantDownscalable: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload, err := scalable.ParseWorkloadFromRawObject("deployment", fmt.Appendf(nil,
+				`{"metadata":{"name":"test","namespace":"default","creationTimestamp":%q,"annotations":{%s}},"spec":{"replicas":3}}`,
+				now.Add(-24*time.Hour).Format(time.RFC3339), test.annotations,
+			))
+			require.NoError(t, err)
+
+			unexpectedDownscales, downscalable := getUnexpectedDownscales(
+				[]scalable.Workload{workload},
+				values.GetDefaultScope(), values.NewScope(), values.NewScope(),
+				map[string]*values.Scope{"default": test.namespaceScope},
+				15*time.Minute,
+				getDefaultConfig(),
+				t.Context(),
+			)
+
+			require.Equal(t, test.wantUnexpected, len(unexpectedDownscales) == 1)
+			require.Equal(t, test.wantDownscalable, downscalable)
+		})
+	}
+}
+
+func TestCircuitBreakerCheck_Acknowledged(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	config := getDefaultConfig()
+	require.NoError(t, config.CircuitBreakerThreshold.Set("1"))
+
+	workloads := make([]scalable.Workload, 0, 2)
+
+	for _, name := range []string{"first", "second"} {
+		workload, err := scalable.ParseWorkloadFromRawObject("deployment", fmt.Appendf(nil,
+			`{"metadata":{"name":%q,"namespace":"default","creationTimestamp":%q,"annotations":{"downscaler/downtime":"always"}},`+
+				`"spec":{"replicas":3}}`,
+			name, now.Add(-24*time.Hour).Format(time.RFC3339),
+		))
+		require.NoError(t, err)
+
+		workloads = append(workloads, workload)
+	}
+
+	// the acknowledgement is read from the downscaler's namespace, so it is shared by all replicas and survives restarts
+	mockClient := new(MockClient)
+	mockClient.On("GetMassDownscalingAcknowledgement", mock.Anything).Return(now.Add(time.Hour), nil)
+
+	tripped := newCircuitBreaker(config).check(
+		workloads,
+		mockClient,
+		t.Context(),
+		values.GetDefaultScope(), values.NewScope(), values.NewScope(),
+		map[string]*values.Scope{"default": values.NewScope()},
+		config,
+	)
+
+	require.False(t, tripped)
+	mockClient.AssertExpectations(t)
+}
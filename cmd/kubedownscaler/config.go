@@ -6,6 +6,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 )
@@ -21,13 +23,51 @@ type runtimeConfiguration struct {
 	Interval time.Duration
 	// MaxRetriesOnConflict sets the maximum number of retries on 409 errors.
 	MaxRetriesOnConflict int
+	// ShutdownTimeout sets how long to wait for in-flight scaling operations on shutdown.
+	ShutdownTimeout time.Duration
+	// ResourceDiscoveryInterval sets how often to re-check which of the included resource types are served by the cluster.
+	ResourceDiscoveryInterval time.Duration
+	// MaxNamespaceBackoff sets the maximum time a repeatedly failing namespace is skipped for.
+	MaxNamespaceBackoff time.Duration
+	// DependencyReadyTimeout sets how long dependents wait for an upscaled workload to become ready.
+	DependencyReadyTimeout time.Duration
+	// EventVerbosity sets which normal events are added to workloads.
+	EventVerbosity eventVerbosity
+	// StatusAnnotation sets if the status of scanned workloads is kept in an annotation on them.
+	StatusAnnotation bool
+	// UpscaleVerificationTimeout sets how long upscaled workloads are watched for becoming ready. Zero disables the verification.
+	UpscaleVerificationTimeout time.Duration
+	// UpscaleVerificationRetries sets how often an upscaled workload which didn't become ready is watched again before failing.
+	UpscaleVerificationRetries int
+	// LivenessIntervalMultiplier sets after how many intervals without a finished scan cycle the liveness check fails.
+	LivenessIntervalMultiplier int
+	// StateStore sets where the original replicas of scaled down workloads are kept.
+	StateStore string
+	// CircuitBreakerThreshold sets how many workloads may unexpectedly be scaled down at once before a scan cycle is aborted.
+	CircuitBreakerThreshold circuitBreakerThreshold
+	// CircuitBreakerScheduleWindow sets how long after a schedule boundary downscaling is expected by the circuit breaker.
+	CircuitBreakerScheduleWindow time.Duration
+	// RedirectToHPA sets if workloads controlled by a HorizontalPodAutoscaler are scaled through it instead of being excluded.
+	RedirectToHPA bool
+	// AdminAPI sets if the admin api for manual scans and scaling should be served.
+	AdminAPI bool
+	// AdminAPITokenFile sets the file containing the bearer token required by the admin api.
+	AdminAPITokenFile string
 }
 
 func getDefaultConfig() *runtimeConfiguration {
 	return &runtimeConfiguration{
-		CommonRuntimeConfiguration: *util.GetDefaultConfig(),
-		Once:                       false,
-		Interval:                   30 * time.Second,
+		CommonRuntimeConfiguration:   *util.GetDefaultConfig(),
+		Once:                         false,
+		Interval:                     30 * time.Second,
+		ShutdownTimeout:              20 * time.Second,
+		ResourceDiscoveryInterval:    5 * time.Minute,
+		MaxNamespaceBackoff:          10 * time.Minute,
+		DependencyReadyTimeout:       5 * time.Minute,
+		LivenessIntervalMultiplier:   10,
+		StateStore:                   kubernetes.StateStoreAnnotation,
+		CircuitBreakerScheduleWindow: 15 * time.Minute,
+		EventVerbosity:               eventVerbosityErrors,
 	}
 }
 
@@ -57,6 +97,88 @@ func (c *runtimeConfiguration) parseConfigFlags() {
 		0,
 		"maximum number of retries on 409 conflict errors (default: 0)",
 	)
+	flag.Var(
+		(*util.DurationValue)(&c.ShutdownTimeout),
+		"shutdown-timeout",
+		"maximum time to wait for in-flight scaling operations to finish on shutdown (default: 20s)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&c.ResourceDiscoveryInterval),
+		"resource-discovery-interval",
+		"time between checks for which of the included resource types are served by the cluster (default: 5m)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&c.MaxNamespaceBackoff),
+		"max-namespace-backoff",
+		"maximum time a repeatedly failing namespace is skipped for (default: 10m)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&c.DependencyReadyTimeout),
+		"dependency-ready-timeout",
+		"maximum time dependents wait for an upscaled workload to become ready (default: 5m)",
+	)
+	flag.BoolVar(
+		&c.StatusAnnotation,
+		"status-annotation",
+		false,
+		"keep the status of scanned workloads in the downscaler/status annotation on them (default: false)",
+	)
+	flag.Var(
+		&c.EventVerbosity,
+		"event-verbosity",
+		"which normal events are added to workloads: errors, scaling or all (default: errors)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&c.UpscaleVerificationTimeout),
+		"upscale-verification-timeout",
+		"maximum time to watch an upscaled workload for becoming ready before reporting the upscale as failed (default: disabled)",
+	)
+	flag.IntVar(
+		&c.UpscaleVerificationRetries,
+		"upscale-verification-retries",
+		0,
+		"number of times an upscaled workload which didn't become ready is watched again before the upscale is reported as failed (default: 0)",
+	)
+	flag.IntVar(
+		&c.LivenessIntervalMultiplier,
+		"liveness-interval-multiplier",
+		10,
+		"number of intervals without a finished scan cycle after which the liveness check fails (default: 10)",
+	)
+	flag.StringVar(
+		&c.StateStore,
+		"state-store",
+		kubernetes.StateStoreAnnotation,
+		"where to keep the original replicas of scaled down workloads: annotation, configmap or crd (default: annotation)",
+	)
+	flag.Var(
+		&c.CircuitBreakerThreshold,
+		"circuit-breaker-threshold",
+		"number or percentage of workloads which may unexpectedly be scaled down at once before a scan cycle is aborted (default: disabled)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&c.CircuitBreakerScheduleWindow),
+		"circuit-breaker-schedule-window",
+		"time after a schedule boundary in which downscaling is expected and not held back by the circuit breaker (default: 15m)",
+	)
+	flag.BoolVar(
+		&c.RedirectToHPA,
+		"redirect-to-hpa",
+		false,
+		"scale workloads controlled by a horizontalpodautoscaler through its min and max replicas instead of excluding them (default: false)",
+	)
+	flag.BoolVar(
+		&c.AdminAPI,
+		"admin-api",
+		false,
+		"serve the admin api for manual scans and scaling on port 8082 (default: false)",
+	)
+	flag.StringVar(
+		&c.AdminAPITokenFile,
+		"admin-api-token-file",
+		"",
+		"file containing the bearer token required by the admin api (required when the admin api is enabled)",
+	)
 }
 
 //nolint:nonamedreturns //required for function clarity
@@ -72,6 +194,16 @@ func initComponent() (config *runtimeConfiguration, scopeDefault, scopeCli, scop
 
 	scopeDefault, scopeCli, scopeEnv = values.InitScopes()
 
+	if config.AdminAPI && config.AdminAPITokenFile == "" {
+		slog.Error("the admin api requires a token file to be set")
+		os.Exit(1)
+	}
+
+	if config.LivenessIntervalMultiplier <= 0 {
+		slog.Error("the liveness interval multiplier has to be greater than zero")
+		os.Exit(1)
+	}
+
 	if config.JsonLogs {
 		opts := &slog.HandlerOptions{
 			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
@@ -96,6 +228,13 @@ func initComponent() (config *runtimeConfiguration, scopeDefault, scopeCli, scop
 		os.Exit(1)
 	}
 
+	if config.CustomResourcesConfig != "" {
+		if err = scalable.LoadCustomResources(config.CustomResourcesConfig); err != nil {
+			slog.Error("failed to load custom resources", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	slog.Debug(
 		"finished getting startup config",
 		"envScope", scopeEnv,
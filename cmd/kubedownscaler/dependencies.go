@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+const (
+	// annotationDependsOn references the workloads in the same namespace a workload depends on, e.g. "statefulset/redis,postgresql/db".
+	annotationDependsOn = "downscaler/depends-on"
+	// dependencyReadyPollInterval is the time between readiness checks of a workload other workloads depend on.
+	dependencyReadyPollInterval = 5 * time.Second
+)
+
+// dependencyNode is a workload in the dependency graph.
+// Its scaling is decided before it waits on other nodes, so nodes only wait on nodes scaling in the same direction.
+type dependencyNode struct {
+	workload     scalable.Workload
+	dependencies []*dependencyNode
+	dependents   []*dependencyNode
+	scaling      values.Scaling
+	decideOnce   sync.Once
+	decided      chan struct{}
+	finishOnce   sync.Once
+	done         chan struct{}
+	// heartbeat is called while the node waits for its workload to become ready, so the wait isn't mistaken for a stuck scan loop
+	heartbeat func()
+}
+
+// dependencyGraph holds the dependencies between the workloads of a scan cycle.
+type dependencyGraph struct {
+	nodes map[scalable.Workload]*dependencyNode
+}
+
+// getDependencyKey gets the key a workload can be referenced by in the depends-on annotation.
+func getDependencyKey(namespace, kind, name string) string {
+	return namespace + "/" + strings.ToLower(kind) + "/" + name
+}
+
+// getWorkloadDependencyKey gets the key the workload can be referenced by in the depends-on annotation.
+func getWorkloadDependencyKey(workload scalable.Workload) string {
+	return getDependencyKey(workload.GetNamespace(), workload.GroupVersionKind().Kind, workload.GetName())
+}
+
+// newDependencyGraph builds the dependency graph from the depends-on annotations of the workloads.
+// References to workloads which aren't scanned are ignored and dependency cycles are broken up.
+// The heartbeat is called periodically while a workload is waited on to become ready.
+func newDependencyGraph(workloads []scalable.Workload, heartbeat func()) *dependencyGraph {
+	graph := &dependencyGraph{nodes: make(map[scalable.Workload]*dependencyNode, len(workloads))}
+	nodesByKey := make(map[string]*dependencyNode, len(workloads))
+
+	for _, workload := range workloads {
+		node := &dependencyNode{
+			workload:  workload,
+			decided:   make(chan struct{}),
+			done:      make(chan struct{}),
+			heartbeat: heartbeat,
+		}
+		graph.nodes[workload] = node
+		nodesByKey[getWorkloadDependencyKey(workload)] = node
+	}
+
+	for _, node := range graph.nodes {
+		dependsOn, ok := node.workload.GetAnnotations()[annotationDependsOn]
+		if !ok {
+			continue
+		}
+
+		for reference := range strings.SplitSeq(dependsOn, ",") {
+			kind, name, found := strings.Cut(strings.TrimSpace(reference), "/")
+			if !found || kind == "" || name == "" {
+				slog.Warn("invalid dependency reference, expected kind/name",
+					"reference", reference,
+					"workload", node.workload.GetName(),
+					"namespace", node.workload.GetNamespace(),
+				)
+
+				continue
+			}
+
+			dependency, exists := nodesByKey[getDependencyKey(node.workload.GetNamespace(), kind, name)]
+			if !exists {
+				slog.Warn("dependency is not scanned by the downscaler, ignoring it",
+					"reference", reference,
+					"workload", node.workload.GetName(),
+					"namespace", node.workload.GetNamespace(),
+				)
+
+				continue
+			}
+
+			node.dependencies = append(node.dependencies, dependency)
+			dependency.dependents = append(dependency.dependents, node)
+		}
+	}
+
+	graph.breakCycles()
+
+	return graph
+}
+
+// breakCycles removes all dependencies of workloads which are part of a dependency cycle, so they don't wait on each other forever.
+func (g *dependencyGraph) breakCycles() {
+	// nodes which can't be sorted topologically in either direction are part of a cycle
+	cyclic := g.getUnsortable(func(node *dependencyNode) []*dependencyNode { return node.dependencies })
+	reverseCyclic := g.getUnsortable(func(node *dependencyNode) []*dependencyNode { return node.dependents })
+
+	var cycleMembers []string
+
+	for node := range cyclic {
+		if _, ok := reverseCyclic[node]; !ok {
+			continue
+		}
+
+		cycleMembers = append(cycleMembers, getWorkloadDependencyKey(node.workload))
+
+		for _, dependency := range node.dependencies {
+			dependency.dependents = slices.DeleteFunc(dependency.dependents, func(dependent *dependencyNode) bool { return dependent == node })
+		}
+
+		for _, dependent := range node.dependents {
+			dependent.dependencies = slices.DeleteFunc(dependent.dependencies, func(dependency *dependencyNode) bool { return dependency == node })
+		}
+
+		node.dependencies = nil
+		node.dependents = nil
+	}
+
+	if len(cycleMembers) > 0 {
+		slices.Sort(cycleMembers)
+		slog.Error("found dependency cycle, ignoring the dependencies of the affected workloads", "workloads", cycleMembers)
+	}
+}
+
+// getUnsortable gets all nodes which are left over after sorting the graph topologically by the given edges.
+func (g *dependencyGraph) getUnsortable(getEdges func(node *dependencyNode) []*dependencyNode) map[*dependencyNode]struct{} {
+	remainingEdges := make(map[*dependencyNode]int, len(g.nodes))
+	reverseEdges := make(map[*dependencyNode][]*dependencyNode, len(g.nodes))
+
+	var queue []*dependencyNode
+
+	for _, node := range g.nodes {
+		remainingEdges[node] = len(getEdges(node))
+		if remainingEdges[node] == 0 {
+			queue = append(queue, node)
+		}
+
+		for _, target := range getEdges(node) {
+			reverseEdges[target] = append(reverseEdges[target], node)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		delete(remainingEdges, node)
+
+		for _, source := range reverseEdges[node] {
+			remainingEdges[source]--
+			if remainingEdges[source] == 0 {
+				queue = append(queue, source)
+			}
+		}
+	}
+
+	unsortable := make(map[*dependencyNode]struct{}, len(remainingEdges))
+	for node := range remainingEdges {
+		unsortable[node] = struct{}{}
+	}
+
+	return unsortable
+}
+
+// getNode gets the node of the workload. The node is nil if the graph is nil.
+func (g *dependencyGraph) getNode(workload scalable.Workload) *dependencyNode {
+	if g == nil {
+		return nil
+	}
+
+	return g.nodes[workload]
+}
+
+// decide publishes the scaling of the node to the nodes waiting on it. Only the first decision counts.
+func (n *dependencyNode) decide(scaling values.Scaling) {
+	if n == nil {
+		return
+	}
+
+	n.decideOnce.Do(func() {
+		n.scaling = scaling
+		close(n.decided)
+	})
+}
+
+// finish marks the node as done, releasing all nodes waiting on it.
+func (n *dependencyNode) finish() {
+	if n == nil {
+		return
+	}
+
+	n.decide(values.ScalingNone)
+	n.finishOnce.Do(func() { close(n.done) })
+}
+
+// hasDependents checks if any other workload depends on the node.
+func (n *dependencyNode) hasDependents() bool {
+	return n != nil && len(n.dependents) > 0
+}
+
+// waitForTurn decides the scaling of the node and waits until it is allowed to be scaled.
+// Upscaling waits for all dependencies which are upscaled, downscaling waits for all dependents which are downscaled.
+func (n *dependencyNode) waitForTurn(scaling values.Scaling, ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+
+	n.decide(scaling)
+
+	var waitFor []*dependencyNode
+
+	switch scaling {
+	case values.ScalingUp:
+		waitFor = n.dependencies
+	case values.ScalingDown:
+		waitFor = n.dependents
+	default:
+		return nil
+	}
+
+	for _, other := range waitFor {
+		select {
+		case <-other.decided:
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for %q: %w", other.workload.GetName(), ctx.Err())
+		}
+
+		if other.scaling != scaling {
+			continue
+		}
+
+		slog.Debug("waiting for related workload to be scaled first",
+			"relatedWorkload", other.workload.GetName(),
+			"workload", n.workload.GetName(),
+			"namespace", n.workload.GetNamespace(),
+		)
+
+		select {
+		case <-other.done:
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for %q: %w", other.workload.GetName(), ctx.Err())
+		}
+	}
+
+	return nil
+}
+
+// waitUntilReady polls the workload until it reports being ready or the timeout is reached.
+// Workloads which can't report their readiness are considered ready right away.
+// The heartbeat is called on every poll, if it is set.
+func waitUntilReady(
+	client kubernetes.Client,
+	workload scalable.Workload,
+	timeout time.Duration,
+	heartbeat func(),
+	ctx context.Context,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(dependencyReadyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		readyWorkload, ok := workload.(scalable.ReadyWorkload)
+		if !ok || readyWorkload.IsReady() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return newDependencyNotReadyError(workload.GetName(), timeout)
+		case <-ticker.C:
+		}
+
+		if heartbeat != nil {
+			heartbeat()
+		}
+
+		err := client.RegetWorkload(workload, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return newDependencyNotReadyError(workload.GetName(), timeout)
+			}
+
+			return fmt.Errorf("failed to check if workload is ready: %w", err)
+		}
+	}
+}
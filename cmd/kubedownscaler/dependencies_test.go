@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (m *MockWorkload) GroupVersionKind() schema.GroupVersionKind {
+	args := m.Called()
+	return args.Get(0).(schema.GroupVersionKind)
+}
+
+func newDependencyMockWorkload(kind, name, dependsOn string) *MockWorkload {
+	workload := new(MockWorkload)
+	workload.On("GetNamespace").Return("test-namespace")
+	workload.On("GetName").Return(name)
+	workload.On("GroupVersionKind").Return(schema.GroupVersionKind{Kind: kind})
+
+	annotations := map[string]string{}
+	if dependsOn != "" {
+		annotations[annotationDependsOn] = dependsOn
+	}
+
+	workload.On("GetAnnotations").Return(annotations)
+
+	return workload
+}
+
+func TestNewDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	t.Run("links dependencies referenced by kind and name", func(t *testing.T) {
+		t.Parallel()
+
+		database := newDependencyMockWorkload("postgresql", "db", "")
+		cache := newDependencyMockWorkload("StatefulSet", "redis", "")
+		app := newDependencyMockWorkload("Deployment", "app", "postgresql/db, statefulset/redis, deployment/missing, invalid")
+
+		graph := newDependencyGraph([]scalable.Workload{database, cache, app}, nil)
+
+		require.ElementsMatch(t, []*dependencyNode{graph.getNode(database), graph.getNode(cache)}, graph.getNode(app).dependencies)
+		require.Equal(t, []*dependencyNode{graph.getNode(app)}, graph.getNode(database).dependents)
+		require.True(t, graph.getNode(cache).hasDependents())
+		require.False(t, graph.getNode(app).hasDependents())
+	})
+
+	t.Run("breaks up dependency cycles", func(t *testing.T) {
+		t.Parallel()
+
+		first := newDependencyMockWorkload("Deployment", "first", "deployment/second")
+		second := newDependencyMockWorkload("Deployment", "second", "deployment/first")
+		third := newDependencyMockWorkload("Deployment", "third", "deployment/first")
+		database := newDependencyMockWorkload("StatefulSet", "db", "")
+		app := newDependencyMockWorkload("Deployment", "app", "statefulset/db")
+
+		graph := newDependencyGraph([]scalable.Workload{first, second, third, database, app}, nil)
+
+		require.Empty(t, graph.getNode(first).dependencies)
+		require.Empty(t, graph.getNode(first).dependents)
+		require.Empty(t, graph.getNode(second).dependencies)
+		require.Empty(t, graph.getNode(third).dependencies)
+		require.Equal(t, []*dependencyNode{graph.getNode(database)}, graph.getNode(app).dependencies)
+	})
+}
+
+func TestDependencyNodeWaitForTurn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("upscaling waits for upscaled dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		database := newDependencyMockWorkload("StatefulSet", "db", "")
+		app := newDependencyMockWorkload("Deployment", "app", "statefulset/db")
+		graph := newDependencyGraph([]scalable.Workload{database, app}, nil)
+
+		waited := make(chan error)
+
+		go func() {
+			waited <- graph.getNode(app).waitForTurn(values.ScalingUp, t.Context())
+		}()
+
+		require.NoError(t, graph.getNode(database).waitForTurn(values.ScalingUp, t.Context()))
+
+		select {
+		case <-waited:
+			t.Fatal("app was scaled up before its dependency finished")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		graph.getNode(database).finish()
+		require.NoError(t, <-waited)
+	})
+
+	t.Run("downscaling waits for downscaled dependents", func(t *testing.T) {
+		t.Parallel()
+
+		database := newDependencyMockWorkload("StatefulSet", "db", "")
+		app := newDependencyMockWorkload("Deployment", "app", "statefulset/db")
+		graph := newDependencyGraph([]scalable.Workload{database, app}, nil)
+
+		waited := make(chan error)
+
+		go func() {
+			waited <- graph.getNode(database).waitForTurn(values.ScalingDown, t.Context())
+		}()
+
+		require.NoError(t, graph.getNode(app).waitForTurn(values.ScalingDown, t.Context()))
+
+		select {
+		case <-waited:
+			t.Fatal("dependency was scaled down before its dependent finished")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		graph.getNode(app).finish()
+		require.NoError(t, <-waited)
+	})
+
+	t.Run("doesn't wait on workloads scaled in the other direction", func(t *testing.T) {
+		t.Parallel()
+
+		database := newDependencyMockWorkload("StatefulSet", "db", "")
+		app := newDependencyMockWorkload("Deployment", "app", "statefulset/db")
+		graph := newDependencyGraph([]scalable.Workload{database, app}, nil)
+
+		graph.getNode(database).decide(values.ScalingDown)
+
+		require.NoError(t, graph.getNode(app).waitForTurn(values.ScalingUp, t.Context()))
+	})
+
+	t.Run("nil node doesn't wait", func(t *testing.T) {
+		t.Parallel()
+
+		var node *dependencyNode
+
+		require.NoError(t, node.waitForTurn(values.ScalingUp, t.Context()))
+		node.finish()
+	})
+}
@@ -1,6 +1,9 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type NamespaceScopeRetrieveError struct {
 	namespace string
@@ -38,6 +41,33 @@ func (s *ScalingInvalidError) Error() string {
 	return s.message
 }
 
+type DependencyNotReadyError struct {
+	workload string
+	timeout  time.Duration
+}
+
+func newDependencyNotReadyError(workload string, timeout time.Duration) error {
+	return &DependencyNotReadyError{workload: workload, timeout: timeout}
+}
+
+func (d *DependencyNotReadyError) Error() string {
+	return fmt.Sprintf("workload %q did not become ready within %s, scaling up its dependents anyway", d.workload, d.timeout)
+}
+
+type InvalidQueryParameterError struct {
+	parameter string
+	reason    string
+	value     string
+}
+
+func newInvalidQueryParameterError(parameter, reason, value string) error {
+	return &InvalidQueryParameterError{parameter: parameter, reason: reason, value: value}
+}
+
+func (i *InvalidQueryParameterError) Error() string {
+	return fmt.Sprintf("invalid query parameter %q: %s, got %q", i.parameter, i.reason, i.value)
+}
+
 type MetricHolderNotFoundError struct {
 	namespace string
 }
@@ -57,3 +87,33 @@ type MetricsDisabledError struct{}
 func (e *MetricsDisabledError) Error() string {
 	return "metrics are disabled"
 }
+
+type InvalidCircuitBreakerThresholdError struct {
+	value string
+}
+
+func newInvalidCircuitBreakerThresholdError(value string) error {
+	return &InvalidCircuitBreakerThresholdError{value: value}
+}
+
+func (i *InvalidCircuitBreakerThresholdError) Error() string {
+	return fmt.Sprintf(
+		"invalid circuit breaker threshold %q: has to be a positive number of workloads or a percentage, e.g. 20 or 30%%",
+		i.value,
+	)
+}
+
+type InvalidEventVerbosityError struct {
+	value string
+}
+
+func newInvalidEventVerbosityError(value string) error {
+	return &InvalidEventVerbosityError{value: value}
+}
+
+func (i *InvalidEventVerbosityError) Error() string {
+	return fmt.Sprintf(
+		"invalid event verbosity %q: has to be either %q, %q or %q",
+		i.value, eventVerbosityErrors, eventVerbosityScaling, eventVerbosityAll,
+	)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// eventVerbosity sets which normal events are added to workloads. Warnings are always added.
+type eventVerbosity string
+
+const (
+	// eventVerbosityErrors only adds warnings and events about respected manual changes.
+	eventVerbosityErrors eventVerbosity = "errors"
+	// eventVerbosityScaling additionally adds events when a workload is scaled down or up.
+	eventVerbosityScaling eventVerbosity = "scaling"
+	// eventVerbosityAll additionally adds events when a workload is skipped.
+	eventVerbosityAll eventVerbosity = "all"
+)
+
+func (e *eventVerbosity) Set(value string) error {
+	switch eventVerbosity(value) {
+	case eventVerbosityErrors, eventVerbosityScaling, eventVerbosityAll:
+		*e = eventVerbosity(value)
+		return nil
+	default:
+		return newInvalidEventVerbosityError(value)
+	}
+}
+
+func (e *eventVerbosity) String() string {
+	if *e == "" {
+		return string(eventVerbosityErrors)
+	}
+
+	return string(*e)
+}
+
+// includesScaling checks if events are added when a workload is scaled down or up.
+func (e eventVerbosity) includesScaling() bool {
+	return e == eventVerbosityScaling || e == eventVerbosityAll
+}
+
+// includesSkipped checks if events are added when a workload is skipped.
+func (e eventVerbosity) includesSkipped() bool {
+	return e == eventVerbosityAll
+}
+
+// getScalingReason describes why the workload is scaled, so it can be added to the events of the workload.
+func getScalingReason(scaling values.Scaling, upscaledOnExclusion bool, scopes values.Scopes) string {
+	switch {
+	case upscaledOnExclusion:
+		return "the workload is excluded and excluded workloads are scaled up"
+	case scopes.IsScalingForced():
+		return "the scaling is forced"
+	case scaling == values.ScalingDown:
+		return "the workload is in its downtime"
+	default:
+		return "the workload is in its uptime"
+	}
+}
+
+// reportScaling adds an event on the workload if it was scaled down or up by this scan.
+func reportScaling(
+	workload scalable.Workload,
+	scaling values.Scaling,
+	wasScaledDown, upscaledOnExclusion bool,
+	scopes values.Scopes,
+	resourceLogger kubernetes.ResourceLogger,
+	ctx context.Context,
+	verbosity eventVerbosity,
+) {
+	if !verbosity.includesScaling() {
+		return
+	}
+
+	isScaledDown := scalable.IsScaledDown(workload)
+	reason := getScalingReason(scaling, upscaledOnExclusion, scopes)
+
+	switch {
+	case scaling == values.ScalingDown && !wasScaledDown && isScaledDown:
+		downscaleReplicas, err := scopes.GetDownscaleReplicas()
+		if err != nil {
+			resourceLogger.InfoScaledDown("scaled down because "+reason, ctx)
+			return
+		}
+
+		resourceLogger.InfoScaledDown(fmt.Sprintf("scaled down to %s replicas because %s", downscaleReplicas, reason), ctx)
+	case scaling == values.ScalingUp && wasScaledDown && !isScaledDown:
+		resourceLogger.InfoScaledUp("scaled up to the original replicas because "+reason, ctx)
+	}
+}
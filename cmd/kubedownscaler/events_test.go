@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventVerbosity_Set(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		value         string
+		wantVerbosity eventVerbosity
+		wantScaling   bool
+		wantSkipped   bool
+		wantErr       bool
+	}{
+		{
+			name:          "errors",
+			value:         "errors",
+			wantVerbosity: eventVerbosityErrors,
+		},
+		{
+			name:          "scaling",
+			value:         "scaling",
+			wantVerbosity: eventVerbosityScaling,
+			wantScaling:   true,
+		},
+		{
+			name:          "all",
+			value:         "all",
+			wantVerbosity: eventVerbosityAll,
+			wantScaling:   true,
+			wantSkipped:   true,
+		},
+		{
+			name:    "invalid",
+			value:   "verbose",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			verbosity := eventVerbosityErrors
+
+			err := verbosity.Set(test.value)
+			if test.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, eventVerbosityErrors, verbosity)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.wantVerbosity, verbosity)
+			assert.Equal(t, test.wantScaling, verbosity.includesScaling())
+			assert.Equal(t, test.wantSkipped, verbosity.includesSkipped())
+		})
+	}
+}
+
+func TestGetScalingReason(t *testing.T) {
+	t.Parallel()
+
+	manualScalingEnd := time.Now().Add(time.Hour)
+	forcedScope := values.NewScope()
+	forcedScope.ManualScaling = values.ScalingDown
+	forcedScope.ManualScalingEnd = &manualScalingEnd
+
+	scopes := values.Scopes{values.NewScope(), values.NewScope(), values.NewScope(), values.NewScope(), values.GetDefaultScope()}
+	forcedScopes := values.Scopes{forcedScope, values.NewScope(), values.NewScope(), values.NewScope(), values.GetDefaultScope()}
+
+	tests := []struct {
+		name                string
+		scaling             values.Scaling
+		upscaledOnExclusion bool
+		scopes              values.Scopes
+		want                string
+	}{
+		{
+			name:                "upscaled on exclusion",
+			scaling:             values.ScalingUp,
+			upscaledOnExclusion: true,
+			scopes:              scopes,
+			want:                "the workload is excluded and excluded workloads are scaled up",
+		},
+		{
+			name:    "forced",
+			scaling: values.ScalingDown,
+			scopes:  forcedScopes,
+			want:    "the scaling is forced",
+		},
+		{
+			name:    "downtime",
+			scaling: values.ScalingDown,
+			scopes:  scopes,
+			want:    "the workload is in its downtime",
+		},
+		{
+			name:    "uptime",
+			scaling: values.ScalingUp,
+			scopes:  scopes,
+			want:    "the workload is in its uptime",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, getScalingReason(test.scaling, test.upscaledOnExclusion, test.scopes))
+		})
+	}
+}
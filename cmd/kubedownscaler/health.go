@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// healthStatus tracks the state of the scan loop for the health endpoints.
+type healthStatus struct {
+	mutex             sync.Mutex
+	identity          string
+	leaderElection    bool
+	leader            string
+	scanning          bool
+	cycleCompleted    bool
+	lastHeartbeat     time.Time
+	lastCycle         time.Time
+	lastCycleDuration time.Duration
+	lastCycleErrors   errorCounts
+	totalErrors       errorCounts
+	pauseMode         values.PauseMode
+	livenessThreshold time.Duration
+}
+
+// errorCounts holds the amount of errors of one or more scan cycles.
+type errorCounts struct {
+	Workloads  int `json:"workloads"`
+	Namespaces int `json:"namespaces"`
+}
+
+// healthStatusResponse is the json body returned by the status endpoint.
+type healthStatusResponse struct {
+	Identity          string      `json:"identity"`
+	LeaderElection    bool        `json:"leaderElection"`
+	Leader            string      `json:"leader,omitempty"`
+	Scanning          bool        `json:"scanning"`
+	Connected         bool        `json:"connected"`
+	Ready             bool        `json:"ready"`
+	Alive             bool        `json:"alive"`
+	LastCycleTime     *time.Time  `json:"lastCycleTime,omitempty"`
+	LastCycleDuration string      `json:"lastCycleDuration,omitempty"`
+	LastCycleErrors   errorCounts `json:"lastCycleErrors"`
+	TotalErrors       errorCounts `json:"totalErrors"`
+	PauseMode         string      `json:"pauseMode"`
+}
+
+// newHealthStatus creates a new healthStatus.
+// The scan loop is considered stuck if no cycle finished within livenessThreshold while scanning.
+func newHealthStatus(leaderElection bool, livenessThreshold time.Duration) *healthStatus {
+	identity, err := os.Hostname()
+	if err != nil {
+		slog.Warn("failed to get hostname for the health status", "error", err)
+	}
+
+	return &healthStatus{
+		identity:          identity,
+		leaderElection:    leaderElection,
+		livenessThreshold: livenessThreshold,
+	}
+}
+
+// setLeader records the identity of the current leader.
+func (h *healthStatus) setLeader(identity string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.leader = identity
+}
+
+// getLeader gets the identity of the current leader.
+func (h *healthStatus) getLeader() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.leader
+}
+
+// setPauseMode records the pause mode seen in the last scan cycle.
+func (h *healthStatus) setPauseMode(mode values.PauseMode) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.pauseMode = mode
+}
+
+// startScanning starts the watchdog on the scan loop.
+func (h *healthStatus) startScanning() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.scanning = true
+	h.cycleCompleted = false
+	h.lastHeartbeat = time.Now()
+}
+
+// stopScanning stops the watchdog on the scan loop.
+func (h *healthStatus) stopScanning() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.scanning = false
+}
+
+// heartbeat resets the watchdog while the scan loop is still making progress within a cycle,
+// e.g. while waiting for a dependency to become ready, which can take longer than the liveness threshold.
+func (h *healthStatus) heartbeat() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastHeartbeat = time.Now()
+}
+
+// finishCycle records a finished scan cycle, resetting the watchdog.
+func (h *healthStatus) finishCycle(start time.Time, workloadErrors, namespaceErrors int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+
+	h.cycleCompleted = true
+	h.lastHeartbeat = now
+	h.lastCycle = now
+	h.lastCycleDuration = now.Sub(start)
+	h.lastCycleErrors = errorCounts{Workloads: workloadErrors, Namespaces: namespaceErrors}
+	h.totalErrors.Workloads += workloadErrors
+	h.totalErrors.Namespaces += namespaceErrors
+}
+
+// isScanning checks if the scan loop is running on this instance, which is only the case for the leader with leader election.
+func (h *healthStatus) isScanning() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.scanning
+}
+
+// isReady checks if the scan loop is ready. A scanning instance is only ready after it completed its first cycle.
+func (h *healthStatus) isReady() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return !h.scanning || h.cycleCompleted
+}
+
+// isAlive checks if the scan loop finished a cycle within the liveness threshold.
+func (h *healthStatus) isAlive() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return !h.scanning || time.Since(h.lastHeartbeat) <= h.livenessThreshold
+}
+
+// getStatus gets the current health status as its response body.
+func (h *healthStatus) getStatus(connected bool) healthStatusResponse {
+	ready, alive := h.isReady(), h.isAlive()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	status := healthStatusResponse{
+		Identity:        h.identity,
+		LeaderElection:  h.leaderElection,
+		Leader:          h.leader,
+		Scanning:        h.scanning,
+		Connected:       connected,
+		Ready:           connected && ready,
+		Alive:           alive,
+		LastCycleErrors: h.lastCycleErrors,
+		TotalErrors:     h.totalErrors,
+		PauseMode:       h.pauseMode.String(),
+	}
+
+	if !h.lastCycle.IsZero() {
+		lastCycle := h.lastCycle
+		status.LastCycleTime = &lastCycle
+		status.LastCycleDuration = h.lastCycleDuration.String()
+	}
+
+	return status
+}
+
+// newHealthHandler creates the handler with the liveness, readiness and status endpoints.
+func newHealthHandler(client kubernetes.Client, health *healthStatus) http.Handler {
+	serveMux := http.NewServeMux()
+
+	serveMux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		if !health.isAlive() {
+			slog.Error("liveness check failed, no scan cycle finished within the liveness threshold", "threshold", health.livenessThreshold.String())
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serveMux.HandleFunc("/readyz", func(w http.ResponseWriter, req *http.Request) {
+		if !isConnected(client, req.Context()) || !health.isReady() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serveMux.HandleFunc("/status", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		err := json.NewEncoder(w).Encode(health.getStatus(isConnected(client, req.Context())))
+		if err != nil {
+			slog.Error("failed to write status response", "error", err)
+		}
+	})
+
+	return serveMux
+}
+
+// isConnected checks if the client can reach the API server.
+func isConnected(client kubernetes.Client, ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	err := client.CheckConnection(ctx)
+	if err != nil {
+		slog.Warn("failed to connect to the API server", "error", err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errConnectionRefused = errors.New("connection refused")
+
+func (m *MockClient) CheckConnection(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		connectionErr error
+		setupHealth   func(health *healthStatus)
+		wantHealthz   int
+		wantReadyz    int
+	}{
+		{
+			name:        "standby instance is ready and alive",
+			setupHealth: func(_ *healthStatus) {},
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusOK,
+		},
+		{
+			name:          "not ready without a connection to the API server",
+			connectionErr: errConnectionRefused,
+			setupHealth:   func(_ *healthStatus) {},
+			wantHealthz:   http.StatusOK,
+			wantReadyz:    http.StatusServiceUnavailable,
+		},
+		{
+			name: "not ready before the first cycle finished",
+			setupHealth: func(health *healthStatus) {
+				health.startScanning()
+			},
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusServiceUnavailable,
+		},
+		{
+			name: "ready after the first cycle finished",
+			setupHealth: func(health *healthStatus) {
+				health.startScanning()
+				health.finishCycle(time.Now(), 0, 0)
+			},
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusOK,
+		},
+		{
+			name: "not alive when no cycle finished within the threshold",
+			setupHealth: func(health *healthStatus) {
+				health.startScanning()
+				health.finishCycle(time.Now(), 0, 0)
+				health.lastHeartbeat = time.Now().Add(-2 * time.Minute)
+			},
+			wantHealthz: http.StatusServiceUnavailable,
+			wantReadyz:  http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(MockClient)
+			mockClient.On("CheckConnection", mock.Anything).Return(test.connectionErr)
+
+			health := newHealthStatus(true, time.Minute)
+			test.setupHealth(health)
+
+			handler := newHealthHandler(mockClient, health)
+
+			for path, wantStatusCode := range map[string]int{"/healthz": test.wantHealthz, "/readyz": test.wantReadyz} {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil))
+
+				require.Equal(t, wantStatusCode, recorder.Code, path)
+			}
+		})
+	}
+}
+
+func TestHealthStatus(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(MockClient)
+	mockClient.On("CheckConnection", mock.Anything).Return(nil)
+
+	health := newHealthStatus(true, time.Minute)
+	health.setLeader("downscaler-0")
+	health.startScanning()
+	health.finishCycle(time.Now(), 2, 1)
+	health.finishCycle(time.Now(), 1, 0)
+
+	recorder := httptest.NewRecorder()
+	newHealthHandler(mockClient, health).ServeHTTP(recorder, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var status healthStatusResponse
+
+	err := json.NewDecoder(recorder.Body).Decode(&status)
+	require.NoError(t, err)
+
+	require.Equal(t, "downscaler-0", status.Leader)
+	require.True(t, status.Scanning)
+	require.True(t, status.Ready)
+	require.True(t, status.Alive)
+	require.NotNil(t, status.LastCycleTime)
+	require.Equal(t, errorCounts{Workloads: 1, Namespaces: 0}, status.LastCycleErrors)
+	require.Equal(t, errorCounts{Workloads: 3, Namespaces: 1}, status.TotalErrors)
+}
+
+func TestHealthStatus_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	health := newHealthStatus(false, time.Minute)
+	health.startScanning()
+	health.lastHeartbeat = time.Now().Add(-2 * time.Minute)
+
+	require.False(t, health.isAlive())
+
+	health.heartbeat()
+
+	require.True(t, health.isAlive(), "waiting within a cycle should keep the scan loop alive")
+	require.False(t, health.isReady(), "a heartbeat doesn't finish the cycle")
+}
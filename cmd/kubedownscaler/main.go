@@ -5,20 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	_ "time/tzdata"
 
 	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/audit"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
-	"k8s.io/apiserver/pkg/registry/generic/registry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apiserver/pkg/server/mux"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -33,26 +37,44 @@ func main() {
 
 	slog.Debug("getting client for kubernetes")
 
-	client, err := kubernetes.NewClient(config.Kubeconfig, config.DryRun, config.Qps, config.Burst)
+	client, err := kubernetes.NewClient(config.Kubeconfig, config.DryRun, config.Qps, config.Burst, config.StateStore)
 	if err != nil {
 		slog.Error("failed to create new Kubernetes client", "error", err)
 		os.Exit(1)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// ctx is cancelled on SIGTERM/SIGINT, which stops the downscaler from accepting new work
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-	defer cancel()
+	defer stop()
 
-	go serveHealth()
+	health := newHealthStatus(config.LeaderElection, time.Duration(config.LivenessIntervalMultiplier)*config.Interval)
+
+	go serveHealth(client, health)
+
+	trigger := newScanTrigger()
+	breaker := newCircuitBreaker(config)
+
+	if config.AdminAPI {
+		go serveAdminAPI(client, trigger, health, config)
+	}
 
 	downscalerMetrics := initMetrics(config)
 
+	auditSink, err := audit.NewSink(config.AuditSink, config.AuditFileMaxSize, config.AuditFileMaxBackups)
+	if err != nil {
+		slog.Error("failed to create audit sink", "error", err)
+		os.Exit(1)
+	}
+
+	defer auditSink.Close()
+
 	if !config.LeaderElection {
-		runWithoutLeaderElection(client, ctx, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics)
+		runWithoutLeaderElection(client, ctx, trigger, health, breaker, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics, auditSink)
 		return
 	}
 
-	runWithLeaderElection(client, cancel, ctx, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics)
+	runWithLeaderElection(client, ctx, trigger, health, breaker, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics, auditSink)
 }
 
 // serveMetrics starts the metrics server for the downscaler.
@@ -82,20 +104,10 @@ func serveMetrics() {
 }
 
 // serveHealth starts the health server for the downscaler.
-func serveHealth() {
-	pathRecorderMux := http.NewServeMux()
-
-	pathRecorderMux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
-
-	pathRecorderMux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
-
+func serveHealth(client kubernetes.Client, health *healthStatus) {
 	server := &http.Server{
 		Addr:         ":8081",
-		Handler:      pathRecorderMux,
+		Handler:      newHealthHandler(client, health),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -109,13 +121,17 @@ func serveHealth() {
 }
 
 // runWithLeaderElection runs the downscaler with leader election enabled.
+// The lease is only released after all in-flight scaling operations were drained on shutdown.
 func runWithLeaderElection(
 	client kubernetes.Client,
-	cancel context.CancelFunc,
 	ctx context.Context,
+	trigger *scanTrigger,
+	health *healthStatus,
+	breaker *circuitBreaker,
 	scopeDefault, scopeCli, scopeEnv *values.Scope,
 	config *runtimeConfiguration,
 	downscalerMetrics *metrics.Metrics,
+	auditSink audit.Sink,
 ) {
 	lease, err := client.CreateLease(leaseName)
 	if err != nil {
@@ -123,36 +139,60 @@ func runWithLeaderElection(
 		os.Exit(1)
 	}
 
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	// leaderCtx is decoupled from ctx, so the lease isn't released before scanning has stopped
+	leaderCtx, cancelLeader := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelLeader()
 
-	go func() {
-		<-sigs
-		cancel()
-	}()
+	var startedLeading atomic.Bool
 
-	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+	stopLeaderOnShutdown := context.AfterFunc(ctx, func() {
+		if !startedLeading.Load() {
+			cancelLeader()
+		}
+	})
+	defer stopLeaderOnShutdown()
+
+	leaderelection.RunOrDie(leaderCtx, leaderelection.LeaderElectionConfig{
 		Lock:            lease,
 		ReleaseOnCancel: true,
 		LeaseDuration:   30 * time.Second,
 		RenewDeadline:   20 * time.Second,
 		RetryPeriod:     5 * time.Second,
 		Callbacks: leaderelection.LeaderCallbacks{
-			OnStartedLeading: func(ctx context.Context) {
+			OnStartedLeading: func(leadingCtx context.Context) {
+				startedLeading.Store(true)
+				defer cancelLeader()
+
 				slog.Info("started leading")
 
-				err = startScanning(client, ctx, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics)
-				if err != nil {
-					slog.Error("an error occurred while scanning workloads", "error", err)
-					cancel()
+				scanCtx, stopScanning := context.WithCancel(leadingCtx)
+				defer stopScanning()
+
+				stopScanningOnShutdown := context.AfterFunc(ctx, stopScanning)
+				defer stopScanningOnShutdown()
+
+				scanErr := startScanning(
+					client,
+					scanCtx,
+					trigger,
+					health,
+					breaker,
+					scopeDefault, scopeCli, scopeEnv,
+					config,
+					downscalerMetrics,
+					auditSink,
+				)
+				if scanErr != nil {
+					slog.Error("an error occurred while scanning workloads", "error", scanErr)
 				}
 			},
 			OnStoppedLeading: func() {
 				slog.Info("stopped leading")
-				cancel()
+				cancelLeader()
 			},
 			OnNewLeader: func(identity string) {
 				slog.Info("new leader elected", "identity", identity)
+				health.setLeader(identity)
 			},
 		},
 	})
@@ -162,44 +202,111 @@ func runWithLeaderElection(
 func runWithoutLeaderElection(
 	client kubernetes.Client,
 	ctx context.Context,
+	trigger *scanTrigger,
+	health *healthStatus,
+	breaker *circuitBreaker,
 	scopeDefault, scopeCli, scopeEnv *values.Scope,
 	config *runtimeConfiguration,
 	downscalerMetrics *metrics.Metrics,
+	auditSink audit.Sink,
 ) {
 	slog.Warn("proceeding without leader election; this could cause errors when running with multiple replicas")
 
-	err := startScanning(client, ctx, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics)
+	err := startScanning(client, ctx, trigger, health, breaker, scopeDefault, scopeCli, scopeEnv, config, downscalerMetrics, auditSink)
 	if err != nil {
 		slog.Error("an error occurred while scanning workloads, exiting", "error", err)
 		os.Exit(1)
 	}
+
+	slog.Info("downscaler stopped")
 }
 
 // startScanning periodically triggers a scan on all workloads.
+// Scans requested through the trigger run immediately, only covering the requested namespaces if possible.
+// Once ctx is cancelled no new scaling operations are started and in-flight ones are drained
+// for up to the configured shutdown timeout before returning.
+//
+//nolint:funlen,cyclop // the scan loop is easier to follow in one place
 func startScanning(
 	client kubernetes.Client,
 	ctx context.Context,
+	trigger *scanTrigger,
+	health *healthStatus,
+	breaker *circuitBreaker,
 	scopeDefault, scopeCli, scopeEnv *values.Scope,
 	config *runtimeConfiguration,
 	downscalerMetrics *metrics.Metrics,
+	auditSink audit.Sink,
 ) error {
 	slog.Info("started downscaler")
 
+	health.startScanning()
+	defer health.stopScanning()
+
+	// scaling operations run on their own context, so they aren't cut off as soon as ctx is cancelled
+	scalingCtx, cancelScaling := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelScaling()
+
+	var inFlightScalings sync.WaitGroup
+	defer drainInFlightScalings(&inFlightScalings, cancelScaling, config.ShutdownTimeout)
+
+	verifier := newUpscaleVerifier(&inFlightScalings, config)
+	waits := newChildrenWait(config.DependencyReadyTimeout)
+
 	previousNamespacesToMetrics := newNamespaceToMetrics(config)
+	resourceDiscovery := newResourceTypeDiscovery(client, config.IncludeResources, config.ResourceDiscoveryInterval)
+	backoff := newNamespaceBackoff(config.Interval, config.MaxNamespaceBackoff)
+
+	scanNamespaces, isFullScan := config.IncludeNamespaces, true
+	pauseMode := values.PauseModeNone
 
 	for {
-		slog.Info("scanning workloads")
+		slog.Info("scanning workloads", "fullScan", isFullScan, "namespaces", scanNamespaces)
 
 		start := time.Now()
 		currentNamespaceToMetrics := newNamespaceToMetrics(config)
+		auditor := newScanAuditor(auditSink, config.DryRun)
 
-		workloads, err := client.GetWorkloads(config.IncludeNamespaces, config.IncludeResources, ctx)
+		resourceTypes, err := resourceDiscovery.getActiveResourceTypes(downscalerMetrics, config.MetricsEnabled)
 		if err != nil {
-			return fmt.Errorf("failed to get workloads: %w", err)
+			return fmt.Errorf("failed to get active resource types: %w", err)
+		}
+
+		// manually requested namespaces are scanned even if they are backing off
+		backingOff := map[string]struct{}{}
+		if isFullScan {
+			backingOff = backoff.getBackingOff()
+		}
+
+		workloads, err := client.GetWorkloads(filterNamespaces(scanNamespaces, backingOff), resourceTypes, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				slog.Info("shutting down, stopping scan cycle")
+				return nil
+			}
+
+			var invalidResourceErr *scalable.InvalidResourceError
+			if errors.As(err, &invalidResourceErr) {
+				return fmt.Errorf("failed to get workloads: %w", err)
+			}
+
+			slog.Error("failed to get some workloads, continuing with the remaining ones", "error", err)
+		}
+
+		failedNamespaces := kubernetes.GetFailedNamespaces(err)
+
+		workloads = filterWorkloads(workloads, backingOff)
+
+		autoscalers, workloads, hpaFailedNamespaces := getHorizontalPodAutoscalers(client, scanNamespaces, backingOff, workloads, ctx)
+		failedNamespaces = append(failedNamespaces, hpaFailedNamespaces...)
+
+		if config.RedirectToHPA {
+			workloads = scalable.RedirectToHorizontalPodAutoscalers(workloads, autoscalers)
 		}
 
 		workloads = scalable.FilterExcluded(
 			workloads,
+			autoscalers,
 			config.IncludeLabels,
 			config.ExcludeNamespaces,
 			config.ExcludeWorkloads,
@@ -209,45 +316,123 @@ func startScanning(
 
 		namespaceScopes, err := client.GetNamespacesScopes(workloads, ctx)
 		if err != nil {
-			return fmt.Errorf("failed to get namespace annotations: %w", err)
+			if ctx.Err() != nil {
+				slog.Info("shutting down, stopping scan cycle")
+				return nil
+			}
+
+			slog.Error("failed to get some namespace annotations, skipping their workloads", "error", err)
+
+			failedNamespaces = append(failedNamespaces, kubernetes.GetFailedNamespaces(err)...)
+			workloads = slices.DeleteFunc(workloads, func(workload scalable.Workload) bool {
+				_, exists := namespaceScopes[workload.GetNamespace()]
+				return !exists
+			})
+		}
+
+		if isFullScan {
+			backoff.update(failedNamespaces, backingOff, currentNamespaceToMetrics)
 		}
 
+		pauseMode = getPauseMode(client, ctx, pauseMode)
+		health.setPauseMode(pauseMode)
+		downscalerMetrics.UpdatePauseMode(config.MetricsEnabled, pauseMode)
+
+		var tripped bool
+
+		switch pauseMode {
+		case values.PauseModeFreeze:
+			slog.Info("downscaler is paused, not changing any workloads", "mode", pauseMode.String())
+
+			workloads = nil
+		case values.PauseModeUpscaleAll:
+			slog.Info("downscaler is paused, scaling up all workloads", "mode", pauseMode.String())
+		default:
+			tripped = breaker.check(workloads, client, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes, config)
+			if tripped {
+				workloads = nil
+			}
+		}
+
+		downscalerMetrics.UpdateCircuitBreaker(config.MetricsEnabled, tripped)
+
+		dependencies := newDependencyGraph(workloads, health.heartbeat)
+
+		var workloadErrors atomic.Int64
+
 		var waitGroup sync.WaitGroup
 		for _, workload := range workloads {
+			if ctx.Err() != nil {
+				slog.Info("shutting down, not scanning remaining workloads in this cycle")
+				break
+			}
+
 			waitGroup.Add(1)
+			inFlightScalings.Add(1)
 
-			go func(workload scalable.Workload) {
+			go func(workload scalable.Workload, node *dependencyNode) {
 				slog.Debug("scanning workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
 
 				defer waitGroup.Done()
+				defer inFlightScalings.Done()
+				defer node.finish()
 
 				workloadNamespaceMetrics, err := getWorkloadNamespaceMetrics(config, workload, currentNamespaceToMetrics)
 				if err != nil && !errors.Is(err, ErrMetricsDisabled) {
 					slog.Error("failed to get namespace metrics", "error", err, "namespace", workload.GetNamespace())
+					workloadErrors.Add(1)
+
 					return
 				}
 
-				err = scanWorkload(workload, client, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes, workloadNamespaceMetrics, config)
+				if pauseMode == values.PauseModeUpscaleAll {
+					err = upscalePausedWorkload(workload, client, scalingCtx, node, verifier, workloadNamespaceMetrics, auditor, config)
+				} else {
+					err = scanWorkload(
+						workload,
+						client,
+						scalingCtx,
+						&inFlightScalings,
+						node,
+						verifier,
+						waits,
+						scopeDefault, scopeCli, scopeEnv,
+						namespaceScopes,
+						workloadNamespaceMetrics,
+						auditor,
+						config,
+					)
+				}
+
 				if err != nil {
 					slog.Error("failed to scan workload", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+					workloadErrors.Add(1)
+					recordScanError(workload, err, client, scalingCtx, config)
+
 					return
 				}
 
 				slog.Debug("successfully scanned workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
-			}(workload)
+			}(workload, dependencies.getNode(workload))
 		}
 
 		waitGroup.Wait()
 		slog.Info("successfully scanned all workloads")
 
-		downscalerMetrics.UpdateMetrics(
-			config.MetricsEnabled,
-			currentNamespaceToMetrics,
-			previousNamespacesToMetrics,
-			time.Since(start).Seconds(),
-		)
+		health.finishCycle(start, int(workloadErrors.Load()), len(failedNamespaces))
 
-		previousNamespacesToMetrics = currentNamespaceToMetrics
+		// metrics of partial or aborted scans would remove the metrics of all namespaces which weren't scanned
+		if isFullScan && !tripped && pauseMode != values.PauseModeFreeze {
+			verifier.collectResults(currentNamespaceToMetrics)
+			downscalerMetrics.UpdateMetrics(
+				config.MetricsEnabled,
+				currentNamespaceToMetrics,
+				previousNamespacesToMetrics,
+				time.Since(start).Seconds(),
+			)
+
+			previousNamespacesToMetrics = currentNamespaceToMetrics
+		}
 
 		if config.Once {
 			slog.Debug("once is set to true, exiting")
@@ -255,12 +440,83 @@ func startScanning(
 		}
 
 		slog.Debug("waiting until next scan", "interval", config.Interval.String())
-		time.Sleep(config.Interval)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down, stopping scan loop")
+			return nil
+		case <-time.After(config.Interval):
+			// the full scan covers all pending requests
+			trigger.take(config.IncludeNamespaces)
+			scanNamespaces, isFullScan = config.IncludeNamespaces, true
+		case <-trigger.notified():
+			scanNamespaces, isFullScan = trigger.take(config.IncludeNamespaces)
+			slog.Info("scan was requested manually")
+		}
 	}
 
 	return nil
 }
 
+// getHorizontalPodAutoscalers gets the horizontalpodautoscalers of the scanned namespaces.
+// They are listed even if they aren't included, so the workloads managed by them are always detected.
+// Workloads of namespaces whose horizontalpodautoscalers couldn't be listed are skipped, as they could conflict with them.
+func getHorizontalPodAutoscalers(
+	client kubernetes.Client,
+	namespaces []string,
+	backingOff map[string]struct{},
+	workloads []scalable.Workload,
+	ctx context.Context,
+) ([]scalable.Workload, []scalable.Workload, []string) {
+	namespaces = filterNamespaces(namespaces, backingOff)
+	if namespaces == nil {
+		namespaces = []string{""}
+	}
+
+	var autoscalers []scalable.Workload
+
+	failedNamespaces := make(map[string]struct{})
+
+	for _, namespace := range namespaces {
+		hpas, err := client.GetHorizontalPodAutoscalers(namespace, ctx)
+		if err != nil {
+			slog.Error("failed to get horizontalpodautoscalers, skipping the workloads of the namespace", "error", err, "namespace", namespace)
+
+			failedNamespaces[namespace] = struct{}{}
+
+			continue
+		}
+
+		autoscalers = append(autoscalers, hpas...)
+	}
+
+	if _, allFailed := failedNamespaces[""]; allFailed {
+		return nil, nil, nil
+	}
+
+	return autoscalers, filterWorkloads(workloads, failedNamespaces), slices.Collect(maps.Keys(failedNamespaces))
+}
+
+// drainInFlightScalings waits for all in-flight scaling operations to finish.
+// If they don't finish within the timeout their context gets cancelled.
+func drainInFlightScalings(inFlightScalings *sync.WaitGroup, cancelScaling context.CancelFunc, timeout time.Duration) {
+	drained := make(chan struct{})
+
+	go func() {
+		inFlightScalings.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		slog.Debug("all in-flight scaling operations finished")
+	case <-time.After(timeout):
+		slog.Warn("in-flight scaling operations did not finish within the shutdown timeout, cancelling them", "timeout", timeout.String())
+		cancelScaling()
+		<-drained
+	}
+}
+
 // attemptScaling handles retries for scaling a workload in case of conflicts.
 func attemptScaling(
 	client kubernetes.Client,
@@ -268,13 +524,15 @@ func attemptScaling(
 	scaling values.Scaling,
 	workload scalable.Workload,
 	scopes values.Scopes,
+	status *scalable.Status,
 	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
 	config *runtimeConfiguration,
 ) error {
 	for retry := range config.MaxRetriesOnConflict + 1 {
-		err := scaleWorkload(scaling, workload, scopes, workloadNamespaceMetrics, client, ctx)
+		err := scaleWorkload(scaling, workload, scopes, status, workloadNamespaceMetrics, auditor, client, ctx)
 		if err != nil {
-			if !strings.Contains(err.Error(), registry.OptimisticLockErrorMsg) {
+			if !apierrors.IsConflict(err) {
 				workloadNamespaceMetrics.IncrementGenericErrorsCount()
 				return fmt.Errorf("failed to scale workload: %w", err)
 			}
@@ -301,38 +559,28 @@ func attemptScaling(
 }
 
 // scanWorkload runs a scan on the workload, determining the scaling and scaling the workload.
+// If the workload is part of a dependency graph it is scaled in order with its related workloads.
 func scanWorkload(
 	workload scalable.Workload,
 	client kubernetes.Client,
 	ctx context.Context,
+	inFlightScalings *sync.WaitGroup,
+	node *dependencyNode,
+	verifier *upscaleVerifier,
+	waits *childrenWait,
 	scopeDefault, scopeCli, scopeEnv *values.Scope,
 	namespaceScopes map[string]*values.Scope,
 	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
 	config *runtimeConfiguration,
 ) error {
 	resourceLogger := kubernetes.NewResourceLoggerForWorkload(client, workload)
 
-	var err error
-
-	slog.Debug(
-		"parsing workload scope from annotations",
-		"annotations", workload.GetAnnotations(),
-		"name", workload.GetName(),
-		"namespace", workload.GetNamespace(),
-	)
-
-	scopeWorkload := values.NewScope()
-	if err = scopeWorkload.GetScopeFromAnnotations(workload.GetAnnotations(), resourceLogger, ctx); err != nil {
-		return fmt.Errorf("failed to parse workload scope from annotations: %w", err)
-	}
-
-	scopeNamespace, exists := namespaceScopes[workload.GetNamespace()]
-	if !exists {
-		return newNamespaceScopeRetrieveError(workload.GetNamespace())
+	scopes, err := getWorkloadScopes(workload, resourceLogger, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes)
+	if err != nil {
+		return err
 	}
 
-	scopes := values.Scopes{scopeWorkload, scopeNamespace, scopeCli, scopeEnv, scopeDefault}
-
 	slog.Debug("finished parsing all scopes", "scopes", scopes, "workload", workload.GetName(), "namespace", workload.GetNamespace())
 
 	isInGracePeriod, err := scopes.IsInGracePeriod(
@@ -351,6 +599,12 @@ func scanWorkload(
 		slog.Debug("workload is on grace period, skipping", "workload", workload.GetName(), "namespace", workload.GetNamespace())
 		workloadNamespaceMetrics.IncrementExcludedWorkloadsCount()
 
+		if config.EventVerbosity.includesSkipped() {
+			resourceLogger.InfoInGracePeriod("the workload is in its grace period, skipping it", ctx)
+		}
+
+		updateWorkloadStatus(workload, scalable.Status{State: scalable.StatusGracePeriod}, client, ctx, config)
+
 		return nil
 	}
 
@@ -361,34 +615,263 @@ func scanWorkload(
 		slog.Debug("workload is excluded, skipping", "workload", workload.GetName(), "namespace", workload.GetNamespace())
 		workloadNamespaceMetrics.IncrementExcludedWorkloadsCount()
 
+		if config.EventVerbosity.includesSkipped() {
+			resourceLogger.InfoSkippedExcluded("the workload is excluded, skipping it", ctx)
+		}
+
+		updateWorkloadStatus(workload, scalable.Status{State: scalable.StatusExcluded}, client, ctx, config)
+
 		return nil
 	}
 
 	scaling := getCurrentScaling(workload, excluded, upscaleOnExclusion, &scopes)
 
-	err = attemptScaling(client, ctx, scaling, workload, scopes, workloadNamespaceMetrics, config)
+	var status scalable.Status
+	if config.StatusAnnotation {
+		status = getScheduleStatus(excluded, scopes)
+	}
+
+	inMinStateDuration, err := isInMinStateDuration(workload, scaling, scopes)
+	if err != nil {
+		return fmt.Errorf("failed to check the minimum state duration: %w", err)
+	}
+
+	if inMinStateDuration {
+		slog.Info(
+			"workload was scaled recently, waiting for the minimum state duration to pass",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+		)
+		updateWorkloadStatus(workload, status, client, ctx, config)
+
+		return nil
+	}
+
+	inManualOverride, err := isInManualOverride(workload, scaling, scopes, client, resourceLogger, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for a manual override: %w", err)
+	}
+
+	if inManualOverride {
+		workloadNamespaceMetrics.IncrementManualOverridesCount()
+
+		status.State = scalable.StatusManualOverride
+		updateWorkloadStatus(workload, status, client, ctx, config)
+
+		return nil
+	}
+
+	err = node.waitForTurn(scaling, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for related workloads: %w", err)
+	}
+
+	wasScaledDown := scalable.IsScaledDown(workload)
+
+	var batchedStatus *scalable.Status
+	if config.StatusAnnotation {
+		batchedStatus = &status
+	}
+
+	// workloads which would revert the scaled down state of their children are scaled up after their children
+	scaleChildrenFirst := scaling == values.ScalingUp && scopes.GetScaleChildren() && scalable.ScalesUpChildrenFirst(workload)
+	if scaleChildrenFirst {
+		isWaitingForChildren, err := scaleUpChildrenFirst(
+			workload,
+			scopes,
+			scopeDefault, scopeCli, scopeEnv,
+			namespaceScopes,
+			workloadNamespaceMetrics,
+			auditor,
+			client,
+			ctx,
+			inFlightScalings,
+			config,
+		)
+		if err != nil {
+			return err
+		}
+
+		if isWaitingForChildren && !waits.hasTimedOut(workload) {
+			slog.Info(
+				"scaling up children of the workload, the workload is scaled up once they are scaled up",
+				"workload", workload.GetName(),
+				"namespace", workload.GetNamespace(),
+			)
+			updateWorkloadStatus(workload, status, client, ctx, config)
+
+			return nil
+		}
+
+		if isWaitingForChildren {
+			slog.Warn(
+				"children of the workload weren't scaled up in time, scaling up the workload anyway",
+				"workload", workload.GetName(),
+				"namespace", workload.GetNamespace(),
+				"timeout", config.DependencyReadyTimeout.String(),
+			)
+			resourceLogger.WarnChildrenNotScaledUp(
+				fmt.Sprintf("not all children were scaled up within %s, scaling up the workload anyway", config.DependencyReadyTimeout),
+				ctx,
+			)
+		}
+	}
+
+	// interrupted waits, e.g. by a downtime, start over the next time the workload is scaled up
+	waits.finish(workload)
+
+	err = attemptScaling(client, ctx, scaling, workload, scopes, batchedStatus, workloadNamespaceMetrics, auditor, config)
 	if err != nil {
 		return fmt.Errorf("failed to scale workload: %w", err)
 	}
 
-	if scopes.GetScaleChildren() {
-		childrenWorkloads, err := client.GetChildrenWorkloads(workload, ctx)
+	if scaling != values.ScalingDown && scaling != values.ScalingUp {
+		updateWorkloadStatus(workload, status, client, ctx, config)
+	}
+
+	reportScaling(workload, scaling, wasScaledDown, excluded, scopes, resourceLogger, ctx, config.EventVerbosity)
+
+	if scaling == values.ScalingUp && wasScaledDown {
+		verifier.verify(workload, client, resourceLogger, ctx)
+	}
+
+	if scaling == values.ScalingUp && node.hasDependents() && !config.DryRun {
+		err = waitUntilReady(client, workload, config.DependencyReadyTimeout, node.heartbeat, ctx)
 		if err != nil {
-			return fmt.Errorf("failed to get children workloads: %w", err)
+			return fmt.Errorf("failed to wait for workload to become ready: %w", err)
 		}
+	}
 
-		slog.Debug(
-			"scaling children workloads",
-			"workload", workload.GetName(),
-			"namespace", workload.GetNamespace(),
-			"childrenCount", len(childrenWorkloads),
-		)
-		scaleWorkloads(scaling, childrenWorkloads, scopes, workloadNamespaceMetrics, client, ctx, config)
+	if scopes.GetScaleChildren() && !scaleChildrenFirst {
+		err = scaleChildren(scaling, workload, scopes, workloadNamespaceMetrics, auditor, client, ctx, inFlightScalings, config)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// scaleChildren scales the children of the workload according to the given wanted scaling state.
+func scaleChildren(
+	scaling values.Scaling,
+	workload scalable.Workload,
+	scopes values.Scopes,
+	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
+	client kubernetes.Client,
+	ctx context.Context,
+	inFlightScalings *sync.WaitGroup,
+	config *runtimeConfiguration,
+) error {
+	childrenWorkloads, err := client.GetChildrenWorkloads(workload, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get children workloads: %w", err)
+	}
+
+	slog.Debug(
+		"scaling children workloads",
+		"workload", workload.GetName(),
+		"namespace", workload.GetNamespace(),
+		"childrenCount", len(childrenWorkloads),
+	)
+	scaleWorkloads(scaling, childrenWorkloads, scopes, workloadNamespaceMetrics, auditor, client, ctx, inFlightScalings, config)
+
+	return nil
+}
+
+// scaleUpChildrenFirst starts scaling up the scaled down children of the workload and returns if there were any.
+// The workload isn't scaled up until a later scan finds all of its children scaled up,
+// so its scan doesn't have to wait for them and it isn't scaled up while one of them failed to scale up.
+// Children which are kept scaled down by their own scopes are skipped, as they would never be scaled up.
+func scaleUpChildrenFirst(
+	workload scalable.Workload,
+	scopes values.Scopes,
+	scopeDefault, scopeCli, scopeEnv *values.Scope,
+	namespaceScopes map[string]*values.Scope,
+	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
+	client kubernetes.Client,
+	ctx context.Context,
+	inFlightScalings *sync.WaitGroup,
+	config *runtimeConfiguration,
+) (bool, error) {
+	childrenWorkloads, err := client.GetChildrenWorkloads(workload, ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get children workloads: %w", err)
+	}
+
+	scaledDownChildren := slices.DeleteFunc(childrenWorkloads, func(child scalable.Workload) bool {
+		return !scalable.IsScaledDown(child) || isKeptScaledDown(child, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes)
+	})
+
+	if len(scaledDownChildren) == 0 {
+		return false, nil
+	}
+
+	slog.Debug(
+		"scaling up children workloads first",
+		"workload", workload.GetName(),
+		"namespace", workload.GetNamespace(),
+		"childrenCount", len(scaledDownChildren),
+	)
+	scaleWorkloads(values.ScalingUp, scaledDownChildren, scopes, workloadNamespaceMetrics, auditor, client, ctx, inFlightScalings, config)
+
+	// in dry run mode the children stay scaled down, so the workload would never be scaled up
+	return !config.DryRun, nil
+}
+
+// isKeptScaledDown checks if the child is kept scaled down by its own scopes, because it is excluded or in its downtime.
+// Children whose scopes can't be parsed, e.g. because their namespace isn't scanned, follow their parent.
+func isKeptScaledDown(
+	child scalable.Workload,
+	ctx context.Context,
+	scopeDefault, scopeCli, scopeEnv *values.Scope,
+	namespaceScopes map[string]*values.Scope,
+) bool {
+	scopes, err := getWorkloadScopes(child, discardResourceLogger{}, ctx, scopeDefault, scopeCli, scopeEnv, namespaceScopes)
+	if err != nil {
+		return false
+	}
+
+	excluded := scopes.GetExcluded(scopes)
+	upscaleOnExclusion := scopes.GetUpscaleExcluded()
+
+	if excluded && !upscaleOnExclusion {
+		return true
+	}
+
+	return getCurrentScaling(child, excluded, upscaleOnExclusion, &scopes) == values.ScalingDown
+}
+
+// getWorkloadScopes gets all scopes of the workload, ordered from the most to the least specific one.
+func getWorkloadScopes(
+	workload scalable.Workload,
+	resourceLogger util.ResourceLogger,
+	ctx context.Context,
+	scopeDefault, scopeCli, scopeEnv *values.Scope,
+	namespaceScopes map[string]*values.Scope,
+) (values.Scopes, error) {
+	slog.Debug(
+		"parsing workload scope from annotations",
+		"annotations", workload.GetAnnotations(),
+		"name", workload.GetName(),
+		"namespace", workload.GetNamespace(),
+	)
+
+	scopeWorkload := values.NewScope()
+	if err := scopeWorkload.GetScopeFromAnnotations(workload.GetAnnotations(), resourceLogger, ctx); err != nil {
+		return values.Scopes{}, fmt.Errorf("failed to parse workload scope from annotations: %w", err)
+	}
+
+	scopeNamespace, exists := namespaceScopes[workload.GetNamespace()]
+	if !exists {
+		return values.Scopes{}, newNamespaceScopeRetrieveError(workload.GetNamespace())
+	}
+
+	return values.Scopes{scopeWorkload, scopeNamespace, scopeCli, scopeEnv, scopeDefault}, nil
+}
+
 func getCurrentScaling(workload scalable.Workload, excluded, upscaleOnExclusion bool, scopes *values.Scopes) values.Scaling {
 	if upscaleOnExclusion && excluded {
 		slog.Debug("upscaling excluded workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
@@ -399,19 +882,125 @@ func getCurrentScaling(workload scalable.Workload, excluded, upscaleOnExclusion
 	return scopes.GetCurrentScaling()
 }
 
+// isInMinStateDuration checks if the scaling would change the state of the workload before the minimum state duration has passed.
+// Forced scalings are never held back.
+func isInMinStateDuration(workload scalable.Workload, scaling values.Scaling, scopes values.Scopes) (bool, error) {
+	minStateDuration := scopes.GetMinStateDuration()
+	if minStateDuration <= 0 || scopes.IsScalingForced() {
+		return false, nil
+	}
+
+	isTransition := (scaling == values.ScalingDown && !scalable.IsScaledDown(workload)) ||
+		(scaling == values.ScalingUp && scalable.IsScaledDown(workload))
+	if !isTransition {
+		return false, nil
+	}
+
+	lastTransition, err := scalable.GetLastTransition(workload)
+	if err != nil {
+		return false, fmt.Errorf("failed to get last transition: %w", err)
+	}
+
+	return time.Since(lastTransition) < minStateDuration, nil
+}
+
+// isInManualOverride checks if the replicas of the scaled down workload were changed manually within the manual override duration.
+// A newly detected manual override is recorded on the workload, so it is only respected for the configured duration.
+// Forced scalings are never held back.
+func isInManualOverride(
+	workload scalable.Workload,
+	scaling values.Scaling,
+	scopes values.Scopes,
+	client kubernetes.Client,
+	resourceLogger kubernetes.ResourceLogger,
+	ctx context.Context,
+) (bool, error) {
+	manualOverrideDuration := scopes.GetManualOverrideDuration()
+	if scaling != values.ScalingDown || manualOverrideDuration <= 0 || scopes.IsScalingForced() {
+		return false, nil
+	}
+
+	overridableWorkload, ok := workload.(scalable.OverridableWorkload)
+	if !ok {
+		return false, nil
+	}
+
+	overridden, err := overridableWorkload.IsManuallyOverridden()
+	if err != nil {
+		return false, fmt.Errorf("failed to check if the workload was changed manually: %w", err)
+	}
+
+	overriddenSince, err := scalable.GetManualOverrideSince(workload)
+	if err != nil {
+		return false, fmt.Errorf("failed to get manual override: %w", err)
+	}
+
+	if !overridden {
+		if overriddenSince.IsZero() {
+			return false, nil
+		}
+
+		// the replicas were set back manually, so a later override has to be respected for the full duration again
+		err = client.SetManualOverride(workload, false, ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to clear manual override: %w", err)
+		}
+
+		return false, nil
+	}
+
+	if overriddenSince.IsZero() {
+		err = client.SetManualOverride(workload, true, ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to record manual override: %w", err)
+		}
+
+		overrideEnd := time.Now().Add(manualOverrideDuration)
+
+		slog.Info(
+			"replicas of scaled down workload were changed manually, respecting the manual override",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+			"until", overrideEnd.Format(time.RFC3339),
+		)
+		resourceLogger.InfoManualOverride(
+			fmt.Sprintf("replicas were changed manually during downtime, not scaling down until %s", overrideEnd.Format(time.RFC3339)),
+			ctx,
+		)
+
+		return true, nil
+	}
+
+	if time.Since(overriddenSince) < manualOverrideDuration {
+		slog.Debug("workload is manually overridden, skipping", "workload", workload.GetName(), "namespace", workload.GetNamespace())
+		return true, nil
+	}
+
+	slog.Info("manual override expired, scaling workload down again", "workload", workload.GetName(), "namespace", workload.GetNamespace())
+
+	return false, nil
+}
+
 // scaleWorkloads scales the given workloads to the specified scaling asynchronously.
+// The scaling operations are tracked in inFlightScalings, so they can be drained on shutdown.
 func scaleWorkloads(
 	scaling values.Scaling,
 	workloads []scalable.Workload,
 	scopes values.Scopes,
 	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
 	client kubernetes.Client,
 	ctx context.Context,
+	inFlightScalings *sync.WaitGroup,
 	config *runtimeConfiguration,
 ) {
 	for _, workload := range workloads {
+		inFlightScalings.Add(1)
+
 		go func(workload scalable.Workload) {
-			err := attemptScaling(client, ctx, scaling, workload, scopes, workloadNamespaceMetrics, config)
+			defer inFlightScalings.Done()
+
+			err := attemptScaling(client, ctx, scaling, workload, scopes, nil, workloadNamespaceMetrics, auditor, config)
 			if err != nil {
 				slog.Error("failed to scale workload", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
 			}
@@ -424,7 +1013,9 @@ func scaleWorkload(
 	scaling values.Scaling,
 	workload scalable.Workload,
 	scopes values.Scopes,
+	status *scalable.Status,
 	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
 	client kubernetes.Client,
 	ctx context.Context,
 ) error {
@@ -467,11 +1058,15 @@ setting different scaling states at the same time (e.g. downtime-period and upti
 			return fmt.Errorf("failed to get downscale replicas: %w", err)
 		}
 
-		savedResources, err := client.DownscaleWorkload(downscaleReplicas, workload, ctx)
+		before := getAuditedState(workload)
+
+		savedResources, err := client.DownscaleWorkload(downscaleReplicas, workload, status, ctx)
 		if err != nil {
 			return fmt.Errorf("failed to downscale workload: %w", err)
 		}
 
+		auditor.record(workload, audit.ActionScaleDown, before, scopes, ctx)
+
 		workloadNamespaceMetrics.IncrementDownscaledWorkloadsCount()
 		workloadNamespaceMetrics.IncrementSavedResources(savedResources)
 	}
@@ -479,11 +1074,15 @@ setting different scaling states at the same time (e.g. downtime-period and upti
 	if scaling == values.ScalingUp {
 		slog.Debug("upscaling workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
 
-		err := client.UpscaleWorkload(workload, ctx)
+		before := getAuditedState(workload)
+
+		err := client.UpscaleWorkload(workload, status, ctx)
 		if err != nil {
 			return fmt.Errorf("failed to upscale workload: %w", err)
 		}
 
+		auditor.record(workload, audit.ActionScaleUp, before, scopes, ctx)
+
 		workloadNamespaceMetrics.IncrementUpscaledWorkloadsCount()
 	}
 
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +14,9 @@ import (
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type MockClient struct {
@@ -28,13 +32,24 @@ func (m *MockClient) GetNamespaceAnnotations(namespace string, ctx context.Conte
 func (m *MockClient) DownscaleWorkload(
 	replicas values.Replicas,
 	workload scalable.Workload,
+	status *scalable.Status,
 	ctx context.Context,
 ) (*metrics.SavedResources, error) {
-	args := m.Called(replicas, workload, ctx)
+	args := m.Called(replicas, workload, status, ctx)
 	return args.Get(0).(*metrics.SavedResources), args.Error(1)
 }
 
-func (m *MockClient) UpscaleWorkload(workload scalable.Workload, ctx context.Context) error {
+func (m *MockClient) UpscaleWorkload(workload scalable.Workload, status *scalable.Status, ctx context.Context) error {
+	args := m.Called(workload, status, ctx)
+	return args.Error(0)
+}
+
+func (m *MockClient) GetChildrenWorkloads(workload scalable.Workload, ctx context.Context) ([]scalable.Workload, error) {
+	args := m.Called(workload, ctx)
+	return args.Get(0).([]scalable.Workload), args.Error(1)
+}
+
+func (m *MockClient) RegetWorkload(workload scalable.Workload, ctx context.Context) error {
 	args := m.Called(workload, ctx)
 	return args.Error(0)
 }
@@ -96,11 +111,192 @@ func TestScanWorkload(t *testing.T) {
 	mockWorkload.On("GetAnnotations").Return(map[string]string{
 		"downscaler/force-downtime": "true",
 	})
-	mockClient.On("DownscaleWorkload", values.AbsoluteReplicas(0), mockWorkload, ctx).Return(metrics.NewSavedResources(0, 0), nil)
-	err := scanWorkload(mockWorkload, mockClient, ctx, values.GetDefaultScope(), scopeCli, scopeEnv, namespaceScopes, namespaceMetrics, config)
+	mockClient.On("DownscaleWorkload", values.AbsoluteReplicas(0), mockWorkload, (*scalable.Status)(nil), ctx).
+		Return(metrics.NewSavedResources(0, 0), nil)
+	err := scanWorkload(
+		mockWorkload,
+		mockClient,
+		ctx,
+		&sync.WaitGroup{},
+		nil,
+		nil,
+		nil,
+		values.GetDefaultScope(),
+		scopeCli,
+		scopeEnv,
+		namespaceScopes,
+		namespaceMetrics,
+		nil,
+		config,
+	)
 
 	require.NoError(t, err)
 
 	mockClient.AssertExpectations(t)
 	mockWorkload.AssertExpectations(t)
 }
+
+func TestAttemptScaling_RetriesOnConflict(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	scope := values.NewScope()
+	scope.DownscaleReplicas = values.AbsoluteReplicas(0)
+
+	mockClient := new(MockClient)
+	mockWorkload := new(MockWorkload)
+
+	mockWorkload.On("GetNamespace").Return("test-namespace")
+	mockWorkload.On("GetName").Return("test-workload")
+	mockWorkload.On("GetAnnotations").Return(map[string]string{})
+
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	conflict := apierrors.NewConflict(deployments, "test-workload", errors.New("object was modified"))
+
+	// the workload was changed concurrently, so the first patch conflicts and the scaling is retried on the regot workload
+	mockClient.On("DownscaleWorkload", values.AbsoluteReplicas(0), mockWorkload, (*scalable.Status)(nil), ctx).
+		Return((*metrics.SavedResources)(nil), conflict).Once()
+	mockClient.On("RegetWorkload", mockWorkload, ctx).Return(nil).Once()
+	mockClient.On("DownscaleWorkload", values.AbsoluteReplicas(0), mockWorkload, (*scalable.Status)(nil), ctx).
+		Return(metrics.NewSavedResources(0, 0), nil).Once()
+
+	err := attemptScaling(
+		mockClient,
+		ctx,
+		values.ScalingDown,
+		mockWorkload,
+		values.Scopes{scope},
+		nil,
+		&metrics.NamespaceMetricsHolder{},
+		nil,
+		&runtimeConfiguration{MaxRetriesOnConflict: 1},
+	)
+
+	require.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "DownscaleWorkload", 2)
+}
+
+func TestDrainInFlightScalings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		operationLength time.Duration
+		timeout         time.Duration
+		wantCancelled   bool
+	}{
+		{
+			name:            "operation finishes before timeout",
+			operationLength: 10 * time.Millisecond,
+			timeout:         time.Second,
+			wantCancelled:   false,
+		},
+		{
+			name:            "operation exceeds timeout",
+			operationLength: time.Minute,
+			timeout:         10 * time.Millisecond,
+			wantCancelled:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			scalingCtx, cancelScaling := context.WithCancel(t.Context())
+			defer cancelScaling()
+
+			var inFlightScalings sync.WaitGroup
+
+			inFlightScalings.Add(1)
+
+			go func() {
+				defer inFlightScalings.Done()
+
+				select {
+				case <-scalingCtx.Done():
+				case <-time.After(test.operationLength):
+				}
+			}()
+
+			drainInFlightScalings(&inFlightScalings, cancelScaling, test.timeout)
+
+			require.Equal(t, test.wantCancelled, scalingCtx.Err() != nil)
+		})
+	}
+}
+
+func TestScaleUpChildrenFirst(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	scaledDownChild, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"scaled-down","namespace":"test-namespace",`+
+			`"annotations":{"downscaler/original-replicas":"2"}},"spec":{"replicas":0}}`,
+	))
+	require.NoError(t, err)
+
+	scaledUpChild, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"scaled-up","namespace":"test-namespace"},"spec":{"replicas":2}}`,
+	))
+	require.NoError(t, err)
+
+	excludedChild, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"excluded","namespace":"test-namespace",`+
+			`"annotations":{"downscaler/original-replicas":"2","downscaler/exclude":"true"}},"spec":{"replicas":0}}`,
+	))
+	require.NoError(t, err)
+
+	forcedDownChild, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"forced-down","namespace":"test-namespace",`+
+			`"annotations":{"downscaler/original-replicas":"2","downscaler/force-downtime":"true"}},"spec":{"replicas":0}}`,
+	))
+	require.NoError(t, err)
+
+	parent := new(MockWorkload)
+	parent.On("GetNamespace").Return("test-namespace")
+	parent.On("GetName").Return("test-application")
+
+	mockClient := new(MockClient)
+	mockClient.On("GetChildrenWorkloads", parent, ctx).
+		Return([]scalable.Workload{scaledDownChild, scaledUpChild, excludedChild, forcedDownChild}, nil).Once()
+	mockClient.On("UpscaleWorkload", scaledDownChild, (*scalable.Status)(nil), ctx).Return(nil).Once()
+
+	namespaceScopes := map[string]*values.Scope{"test-namespace": values.NewScope()}
+
+	var inFlightScalings sync.WaitGroup
+
+	scaleUp := func() (bool, error) {
+		return scaleUpChildrenFirst(
+			parent,
+			values.Scopes{},
+			values.GetDefaultScope(), values.NewScope(), values.NewScope(),
+			namespaceScopes,
+			&metrics.NamespaceMetricsHolder{},
+			nil,
+			mockClient,
+			ctx,
+			&inFlightScalings,
+			&runtimeConfiguration{},
+		)
+	}
+
+	isWaitingForChildren, err := scaleUp()
+	require.NoError(t, err)
+	require.True(t, isWaitingForChildren, "the workload mustn't be scaled up before its children")
+
+	inFlightScalings.Wait()
+
+	mockClient.On("GetChildrenWorkloads", parent, ctx).
+		Return([]scalable.Workload{scaledUpChild, excludedChild, forcedDownChild}, nil).Once()
+
+	isWaitingForChildren, err = scaleUp()
+	require.NoError(t, err)
+	require.False(t, isWaitingForChildren, "children kept scaled down by their own scopes mustn't hold back the workload")
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *MockClient) SetManualOverride(workload scalable.Workload, overridden bool, ctx context.Context) error {
+	args := m.Called(workload, overridden, ctx)
+	return args.Error(0)
+}
+
+func TestIsInManualOverride(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		replicas        int
+		overriddenSince time.Time
+		scaling         values.Scaling
+		wantOverride    bool
+		wantCleared     bool
+	}{
+		{
+			name:         "replicas unchanged",
+			replicas:     0,
+			scaling:      values.ScalingDown,
+			wantOverride: false,
+		},
+		{
+			name:            "manual override still active",
+			replicas:        2,
+			overriddenSince: time.Now().Add(-10 * time.Minute),
+			scaling:         values.ScalingDown,
+			wantOverride:    true,
+		},
+		{
+			name:            "manual override expired",
+			replicas:        2,
+			overriddenSince: time.Now().Add(-2 * time.Hour),
+			scaling:         values.ScalingDown,
+			wantOverride:    false,
+		},
+		{
+			name:            "manual override ignored when scaling up",
+			replicas:        2,
+			overriddenSince: time.Now().Add(-10 * time.Minute),
+			scaling:         values.ScalingUp,
+			wantOverride:    false,
+		},
+		{
+			name:            "manual override cleared after replicas were set back",
+			replicas:        0,
+			overriddenSince: time.Now().Add(-10 * time.Minute),
+			scaling:         values.ScalingDown,
+			wantOverride:    false,
+			wantCleared:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			annotations := `"downscaler/original-replicas":"3","downscaler/applied-replicas":"0"`
+			if !test.overriddenSince.IsZero() {
+				annotations += fmt.Sprintf(`,"downscaler/manual-override-since":%q`, test.overriddenSince.UTC().Format(time.RFC3339))
+			}
+
+			workload, err := scalable.ParseWorkloadFromRawObject("deployment", fmt.Appendf(nil,
+				`{"metadata":{"name":"test","namespace":"default","annotations":{%s}},"spec":{"replicas":%d}}`,
+				annotations, test.replicas,
+			))
+			require.NoError(t, err)
+
+			mockClient := new(MockClient)
+			if test.wantCleared {
+				mockClient.On("SetManualOverride", workload, false, mock.Anything).Return(nil)
+			}
+
+			scopeCli := values.NewScope()
+			scopeCli.ManualOverride = time.Hour
+			scopes := values.Scopes{values.NewScope(), values.NewScope(), scopeCli, values.NewScope(), values.GetDefaultScope()}
+
+			inManualOverride, err := isInManualOverride(
+				workload,
+				test.scaling,
+				scopes,
+				mockClient,
+				kubernetes.NewResourceLoggerForWorkload(mockClient, workload),
+				t.Context(),
+			)
+			require.NoError(t, err)
+			require.Equal(t, test.wantOverride, inManualOverride)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsInManualOverride_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	workload, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(
+		`{"metadata":{"name":"test","namespace":"default","annotations":{"downscaler/original-replicas":"3","downscaler/applied-replicas":"0"}},`+
+			`"spec":{"replicas":2}}`,
+	))
+	require.NoError(t, err)
+
+	mockClient := new(MockClient)
+	scopes := values.Scopes{values.NewScope(), values.NewScope(), values.NewScope(), values.NewScope(), values.GetDefaultScope()}
+
+	inManualOverride, err := isInManualOverride(
+		workload,
+		values.ScalingDown,
+		scopes,
+		mockClient,
+		kubernetes.NewResourceLoggerForWorkload(mockClient, workload),
+		t.Context(),
+	)
+	require.NoError(t, err)
+	require.False(t, inManualOverride, "manual overrides should only be respected when the detection is enabled")
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+)
+
+// namespaceBackoff keeps track of namespaces failing repeatedly and skips them with an exponential backoff.
+type namespaceBackoff struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	failures  map[string]namespaceFailure
+}
+
+// namespaceFailure holds the amount of consecutive failures of a namespace and until when it is skipped.
+type namespaceFailure struct {
+	count      int
+	retryAfter time.Time
+}
+
+// newNamespaceBackoff creates a new namespaceBackoff.
+// The first failure of a namespace is retried in the next cycle, every consecutive one doubles the delay starting at baseDelay.
+func newNamespaceBackoff(baseDelay, maxDelay time.Duration) *namespaceBackoff {
+	return &namespaceBackoff{
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		failures:  make(map[string]namespaceFailure),
+	}
+}
+
+// getBackingOff gets all namespaces which are currently skipped.
+func (n *namespaceBackoff) getBackingOff() map[string]struct{} {
+	backingOff := make(map[string]struct{})
+
+	for namespace, failure := range n.failures {
+		if time.Now().Before(failure.retryAfter) {
+			backingOff[namespace] = struct{}{}
+		}
+	}
+
+	return backingOff
+}
+
+// filterNamespaces removes the skipped namespaces from the namespaces.
+// Nil stays nil, since it targets all namespaces.
+func filterNamespaces(namespaces []string, backingOff map[string]struct{}) []string {
+	if namespaces == nil {
+		return nil
+	}
+
+	filtered := make([]string, 0, len(namespaces))
+
+	for _, namespace := range namespaces {
+		if _, skipped := backingOff[namespace]; skipped {
+			continue
+		}
+
+		filtered = append(filtered, namespace)
+	}
+
+	return filtered
+}
+
+// filterWorkloads removes the workloads in skipped namespaces from the workloads.
+func filterWorkloads(workloads []scalable.Workload, backingOff map[string]struct{}) []scalable.Workload {
+	return slices.DeleteFunc(workloads, func(workload scalable.Workload) bool {
+		_, skipped := backingOff[workload.GetNamespace()]
+		return skipped
+	})
+}
+
+// update records the failed namespaces and resets all namespaces which were scanned successfully.
+func (n *namespaceBackoff) update(
+	failedNamespaces []string,
+	backingOff map[string]struct{},
+	currentNamespaceToMetrics map[string]*metrics.NamespaceMetricsHolder,
+) {
+	// a namespace can fail multiple times per cycle, but should only count as one failure
+	slices.Sort(failedNamespaces)
+	failedNamespaces = slices.Compact(failedNamespaces)
+
+	for namespace := range n.failures {
+		if _, skipped := backingOff[namespace]; skipped || slices.Contains(failedNamespaces, namespace) {
+			continue
+		}
+
+		slog.Info("namespace recovered, no longer backing off", "namespace", namespace)
+		delete(n.failures, namespace)
+	}
+
+	for _, namespace := range failedNamespaces {
+		if currentNamespaceToMetrics != nil {
+			if _, ok := currentNamespaceToMetrics[namespace]; !ok {
+				currentNamespaceToMetrics[namespace] = metrics.NewNamespaceMetricsHolder()
+			}
+
+			currentNamespaceToMetrics[namespace].IncrementNamespaceErrorsCount()
+		}
+
+		delay := n.recordFailure(namespace)
+		if delay > 0 {
+			slog.Warn("namespace failed repeatedly, skipping it", "namespace", namespace, "duration", delay.String())
+		}
+	}
+}
+
+// recordFailure records a failure of the namespace and returns how long it is skipped for.
+func (n *namespaceBackoff) recordFailure(namespace string) time.Duration {
+	failure := n.failures[namespace]
+	failure.count++
+
+	var delay time.Duration
+
+	if failure.count > 1 {
+		delay = n.baseDelay
+		for i := 2; i < failure.count && delay < n.maxDelay; i++ {
+			delay *= 2
+		}
+
+		delay = min(delay, n.maxDelay)
+	}
+
+	failure.retryAfter = time.Now().Add(delay)
+	n.failures[namespace] = failure
+
+	return delay
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries the first failure in the next cycle", func(t *testing.T) {
+		t.Parallel()
+
+		backoff := newNamespaceBackoff(time.Hour, 10*time.Hour)
+		backoff.update([]string{"ns1", "ns1"}, backoff.getBackingOff(), nil)
+
+		require.Empty(t, backoff.getBackingOff())
+		require.Equal(t, 1, backoff.failures["ns1"].count)
+	})
+
+	t.Run("backs off exponentially on consecutive failures", func(t *testing.T) {
+		t.Parallel()
+
+		backoff := newNamespaceBackoff(time.Hour, 3*time.Hour)
+
+		require.Equal(t, time.Duration(0), backoff.recordFailure("ns1"))
+		require.Equal(t, time.Hour, backoff.recordFailure("ns1"))
+		require.Equal(t, 2*time.Hour, backoff.recordFailure("ns1"))
+		require.Equal(t, 3*time.Hour, backoff.recordFailure("ns1"))
+		require.Equal(t, map[string]struct{}{"ns1": {}}, backoff.getBackingOff())
+	})
+
+	t.Run("resets namespaces which recovered", func(t *testing.T) {
+		t.Parallel()
+
+		backoff := newNamespaceBackoff(time.Hour, 10*time.Hour)
+		backoff.update([]string{"ns1", "ns2"}, backoff.getBackingOff(), nil)
+		backoff.update([]string{"ns2"}, backoff.getBackingOff(), nil)
+
+		require.NotContains(t, backoff.failures, "ns1")
+		require.Equal(t, map[string]struct{}{"ns2": {}}, backoff.getBackingOff())
+
+		// ns2 is skipped, so it can't have recovered
+		backoff.update(nil, backoff.getBackingOff(), nil)
+		require.Contains(t, backoff.failures, "ns2")
+	})
+
+	t.Run("records the failures in the namespace metrics", func(t *testing.T) {
+		t.Parallel()
+
+		namespaceToMetrics := map[string]*metrics.NamespaceMetricsHolder{}
+
+		backoff := newNamespaceBackoff(time.Hour, 10*time.Hour)
+		backoff.update([]string{"ns1"}, backoff.getBackingOff(), namespaceToMetrics)
+
+		require.Contains(t, namespaceToMetrics, "ns1")
+		require.InDelta(t, 1.0, namespaceToMetrics["ns1"].NamespaceErrors(), 0)
+	})
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	t.Parallel()
+
+	backingOff := map[string]struct{}{"ns2": {}}
+
+	require.Nil(t, filterNamespaces(nil, backingOff))
+	require.Equal(t, []string{"ns1"}, filterNamespaces([]string{"ns1", "ns2"}, backingOff))
+	require.NotNil(t, filterNamespaces([]string{"ns2"}, backingOff))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// getPauseMode gets the current pause mode of the downscaler.
+// If it can't be fetched the previous pause mode is kept, so a paused downscaler doesn't resume on a failed request.
+func getPauseMode(client kubernetes.Client, ctx context.Context, previous values.PauseMode) values.PauseMode {
+	mode, err := client.GetPauseMode(ctx)
+	if err != nil {
+		slog.Error("failed to get pause mode, keeping the previous one", "error", err, "mode", previous.String())
+		return previous
+	}
+
+	if mode != previous {
+		slog.Info("pause mode changed", "mode", mode.String(), "previousMode", previous.String())
+	}
+
+	return mode
+}
+
+// upscalePausedWorkload scales the workload back up while the downscaler is paused with the upscale-all mode.
+// Scopes aren't evaluated, so excluded or forced down workloads are restored as well.
+func upscalePausedWorkload(
+	workload scalable.Workload,
+	client kubernetes.Client,
+	ctx context.Context,
+	node *dependencyNode,
+	verifier *upscaleVerifier,
+	workloadNamespaceMetrics *metrics.NamespaceMetricsHolder,
+	auditor *scanAuditor,
+	config *runtimeConfiguration,
+) error {
+	if !scalable.IsScaledDown(workload) {
+		slog.Debug("workload is not scaled down, skipping", "workload", workload.GetName(), "namespace", workload.GetNamespace())
+		return nil
+	}
+
+	err := node.waitForTurn(values.ScalingUp, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for related workloads: %w", err)
+	}
+
+	err = attemptScaling(client, ctx, values.ScalingUp, workload, values.Scopes{}, nil, workloadNamespaceMetrics, auditor, config)
+	if err != nil {
+		return fmt.Errorf("failed to scale workload: %w", err)
+	}
+
+	resourceLogger := kubernetes.NewResourceLoggerForWorkload(client, workload)
+
+	if config.EventVerbosity.includesScaling() && !scalable.IsScaledDown(workload) {
+		resourceLogger.InfoScaledUp("scaled up to the original replicas because the downscaler is paused with the upscale-all mode", ctx)
+	}
+
+	verifier.verify(workload, client, resourceLogger, ctx)
+
+	if node.hasDependents() && !config.DryRun {
+		err = waitUntilReady(client, workload, config.DependencyReadyTimeout, node.heartbeat, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for workload to become ready: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errPauseModeUnavailable = errors.New("pause mode unavailable")
+
+func (m *MockClient) GetPauseMode(ctx context.Context) (values.PauseMode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(values.PauseMode), args.Error(1)
+}
+
+func (m *MockClient) SetPauseMode(mode values.PauseMode, ctx context.Context) error {
+	args := m.Called(mode, ctx)
+	return args.Error(0)
+}
+
+func TestGetPauseMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     values.PauseMode
+		err      error
+		previous values.PauseMode
+		want     values.PauseMode
+	}{
+		{
+			name:     "pauses the downscaler",
+			mode:     values.PauseModeFreeze,
+			previous: values.PauseModeNone,
+			want:     values.PauseModeFreeze,
+		},
+		{
+			name:     "resumes the downscaler",
+			mode:     values.PauseModeNone,
+			previous: values.PauseModeUpscaleAll,
+			want:     values.PauseModeNone,
+		},
+		{
+			name:     "keeps the previous mode on errors",
+			mode:     values.PauseModeNone,
+			err:      errPauseModeUnavailable,
+			previous: values.PauseModeFreeze,
+			want:     values.PauseModeFreeze,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(MockClient)
+			mockClient.On("GetPauseMode", mock.Anything).Return(test.mode, test.err)
+
+			require.Equal(t, test.want, getPauseMode(mockClient, t.Context(), test.previous))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+)
+
+// resourceTypeDiscovery keeps track of which of the included resource types are served by the cluster.
+type resourceTypeDiscovery struct {
+	client                kubernetes.Client
+	includedResourceTypes []string
+	interval              time.Duration
+	activeResourceTypes   []string
+	inactiveResourceTypes []string
+	lastDiscovery         time.Time
+}
+
+// newResourceTypeDiscovery creates a new resourceTypeDiscovery for the included resource types.
+func newResourceTypeDiscovery(client kubernetes.Client, includedResourceTypes []string, interval time.Duration) *resourceTypeDiscovery {
+	return &resourceTypeDiscovery{
+		client:                client,
+		includedResourceTypes: includedResourceTypes,
+		interval:              interval,
+	}
+}
+
+// getActiveResourceTypes gets the included resource types which are served by the cluster.
+// The served resource types are only re-discovered once the discovery interval has passed.
+// If the discovery fails the previous result is used, so a flaky API server doesn't stop the scan.
+func (r *resourceTypeDiscovery) getActiveResourceTypes(downscalerMetrics *metrics.Metrics, metricsEnabled bool) ([]string, error) {
+	if r.activeResourceTypes != nil && time.Since(r.lastDiscovery) < r.interval {
+		return r.activeResourceTypes, nil
+	}
+
+	active, inactive, err := r.client.GetServedResourceTypes(r.includedResourceTypes)
+	if err != nil {
+		var invalidResourceErr *scalable.InvalidResourceError
+		if errors.As(err, &invalidResourceErr) {
+			return nil, fmt.Errorf("failed to discover served resource types: %w", err)
+		}
+
+		if r.activeResourceTypes == nil {
+			slog.Warn("failed to discover served resource types, trying all included resource types", "error", err)
+			return r.includedResourceTypes, nil
+		}
+
+		slog.Warn("failed to discover served resource types, using the previously discovered ones", "error", err)
+
+		return r.activeResourceTypes, nil
+	}
+
+	for _, resourceType := range inactive {
+		if slices.Contains(r.inactiveResourceTypes, resourceType) {
+			continue
+		}
+
+		slog.Warn("resource type is not served by the cluster, e.g. because its CRD isn't installed, skipping it until it becomes available",
+			"resourceType", resourceType)
+	}
+
+	for _, resourceType := range active {
+		if !slices.Contains(r.inactiveResourceTypes, resourceType) {
+			continue
+		}
+
+		slog.Info("resource type is now served by the cluster, including it again", "resourceType", resourceType)
+	}
+
+	r.activeResourceTypes = active
+	r.inactiveResourceTypes = inactive
+	r.lastDiscovery = time.Now()
+
+	downscalerMetrics.UpdateActiveResourceTypes(metricsEnabled, active, inactive)
+
+	return r.activeResourceTypes, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errDiscoveryFailed = errors.New("discovery failed")
+
+func (m *MockClient) GetServedResourceTypes(resourceTypes []string) ([]string, []string, error) {
+	args := m.Called(resourceTypes)
+	return args.Get(0).([]string), args.Get(1).([]string), args.Error(2)
+}
+
+func TestResourceTypeDiscovery(t *testing.T) {
+	t.Parallel()
+
+	includedResourceTypes := []string{"deployments", "rollouts"}
+
+	t.Run("skips resource types which aren't served", func(t *testing.T) {
+		t.Parallel()
+
+		mockClient := new(MockClient)
+		mockClient.On("GetServedResourceTypes", includedResourceTypes).Return([]string{"deployments"}, []string{"rollouts"}, nil).Once()
+
+		discovery := newResourceTypeDiscovery(mockClient, includedResourceTypes, time.Hour)
+
+		active, err := discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"deployments"}, active)
+
+		// the interval hasn't passed yet, so the cached result should be used
+		active, err = discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"deployments"}, active)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("picks up newly served resource types after the interval", func(t *testing.T) {
+		t.Parallel()
+
+		mockClient := new(MockClient)
+		mockClient.On("GetServedResourceTypes", includedResourceTypes).Return([]string{"deployments"}, []string{"rollouts"}, nil).Once()
+		mockClient.On("GetServedResourceTypes", includedResourceTypes).Return(includedResourceTypes, []string{}, nil).Once()
+
+		discovery := newResourceTypeDiscovery(mockClient, includedResourceTypes, 0)
+
+		active, err := discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"deployments"}, active)
+
+		active, err = discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+		require.Equal(t, includedResourceTypes, active)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("falls back to previous result when discovery fails", func(t *testing.T) {
+		t.Parallel()
+
+		mockClient := new(MockClient)
+		mockClient.On("GetServedResourceTypes", includedResourceTypes).Return([]string{"deployments"}, []string{"rollouts"}, nil).Once()
+		mockClient.On("GetServedResourceTypes", includedResourceTypes).Return([]string(nil), []string(nil), errDiscoveryFailed).Once()
+
+		discovery := newResourceTypeDiscovery(mockClient, includedResourceTypes, 0)
+
+		_, err := discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+
+		active, err := discovery.getActiveResourceTypes(nil, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"deployments"}, active)
+
+		mockClient.AssertExpectations(t)
+	})
+}
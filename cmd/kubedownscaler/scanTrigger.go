@@ -0,0 +1,69 @@
+package main
+
+import (
+	"slices"
+	"sync"
+)
+
+// scanTrigger collects requests for immediate scans until the scan loop picks them up.
+type scanTrigger struct {
+	mutex      sync.Mutex
+	full       bool
+	namespaces map[string]struct{}
+	notify     chan struct{}
+}
+
+// newScanTrigger creates a new scanTrigger without any pending requests.
+func newScanTrigger() *scanTrigger {
+	return &scanTrigger{
+		namespaces: make(map[string]struct{}),
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// requestScan requests an immediate scan of the namespace. An empty namespace requests a scan of all namespaces.
+func (s *scanTrigger) requestScan(namespace string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if namespace == "" {
+		s.full = true
+	} else {
+		s.namespaces[namespace] = struct{}{}
+	}
+
+	select {
+	case s.notify <- struct{}{}:
+	default: // a notification is already pending
+	}
+}
+
+// notified gets a channel which receives a value once a scan was requested.
+func (s *scanTrigger) notified() <-chan struct{} {
+	return s.notify
+}
+
+// take gets and clears all pending requests.
+// It returns the namespaces to scan and if the scan covers all included namespaces.
+func (s *scanTrigger) take(includeNamespaces []string) ([]string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	full := s.full
+	namespaces := make([]string, 0, len(s.namespaces))
+
+	for namespace := range s.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+
+	s.full = false
+	clear(s.namespaces)
+
+	if full || len(namespaces) == 0 {
+		return includeNamespaces, true
+	}
+
+	slices.Sort(namespaces)
+
+	return namespaces, false
+}
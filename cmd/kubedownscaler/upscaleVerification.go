@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+)
+
+// upscaleVerifier watches upscaled workloads in the background, so scan cycles don't wait for them to become ready.
+// The verifications are tracked in inFlightScalings, so they can be drained on shutdown.
+// Their results are collected per namespace until they are added to the metrics of the next finished scan cycle.
+type upscaleVerifier struct {
+	mutex            sync.Mutex
+	results          map[string]*metrics.NamespaceMetricsHolder
+	inFlightScalings *sync.WaitGroup
+	timeout          time.Duration
+	retries          int
+	metricsEnabled   bool
+}
+
+// newUpscaleVerifier creates a new upscaleVerifier. It is nil if the upscale verification is disabled.
+func newUpscaleVerifier(inFlightScalings *sync.WaitGroup, config *runtimeConfiguration) *upscaleVerifier {
+	if config.UpscaleVerificationTimeout <= 0 || config.DryRun {
+		return nil
+	}
+
+	return &upscaleVerifier{
+		results:          make(map[string]*metrics.NamespaceMetricsHolder),
+		inFlightScalings: inFlightScalings,
+		timeout:          config.UpscaleVerificationTimeout,
+		retries:          config.UpscaleVerificationRetries,
+		metricsEnabled:   config.MetricsEnabled,
+	}
+}
+
+// verify starts watching the upscaled workload until it becomes ready.
+// Workloads which can't report their readiness are skipped, as they can't be verified.
+func (v *upscaleVerifier) verify(
+	workload scalable.Workload,
+	client kubernetes.Client,
+	resourceLogger kubernetes.ResourceLogger,
+	ctx context.Context,
+) {
+	if v == nil {
+		return
+	}
+
+	if !scalable.CanReportReadiness(workload) {
+		slog.Debug("workload can't report its readiness, skipping upscale verification",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+		)
+
+		return
+	}
+
+	v.inFlightScalings.Add(1)
+
+	go func() {
+		defer v.inFlightScalings.Done()
+
+		v.verifyUpscale(workload, client, resourceLogger, ctx)
+	}()
+}
+
+// verifyUpscale waits until the upscaled workload becomes ready, watching it again for each retry if it doesn't.
+// The result is reported as an event on the workload and in the upscale verification metrics.
+func (v *upscaleVerifier) verifyUpscale(
+	workload scalable.Workload,
+	client kubernetes.Client,
+	resourceLogger kubernetes.ResourceLogger,
+	ctx context.Context,
+) {
+	start := time.Now()
+
+	var err error
+
+	for attempt := range v.retries + 1 {
+		err = waitUntilReady(client, workload, v.timeout, nil, ctx)
+		if ctx.Err() != nil {
+			slog.Debug("stopped verifying upscale of workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
+			return
+		}
+
+		if err == nil || attempt == v.retries {
+			break
+		}
+
+		slog.Warn("upscaled workload isn't ready yet, watching it again",
+			"error", err,
+			"attempt", attempt+1,
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+		)
+		resourceLogger.WarnUpscaleNotReady(
+			fmt.Sprintf("the workload isn't ready %s after being scaled up, watching it again", time.Since(start).Round(time.Second)),
+			ctx,
+		)
+	}
+
+	if err == nil {
+		slog.Info(
+			"upscaled workload became ready",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+			"duration", time.Since(start).Round(time.Second).String(),
+		)
+		resourceLogger.InfoUpscaleSucceeded("the workload became ready after being scaled up", ctx)
+		v.addResult(workload.GetNamespace(), (*metrics.NamespaceMetricsHolder).IncrementSucceededUpscalesCount)
+
+		return
+	}
+
+	message := fmt.Sprintf("the workload failed to become ready after being scaled up: %s", err.Error())
+
+	var notReadyErr *DependencyNotReadyError
+	if errors.As(err, &notReadyErr) {
+		message = fmt.Sprintf("the workload did not become ready within %s after being scaled up", time.Since(start).Round(time.Second))
+	}
+
+	slog.Warn("upscaled workload didn't become ready", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+	resourceLogger.ErrorUpscaleFailed(message, ctx)
+	v.addResult(workload.GetNamespace(), (*metrics.NamespaceMetricsHolder).IncrementFailedUpscalesCount)
+}
+
+// addResult records the result of a verification in the namespace until it is collected.
+func (v *upscaleVerifier) addResult(namespace string, increment func(*metrics.NamespaceMetricsHolder)) {
+	if !v.metricsEnabled {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	results, exists := v.results[namespace]
+	if !exists {
+		results = metrics.NewNamespaceMetricsHolder()
+		v.results[namespace] = results
+	}
+
+	increment(results)
+}
+
+// collectResults adds the results of all finished verifications to the metrics of their namespace.
+// Results of namespaces which weren't scanned are dropped, as their metrics are removed anyway.
+func (v *upscaleVerifier) collectResults(namespaceToMetrics map[string]*metrics.NamespaceMetricsHolder) {
+	if v == nil {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for namespace, results := range v.results {
+		namespaceToMetrics[namespace].AddUpscaleVerifications(results)
+	}
+
+	clear(v.results)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpscaleVerifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("is disabled without a timeout", func(t *testing.T) {
+		t.Parallel()
+
+		config := getDefaultConfig()
+
+		require.Nil(t, newUpscaleVerifier(&sync.WaitGroup{}, config))
+	})
+
+	t.Run("adds the results to the metrics of the next cycle", func(t *testing.T) {
+		t.Parallel()
+
+		config := getDefaultConfig()
+		config.UpscaleVerificationTimeout = time.Minute
+		config.MetricsEnabled = true
+		verifier := newUpscaleVerifier(&sync.WaitGroup{}, config)
+
+		verifier.addResult("ns1", (*metrics.NamespaceMetricsHolder).IncrementSucceededUpscalesCount)
+		verifier.addResult("ns1", (*metrics.NamespaceMetricsHolder).IncrementFailedUpscalesCount)
+		verifier.addResult("removed", (*metrics.NamespaceMetricsHolder).IncrementFailedUpscalesCount)
+
+		namespaceToMetrics := map[string]*metrics.NamespaceMetricsHolder{"ns1": metrics.NewNamespaceMetricsHolder()}
+		verifier.collectResults(namespaceToMetrics)
+
+		require.InDelta(t, 1, namespaceToMetrics["ns1"].SucceededUpscales(), 0)
+		require.InDelta(t, 1, namespaceToMetrics["ns1"].FailedUpscales(), 0)
+		require.Empty(t, verifier.results)
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+)
+
+// getScheduleStatus gets the status of a workload scaled by the scopes, containing the deciding scope and the next transition.
+// Excluded workloads which are scaled up aren't scaled by their schedule, so their status doesn't contain either.
+func getScheduleStatus(upscaledOnExclusion bool, scopes values.Scopes) scalable.Status {
+	var status scalable.Status
+	if upscaledOnExclusion {
+		return status
+	}
+
+	if scopeID, found := scopes.GetScalingScope(); found {
+		status.Scope = scopeID.Name()
+	}
+
+	if nextTransition, found := scopes.GetNextScalingChange(); found {
+		status.NextTransition = &nextTransition
+	}
+
+	return status
+}
+
+// updateWorkloadStatus updates the status annotation of the workload if status annotations are enabled.
+// The status is only informational, so failing to update it doesn't fail the scan of the workload.
+func updateWorkloadStatus(
+	workload scalable.Workload,
+	status scalable.Status,
+	client kubernetes.Client,
+	ctx context.Context,
+	config *runtimeConfiguration,
+) {
+	if !config.StatusAnnotation {
+		return
+	}
+
+	err := client.UpdateWorkloadStatus(workload, status, ctx)
+	if err != nil {
+		slog.Error("failed to update status of workload", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+	}
+}
+
+// recordScanError adds the error of a failed scan to the status of the workload, keeping the rest of its previous status.
+func recordScanError(
+	workload scalable.Workload,
+	scanErr error,
+	client kubernetes.Client,
+	ctx context.Context,
+	config *runtimeConfiguration,
+) {
+	if !config.StatusAnnotation || ctx.Err() != nil {
+		return
+	}
+
+	status, err := scalable.GetStatus(workload)
+	if err != nil {
+		slog.Warn("failed to get previous status of workload, replacing it", "error", err, "workload", workload.GetName())
+	}
+
+	status.LastError = scanErr.Error()
+	updateWorkloadStatus(workload, status, client, ctx, config)
+}
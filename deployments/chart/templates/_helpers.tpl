@@ -175,6 +175,55 @@ Create defined permissions for the webhook clusterrole
     - update
 {{- end }}
 
+{{- define "go-kube-downscaler.webhookController.autoscalers.permissions" -}}
+- apiGroups:
+    - autoscaling
+  resources:
+    - horizontalpodautoscalers
+  verbs:
+    - list
+{{- end }}
+
+{{- define "go-kube-downscaler.customresourcedefinitions.permissions" -}}
+{{- range $resource := .Values.includedResources }}
+{{- if contains "." $resource }}
+- apiGroups:
+    - apiextensions.k8s.io
+  resources:
+    - customresourcedefinitions
+  verbs:
+    - get
+{{- break }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Create permissions for the state store
+*/}}
+{{- define "go-kube-downscaler.statestore.permissions" -}}
+{{- if eq .Values.stateStore "configmap" }}
+- apiGroups:
+    - ""
+  resources:
+    - configmaps
+  verbs:
+    - get
+    - create
+    - update
+{{- end }}
+{{- if eq .Values.stateStore "crd" }}
+- apiGroups:
+    - kube-downscaler.k8s
+  resources:
+    - downscalerstates
+  verbs:
+    - get
+    - create
+    - update
+{{- end }}
+{{- end }}
+
 {{/*
 Create defined permissions for roles
 */}}
@@ -185,6 +234,7 @@ Create defined permissions for roles
     - namespaces
   verbs:
     - get
+    - patch
 - apiGroups:
     - ""
   resources:
@@ -193,6 +243,13 @@ Create defined permissions for roles
     - get
     - create
     - update
+- apiGroups:
+    - autoscaling
+  resources:
+    - horizontalpodautoscalers
+  verbs:
+    - list
+{{- include "go-kube-downscaler.statestore.permissions" . }}
 {{- range $resource := .Values.includedResources }}
 {{- if eq $resource "deployments" }}
 - apiGroups:
@@ -202,7 +259,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "statefulsets" }}
 - apiGroups:
@@ -212,7 +269,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "daemonsets" }}
 - apiGroups:
@@ -222,7 +279,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "rollouts" }}
 - apiGroups:
@@ -232,7 +289,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "horizontalpodautoscalers" }}
 - apiGroups:
@@ -242,7 +299,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "jobs" }}
 - apiGroups:
@@ -252,7 +309,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "cronjobs" }}
 - apiGroups:
@@ -265,7 +322,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "scaledobjects" }}
 - apiGroups:
@@ -275,7 +332,17 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
+{{- end }}
+{{- if eq $resource "scaledjobs" }}
+- apiGroups:
+    - keda.sh
+  resources:
+    - scaledjobs
+  verbs:
+    - get
+    - list
+    - patch
 {{- end }}
 {{- if eq $resource "stacks" }}
 - apiGroups:
@@ -285,7 +352,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "prometheuses" }}
 - apiGroups:
@@ -295,7 +362,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "poddisruptionbudgets" }}
 - apiGroups:
@@ -305,7 +372,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "autoscalingrunnersets" }}
 - apiGroups:
@@ -315,7 +382,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if or (eq $resource "services") (eq $resource "awselbservices") (eq $resource "awsnlbservices")}}
 - apiGroups:
@@ -325,7 +392,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "ingresses"}}
 - apiGroups:
@@ -335,7 +402,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "gateways"}}
 - apiGroups:
@@ -345,7 +412,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "postgresqls" }}
 - apiGroups:
@@ -355,7 +422,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "kafkaconnects" }}
 - apiGroups:
@@ -365,7 +432,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "kafkamirrormaker2s" }}
 - apiGroups:
@@ -375,7 +442,7 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
 {{- end }}
 {{- if eq $resource "kafkabridges" }}
 - apiGroups:
@@ -385,7 +452,91 @@ Create defined permissions for roles
   verbs:
     - get
     - list
-    - update
+    - patch
+{{- end }}
+{{- if or (eq $resource "knativeservices") (eq $resource "knativerevisions") }}
+- apiGroups:
+    - serving.knative.dev
+  resources:
+    - {{ trimPrefix "knative" $resource }}
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if eq $resource "helmreleases" }}
+- apiGroups:
+    - helm.toolkit.fluxcd.io
+  resources:
+    - helmreleases
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if eq $resource "kustomizations" }}
+- apiGroups:
+    - kustomize.toolkit.fluxcd.io
+  resources:
+    - kustomizations
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if eq $resource "applications" }}
+- apiGroups:
+    - argoproj.io
+  resources:
+    - applications
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if eq $resource "cnpgclusters" }}
+- apiGroups:
+    - postgresql.cnpg.io
+  resources:
+    - clusters
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if eq $resource "virtualmachines" }}
+- apiGroups:
+    - kubevirt.io
+  resources:
+    - virtualmachines
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- if contains "." $resource }}
+{{- $groupResource := splitn "." 2 $resource }}
+- apiGroups:
+    - {{ $groupResource._1 }}
+  resources:
+    - {{ $groupResource._0 }}
+    - {{ $groupResource._0 }}/scale
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
+{{- range $customResource := $.Values.customResources }}
+{{- if eq $resource $customResource.name }}
+- apiGroups:
+    - {{ $customResource.group | quote }}
+  resources:
+    - {{ $customResource.resource }}
+  verbs:
+    - get
+    - list
+    - patch
+{{- end }}
 {{- end }}
 {{- end }}
 {{- end }}
@@ -486,6 +637,17 @@ Create webhook resources
   resources:
     - scaledobjects
 {{ end -}}
+{{ if eq $resource "scaledjobs" -}}
+- apiGroups:
+    - keda.sh
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - scaledjobs
+{{ end -}}
 {{ if eq $resource "stacks" -}}
 - apiGroups:
     - zalando.org
@@ -613,6 +775,97 @@ Create webhook resources
   resources:
     - kafkabridges
 {{ end -}}
+{{ if or (eq $resource "knativeservices") (eq $resource "knativerevisions") -}}
+- apiGroups:
+    - serving.knative.dev
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - {{ trimPrefix "knative" $resource }}
+{{ end -}}
+{{ if eq $resource "helmreleases" -}}
+- apiGroups:
+    - helm.toolkit.fluxcd.io
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - helmreleases
+{{ end -}}
+{{ if eq $resource "kustomizations" -}}
+- apiGroups:
+    - kustomize.toolkit.fluxcd.io
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - kustomizations
+{{ end -}}
+{{ if eq $resource "applications" -}}
+- apiGroups:
+    - argoproj.io
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - applications
+{{ end -}}
+{{ if eq $resource "cnpgclusters" -}}
+- apiGroups:
+    - postgresql.cnpg.io
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - clusters
+{{ end -}}
+{{ if eq $resource "virtualmachines" -}}
+- apiGroups:
+    - kubevirt.io
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - virtualmachines
+{{ end -}}
+{{ if contains "." $resource -}}
+{{- $groupResource := splitn "." 2 $resource -}}
+- apiGroups:
+    - {{ $groupResource._1 }}
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - {{ $groupResource._0 }}
+{{ end -}}
+{{ range $customResource := $.Values.customResources -}}
+{{ if eq $resource $customResource.name -}}
+- apiGroups:
+    - {{ $customResource.group | quote }}
+  apiVersions:
+    - "*"
+  operations:
+    - "CREATE"
+    - "UPDATE"
+  resources:
+    - {{ $customResource.resource }}
+{{ end -}}
+{{ end -}}
 {{ end -}}
 {{- end }}
 
@@ -730,6 +983,19 @@ resources include in annotationsCompliance
   resources:
     - scaledobjects
 {{ end -}}
+{{ if eq $resource "scaledjobs" -}}
+- apiGroups:
+    - keda.sh
+  apiVersions:
+    - "*"
+  operations:
+  {{- if $createUpdate }}
+    - "CREATE"
+  {{- end }}
+    - "UPDATE"
+  resources:
+    - scaledjobs
+{{ end -}}
 {{ if eq $resource "stacks" -}}
 - apiGroups:
     - zalando.org
@@ -4,17 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/caas-team/gokubedownscaler/internal/api/kubernetes"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/audit"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // WorkloadMutationHandler is a struct that implements the admissionHandler interface.
@@ -31,6 +35,7 @@ type WorkloadMutationHandler struct {
 	includeResourcesSet map[string]struct{}
 	metricsEnabled      bool
 	admissionMetrics    *metrics.AdmissionMetrics
+	auditSink           audit.Sink
 }
 
 // NewWorkloadMutationHandler creates a new WorkloadMutationHandler.
@@ -43,6 +48,7 @@ func NewWorkloadMutationHandler(
 	includeResources map[string]struct{},
 	metricsEnabled bool,
 	admissionMetrics *metrics.AdmissionMetrics,
+	auditSink audit.Sink,
 ) *WorkloadMutationHandler {
 	return &WorkloadMutationHandler{
 		client:              client,
@@ -57,6 +63,7 @@ func NewWorkloadMutationHandler(
 		includeResourcesSet: includeResources,
 		metricsEnabled:      metricsEnabled,
 		admissionMetrics:    admissionMetrics,
+		auditSink:           auditSink,
 	}
 }
 
@@ -70,7 +77,7 @@ func (v *WorkloadMutationHandler) HandleWorkloadMutation(ctx context.Context, wr
 		return
 	}
 
-	workload, err := scalable.ParseWorkloadFromRawObject(strings.ToLower(input.Request.Kind.Kind), input.Request.Object.Raw)
+	workload, err := v.parseWorkload(input.Request, ctx)
 	if err != nil {
 		slog.Error("error encountered while parsing the workload", "error", err)
 
@@ -105,6 +112,45 @@ func (v *WorkloadMutationHandler) HandleWorkloadMutation(ctx context.Context, wr
 	sendAdmissionReviewResponse(writer, out)
 }
 
+// parseWorkload parses the workload of the admission request.
+// Declared custom resources are parsed with their path, while included resource types given as "<resource>.<group>"
+// are parsed as custom resources scaled through their scale subresource.
+//
+//nolint:ireturn // this function should return an interface type
+func (v *WorkloadMutationHandler) parseWorkload(request *admissionv1.AdmissionRequest, ctx context.Context) (scalable.Workload, error) {
+	resource := schema.GroupVersionResource(request.Resource)
+
+	if name, found := scalable.GetCustomResourceName(resource.GroupResource()); found {
+		workload, err := scalable.ParseCustomResourceWorkloadFromRawObject(name, request.Object.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse custom resource: %w", err)
+		}
+
+		return workload, nil
+	}
+
+	if _, included := v.includeResourcesSet[strings.ToLower(resource.GroupResource().String())]; included && resource.Group != "" {
+		workload, err := v.client.ParseScaleSubresourceWorkload(resource, request.Object.Raw, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse custom resource: %w", err)
+		}
+
+		return workload, nil
+	}
+
+	// the kinds of Knative resources collide with built-in kinds, so they are parsed by their resource instead
+	if resource.Group == scalable.KnativeServingGroup {
+		return scalable.ParseKnativeWorkloadFromRawObject(resource.Resource, request.Object.Raw) //nolint:wrapcheck // already wrapped
+	}
+
+	// the Cluster kind of CloudNativePG collides with the kinds of other operators, so it is parsed by its group instead
+	if resource.Group == scalable.CNPGGroup {
+		return scalable.ParseCNPGWorkloadFromRawObject(resource.Resource, request.Object.Raw) //nolint:wrapcheck // already wrapped
+	}
+
+	return scalable.ParseWorkloadFromRawObject(strings.ToLower(request.Kind.Kind), request.Object.Raw) //nolint:wrapcheck // already wrapped
+}
+
 // evaluateWorkloadMutation validates the workload and returns an AdmissionReview.
 func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 	ctx context.Context,
@@ -116,6 +162,46 @@ func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 
 	slog.Info("evaluating mutation on workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
 
+	// check if the downscaler is paused
+	slog.Debug("checking pause mode")
+
+	pauseMode, err := v.client.GetPauseMode(ctx)
+	if err != nil {
+		slog.Error("failed to get pause mode", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+
+		v.admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, true, workload.GetNamespace())
+
+		return newReviewResponse(
+			review.Request.UID,
+			true,
+			http.StatusAccepted,
+			"failed to get pause mode of the downscaler",
+			true,
+			v.dryRun,
+		), err
+	}
+
+	if pauseMode.IsPaused() {
+		slog.Info(
+			"downscaler is paused, not mutating workload",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+			"mode", pauseMode.String(),
+			"dryRun", v.dryRun,
+		)
+
+		v.admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, false, workload.GetNamespace())
+
+		return newReviewResponse(
+			review.Request.UID,
+			true,
+			http.StatusAccepted,
+			"downscaler is paused, doesn't need mutation",
+			false,
+			v.dryRun,
+		), nil
+	}
+
 	// check if namespace is included
 	slog.Debug("checking included namespaces")
 
@@ -145,7 +231,7 @@ func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 
 	externalScalingReview, err := v.evaluateWorkloadExternalScalingCondition(ctx, workload, *review)
 	if !errors.Is(err, ErrNoExternalScaling) {
-		slog.Info("workload is controlled by keda scaledobjects, excluding it")
+		slog.Info("workload is controlled by keda scaledobjects or horizontalpodautoscalers, excluding it")
 		v.admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, false, workload.GetNamespace())
 
 		return externalScalingReview, err
@@ -153,7 +239,7 @@ func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 
 	slog.Debug("checking labels, excluded namespaces and excluded workloads")
 
-	workloads := scalable.FilterExcluded(workloadArray, *v.includeLabels, *v.excludeNamespaces, *v.excludeWorkloads, nil)
+	workloads := scalable.FilterExcluded(workloadArray, nil, *v.includeLabels, *v.excludeNamespaces, *v.excludeWorkloads, nil)
 
 	if len(workloads) == 0 {
 		slog.Info(
@@ -261,7 +347,36 @@ func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 
 	scaling := scopes.GetCurrentScaling()
 
-	response, err := evaluateWorkloadScalingConditions(scaling, workload, scopes, review, v.dryRun, metricsEnabled, v.admissionMetrics)
+	if scaling == values.ScalingDown {
+		err = v.client.LoadAdmittedWorkloadState(workload, ctx)
+		if err != nil {
+			slog.Error("failed to load stored state of workload", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+
+			v.admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, true, workload.GetNamespace())
+
+			return newReviewResponse(
+				review.Request.UID,
+				true,
+				http.StatusAccepted,
+				"failed to load stored state of workload",
+				true,
+				v.dryRun,
+			), err
+		}
+	}
+
+	response, err := evaluateWorkloadScalingConditions(
+		v.client,
+		scaling,
+		workload,
+		scopes,
+		review,
+		v.dryRun,
+		metricsEnabled,
+		v.admissionMetrics,
+		v.auditSink,
+		ctx,
+	)
 	if err != nil {
 		return response, err
 	}
@@ -271,6 +386,7 @@ func (v *WorkloadMutationHandler) evaluateWorkloadMutation(
 
 // evaluateWorkloadScalingConditions scales the given workload according to the given wanted scaling state.
 func evaluateWorkloadScalingConditions(
+	client kubernetes.Client,
 	scaling values.Scaling,
 	workload scalable.Workload,
 	scopes values.Scopes,
@@ -278,6 +394,8 @@ func evaluateWorkloadScalingConditions(
 	dryRun bool,
 	metricsEnabled bool,
 	admissionMetrics *metrics.AdmissionMetrics,
+	auditSink audit.Sink,
+	ctx context.Context,
 ) (*admissionv1.AdmissionReview, error) {
 	if scaling == values.ScalingNone {
 		slog.Debug(
@@ -391,7 +509,23 @@ setting different scaling states at the same time (e.g. downtime-period and upti
 			), err
 		}
 
-		response, err := mutateWorkload(workload, review, downscaleReplicas, dryRun, metricsEnabled, admissionMetrics)
+		var decidingScope string
+		if scopeID, found := scopes.GetScalingScope(); found {
+			decidingScope = scopeID.Name()
+		}
+
+		response, err := mutateWorkload(
+			client,
+			workload,
+			review,
+			downscaleReplicas,
+			decidingScope,
+			dryRun,
+			metricsEnabled,
+			admissionMetrics,
+			auditSink,
+			ctx,
+		)
 		if err != nil {
 			return response, err
 		}
@@ -430,20 +564,46 @@ setting different scaling states at the same time (e.g. downtime-period and upti
 	), nil
 }
 
-// evaluateWorkloadExternalScalingCondition checks if the workload is externally managed.
+// evaluateWorkloadExternalScalingCondition checks if the workload is externally managed
+// by a Keda ScaledObject or a HorizontalPodAutoscaler.
 func (v *WorkloadMutationHandler) evaluateWorkloadExternalScalingCondition(
 	ctx context.Context,
 	workload scalable.Workload,
 	review admissionv1.AdmissionReview,
 ) (*admissionv1.AdmissionReview, error) {
-	if _, ok := v.includeResourcesSet["scaledobjects"]; !ok {
-		return nil, ErrNoExternalScaling
+	_, scaledObjectsIncluded := v.includeResourcesSet["scaledobjects"]
+
+	var autoscalers []scalable.Workload
+
+	if scaledObjectsIncluded {
+		scaledObjects, err := v.client.GetScaledObjects(workload.GetNamespace(), ctx)
+		if err != nil {
+			slog.Error(
+				"failed to get scaledobjects from namespace",
+				"error", err,
+				"namespace", workload.GetNamespace(),
+				"workload", workload.GetName(),
+				"dryRun", v.dryRun,
+			)
+
+			return newReviewResponse(
+				review.Request.UID,
+				true,
+				http.StatusAccepted,
+				"failed to get scaledobjects from namespace to evaluate external scaling condition",
+				true,
+				v.dryRun,
+			), err
+		}
+
+		autoscalers = append(autoscalers, scaledObjects...)
 	}
 
-	scaledObjects, err := v.client.GetScaledObjects(workload.GetNamespace(), ctx)
+	// horizontalpodautoscalers are always checked, so the workloads they manage are detected even if they aren't included
+	hpas, err := v.client.GetHorizontalPodAutoscalers(workload.GetNamespace(), ctx)
 	if err != nil {
 		slog.Error(
-			"failed to get scaledobjects from namespace",
+			"failed to get horizontalpodautoscalers from namespace",
 			"error", err,
 			"namespace", workload.GetNamespace(),
 			"workload", workload.GetName(),
@@ -454,13 +614,15 @@ func (v *WorkloadMutationHandler) evaluateWorkloadExternalScalingCondition(
 			review.Request.UID,
 			true,
 			http.StatusAccepted,
-			"failed to get scaledobjects from namespace to evaluate external scaling condition",
+			"failed to get horizontalpodautoscalers from namespace to evaluate external scaling condition",
 			true,
 			v.dryRun,
 		), err
 	}
 
-	if scalable.IsWorkloadExternallyManaged(workload, scaledObjects) {
+	autoscalers = append(autoscalers, hpas...)
+
+	if scalable.IsWorkloadExternallyManaged(workload, autoscalers) {
 		return newReviewResponse(
 			review.Request.UID,
 			true,
@@ -475,13 +637,18 @@ func (v *WorkloadMutationHandler) evaluateWorkloadExternalScalingCondition(
 }
 
 // mutateWorkload mutates the workload by scaling it down based on the scopes.
+// Mutations which change the workload are recorded in the audit sink.
 func mutateWorkload(
+	client kubernetes.Client,
 	workload scalable.Workload,
 	review *admissionv1.AdmissionReview,
 	downscaleReplicas values.Replicas,
+	decidingScope string,
 	dryRun bool,
 	metricsEnabled bool,
 	admissionMetrics *metrics.AdmissionMetrics,
+	auditSink audit.Sink,
+	ctx context.Context,
 ) (*admissionv1.AdmissionReview, error) {
 	// generate a deep copy of the workload to be able to generate a comparison patch
 	workloadCopy, err := workload.Copy()
@@ -518,6 +685,22 @@ func mutateWorkload(
 		), err
 	}
 
+	if !dryRun {
+		err = client.StoreAdmittedWorkloadState(workload, workloadCopy, ctx)
+		if err != nil {
+			admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, true, workload.GetNamespace())
+
+			return newReviewResponse(
+				review.Request.UID,
+				true,
+				http.StatusAccepted,
+				"failed to store state of workload",
+				true,
+				dryRun,
+			), err
+		}
+	}
+
 	patch, err := workload.Compare(workloadCopy)
 	if err != nil {
 		admissionMetrics.UpdateValidateWorkloadAdmissionRequestsTotal(metricsEnabled, false, true, workload.GetNamespace())
@@ -534,6 +717,10 @@ func mutateWorkload(
 
 	slog.Debug("comparison patch correctly generated", "patch", patch.String())
 
+	if len(patch) != 0 {
+		writeAuditRecord(auditSink, workload, workloadCopy, review, decidingScope, dryRun, ctx)
+	}
+
 	// convert the patch into JSON format
 	jsonPatch, err := json.Marshal(patch)
 	if err != nil {
@@ -565,3 +752,43 @@ func mutateWorkload(
 		dryRun,
 	), nil
 }
+
+// writeAuditRecord writes a record of the workload scaled down by the admission request to the audit sink.
+// Failing to write the record doesn't fail the admission request.
+func writeAuditRecord(
+	auditSink audit.Sink,
+	workload, scaledWorkload scalable.Workload,
+	review *admissionv1.AdmissionReview,
+	decidingScope string,
+	dryRun bool,
+	ctx context.Context,
+) {
+	if auditSink == nil {
+		return
+	}
+
+	record := audit.Record{
+		Time:      time.Now(),
+		CycleID:   string(review.Request.UID),
+		Source:    audit.SourceAdmission,
+		Kind:      workload.GroupVersionKind().Kind,
+		Namespace: workload.GetNamespace(),
+		Name:      workload.GetName(),
+		Action:    audit.ActionScaleDown,
+		Scope:     decidingScope,
+		DryRun:    dryRun,
+	}
+
+	if oldReplicas, ok := scalable.GetReplicas(workload); ok {
+		record.OldReplicas = oldReplicas.String()
+	}
+
+	if newReplicas, ok := scalable.GetReplicas(scaledWorkload); ok {
+		record.NewReplicas = newReplicas.String()
+	}
+
+	err := auditSink.Write(record, ctx)
+	if err != nil {
+		slog.Error("failed to write audit record", "error", err, "workload", workload.GetName(), "namespace", workload.GetNamespace())
+	}
+}
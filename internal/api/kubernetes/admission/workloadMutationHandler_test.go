@@ -34,11 +34,31 @@ func (m *MockClient) GetNamespaceScope(
 	return args.Get(0).(*values.Scope), args.Error(1)
 }
 
+func (m *MockClient) GetHorizontalPodAutoscalers(namespace string, ctx context.Context) ([]scalable.Workload, error) {
+	args := m.Called(namespace, ctx)
+	return args.Get(0).([]scalable.Workload), args.Error(1)
+}
+
 func (m *MockClient) GetScaledObjects(namespace string, ctx context.Context) ([]scalable.Workload, error) {
 	args := m.Called(namespace, ctx)
 	return args.Get(0).([]scalable.Workload), args.Error(1)
 }
 
+func (m *MockClient) GetPauseMode(ctx context.Context) (values.PauseMode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(values.PauseMode), args.Error(1)
+}
+
+func (m *MockClient) LoadAdmittedWorkloadState(workload scalable.Workload, ctx context.Context) error {
+	args := m.Called(workload, ctx)
+	return args.Error(0)
+}
+
+func (m *MockClient) StoreAdmittedWorkloadState(original, mutated scalable.Workload, ctx context.Context) error {
+	args := m.Called(original, mutated, ctx)
+	return args.Error(0)
+}
+
 func newAdmissionRequests(t *testing.T, uid, kind, namespace string, rawJSON []byte) *http.Request {
 	t.Helper()
 
@@ -158,6 +178,35 @@ func buildScaledObjectFromBytes(t *testing.T, namespace string) scalable.Workloa
 	return soWorkload
 }
 
+func buildHorizontalPodAutoscalerFromBytes(t *testing.T, namespace string) scalable.Workload {
+	t.Helper()
+
+	rawHPA := []byte(`{
+		"apiVersion": "autoscaling/v2",
+		"kind": "HorizontalPodAutoscaler",
+		"metadata": {
+			"name": "test-deploy",
+			"namespace": "` + namespace + `"
+		},
+		"spec": {
+			"scaleTargetRef": {
+				"name": "test-deploy",
+				"kind": "Deployment",
+				"apiVersion": "apps/v1"
+			},
+			"minReplicas": 1,
+			"maxReplicas": 5
+		}
+	}`)
+
+	hpaWorkload, err := scalable.ParseWorkloadFromRawObject("horizontalpodautoscaler", rawHPA)
+	if err != nil {
+		t.Fatalf("failed to parse horizontalpodautoscaler from bytes: %v", err)
+	}
+
+	return hpaWorkload
+}
+
 func newHandlerWithMocks(mockClient *MockClient) *WorkloadMutationHandler {
 	return NewWorkloadMutationHandler(
 		mockClient,
@@ -165,6 +214,7 @@ func newHandlerWithMocks(mockClient *MockClient) *WorkloadMutationHandler {
 		false, nil, &util.RegexList{regexp.MustCompile(".*")}, &util.RegexList{}, &util.RegexList{},
 		map[string]struct{}{"deployments": {}, "scaledobjects": {}}, false,
 		nil,
+		nil,
 	)
 }
 
@@ -174,12 +224,25 @@ func TestEvaluateMutation(t *testing.T) {
 
 	tests := []struct {
 		name            string
+		pauseMode       values.PauseMode
 		setupMocks      func(*testing.T, *MockClient)
 		setupHandler    func(*WorkloadMutationHandler)
 		request         func(*testing.T) *http.Request
 		expectedMessage string
 		expectedCode    int32
 	}{
+		{
+			name:         "Workload not mutated because the downscaler is paused",
+			pauseMode:    values.PauseModeFreeze,
+			setupMocks:   func(t *testing.T, mockClient *MockClient) { t.Helper() },
+			setupHandler: func(h *WorkloadMutationHandler) { h.includeNamespaces = &[]string{"default"} },
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+				return newDeploymentRequestWithLabels(t, "default")
+			},
+			expectedMessage: "downscaler is paused",
+			expectedCode:    http.StatusAccepted,
+		},
 		{
 			name: "Workload excluded because uptime",
 			setupMocks: func(t *testing.T, mockClient *MockClient) {
@@ -211,6 +274,7 @@ func TestEvaluateMutation(t *testing.T) {
 
 				mockClient.On("GetScaledObjects", "default", mock.Anything).Return([]scalable.Workload{}, nil)
 				mockClient.On("GetNamespaceScope", "default", mock.Anything).Return(scope, nil)
+				mockClient.On("LoadAdmittedWorkloadState", mock.Anything, mock.Anything).Return(nil)
 			},
 			setupHandler: func(h *WorkloadMutationHandler) { h.includeNamespaces = &[]string{"default"}; h.dryRun = true },
 			request: func(t *testing.T) *http.Request {
@@ -288,6 +352,22 @@ func TestEvaluateMutation(t *testing.T) {
 			expectedMessage: "workload is excluded from downscaling",
 			expectedCode:    http.StatusAccepted,
 		},
+		{
+			name: "Workload excluded by external scaling (hpa)",
+			setupMocks: func(t *testing.T, mockClient *MockClient) {
+				t.Helper()
+				hpa := buildHorizontalPodAutoscalerFromBytes(t, "default")
+				mockClient.On("GetScaledObjects", "default", mock.Anything).Return([]scalable.Workload{}, nil)
+				mockClient.On("GetHorizontalPodAutoscalers", "default", mock.Anything).Return([]scalable.Workload{hpa}, nil)
+			},
+			setupHandler: func(h *WorkloadMutationHandler) { h.includeNamespaces = &[]string{"default"} },
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+				return newDeploymentRequestWithLabels(t, "default")
+			},
+			expectedMessage: "workload is excluded from downscaling",
+			expectedCode:    http.StatusAccepted,
+		},
 		{
 			name:         "Workload ignored because namespace not included",
 			setupMocks:   func(t *testing.T, mockClient *MockClient) { t.Helper() },
@@ -344,11 +424,14 @@ func TestEvaluateMutation(t *testing.T) {
 
 			mockClient := &MockClient{}
 			handler := newHandlerWithMocks(mockClient)
+			mockClient.On("GetPauseMode", mock.Anything).Return(currentTest.pauseMode, nil)
 
 			if currentTest.setupMocks != nil {
 				currentTest.setupMocks(t, mockClient)
 			}
 
+			mockClient.On("GetHorizontalPodAutoscalers", mock.Anything, mock.Anything).Return([]scalable.Workload{}, nil).Maybe()
+
 			if currentTest.setupHandler != nil {
 				currentTest.setupHandler(handler)
 			}
@@ -364,3 +447,35 @@ func TestEvaluateMutation(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateMutation_StoresAdmittedWorkloadState(t *testing.T) {
+	t.Parallel()
+
+	scope := values.NewScope()
+	scope.DownscaleReplicas = values.AbsoluteReplicas(0)
+	_ = scope.ForceDowntime.Set("always")
+
+	mockClient := &MockClient{}
+	mockClient.On("GetPauseMode", mock.Anything).Return(values.PauseModeNone, nil)
+	mockClient.On("GetScaledObjects", "default", mock.Anything).Return([]scalable.Workload{}, nil)
+	mockClient.On("GetHorizontalPodAutoscalers", "default", mock.Anything).Return([]scalable.Workload{}, nil)
+	mockClient.On("GetNamespaceScope", "default", mock.Anything).Return(scope, nil)
+	mockClient.On("LoadAdmittedWorkloadState", mock.Anything, mock.Anything).Return(nil)
+	mockClient.On("StoreAdmittedWorkloadState", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	handler := newHandlerWithMocks(mockClient)
+	handler.includeNamespaces = &[]string{"default"}
+
+	input, err := parseAdmissionReviewFromRequest(newDeploymentRequestWithLabels(t, "default"))
+	require.NoError(t, err)
+
+	workload, err := scalable.ParseWorkloadFromRawObject("deployment", input.Request.Object.Raw)
+	require.NoError(t, err)
+
+	resp, err := handler.evaluateWorkloadMutation(t.Context(), workload, input, false)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Response.Patch)
+
+	mockClient.AssertCalled(t, "LoadAdmittedWorkloadState", workload, mock.Anything)
+	mockClient.AssertNumberOfCalls(t, "StoreAdmittedWorkloadState", 1)
+}
@@ -3,10 +3,13 @@ package kubernetes
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	stdErrors "errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,36 +53,69 @@ var (
 type Client interface {
 	// GetNamespacesAsSet gets all namespaces or a specific list of namespace
 	GetNamespacesAsSet() (map[string]struct{}, error)
-	// GetNamespacesScopes gets the namespaces scopes from the namespaces annotations
+	// GetNamespacesScopes gets the namespaces scopes from the namespaces annotations, skipping namespaces which failed
 	GetNamespacesScopes(workloads []scalable.Workload, ctx context.Context) (map[string]*values.Scope, error)
 	// GetNamespaceScope gets the namespace scope from its annotations
 	GetNamespaceScope(namespace string, ctx context.Context) (*values.Scope, error)
-	// GetWorkloads gets all workloads of the specified resources for the specified namespaces
+	// GetServedResourceTypes splits the resource types into the ones served by the API server and the ones that aren't
+	GetServedResourceTypes(resourceTypes []string) ([]string, []string, error)
+	// GetWorkloads gets all workloads of the specified resources for the specified namespaces, returning partial results on failures
 	GetWorkloads(namespaces []string, resourceTypes []string, ctx context.Context) ([]scalable.Workload, error)
 	// RegetWorkload gets the workload again to ensure the latest state
 	RegetWorkload(workload scalable.Workload, ctx context.Context) error
-	// DownscaleWorkload downscales the workload to the specified replicas
-	DownscaleWorkload(replicas values.Replicas, workload scalable.Workload, ctx context.Context) (*metrics.SavedResources, error)
-	// UpscaleWorkload upscales the workload to the original replicas
-	UpscaleWorkload(workload scalable.Workload, ctx context.Context) error
+	// DownscaleWorkload downscales the workload to the specified replicas, updating its status in the same request if it is set
+	DownscaleWorkload(
+		replicas values.Replicas,
+		workload scalable.Workload,
+		status *scalable.Status,
+		ctx context.Context,
+	) (*metrics.SavedResources, error)
+	// UpscaleWorkload upscales the workload to the original replicas, updating its status in the same request if it is set
+	UpscaleWorkload(workload scalable.Workload, status *scalable.Status, ctx context.Context) error
+	// SetManualOverride records or clears the manual override of the scaled down workload
+	SetManualOverride(workload scalable.Workload, overridden bool, ctx context.Context) error
+	// UpdateWorkloadStatus updates the status annotation of the workload if it changed
+	UpdateWorkloadStatus(workload scalable.Workload, status scalable.Status, ctx context.Context) error
 	// ensureSecret ensures that the secret used for storing TLS certificates exists
 	ensureSecret(namespace, secretName string, ctx context.Context) (bool, error)
 	// GetScaledObjects gets all scaledobjects in the specified namespace
 	GetScaledObjects(namespace string, ctx context.Context) ([]scalable.Workload, error)
+	// GetHorizontalPodAutoscalers gets all horizontalpodautoscalers in the specified namespace
+	GetHorizontalPodAutoscalers(namespace string, ctx context.Context) ([]scalable.Workload, error)
+	// LoadAdmittedWorkloadState loads the stored state of the workload of an admission request into its annotations
+	LoadAdmittedWorkloadState(workload scalable.Workload, ctx context.Context) error
+	// StoreAdmittedWorkloadState stores the state the mutated workload of an admission request got and removes it from both workloads
+	StoreAdmittedWorkloadState(original, mutated scalable.Workload, ctx context.Context) error
+	// ParseScaleSubresourceWorkload parses the admission review of a custom resource which is scaled through its scale subresource
+	ParseScaleSubresourceWorkload(resource schema.GroupVersionResource, rawObject []byte, ctx context.Context) (scalable.Workload, error)
 	// CreateLease creates a new lease for the downscaler
 	CreateLease(leaseName string) (*resourcelock.LeaseLock, error)
 	// GetNamespaceAnnotations gets the annotations of the workload's namespace
 	GetNamespaceAnnotations(namespace string, ctx context.Context) (map[string]string, error)
+	// AnnotateNamespace adds the annotations to the namespace
+	AnnotateNamespace(namespace string, annotations map[string]string, ctx context.Context) error
+	// AnnotateWorkload adds the annotations to the workload of the resource type with the given name
+	AnnotateWorkload(resourceType, namespace, name string, annotations map[string]string, ctx context.Context) error
 	// addEvent creates a new event on either a workload or a namespace
 	addEvent(eventType, reason, identifier, message string, object *corev1.ObjectReference, ctx context.Context) error
 	// GetChildrenWorkloads gets the children workloads of the specified workload
 	GetChildrenWorkloads(workload scalable.Workload, ctx context.Context) ([]scalable.Workload, error)
+	// CheckConnection checks if the API server is reachable and ready
+	CheckConnection(ctx context.Context) error
+	// GetPauseMode gets the pause mode from the annotations of the downscaler's namespace
+	GetPauseMode(ctx context.Context) (values.PauseMode, error)
+	// SetPauseMode sets the pause mode on the downscaler's namespace, removing it if the downscaler isn't paused
+	SetPauseMode(mode values.PauseMode, ctx context.Context) error
+	// GetMassDownscalingAcknowledgement gets until when mass downscaling of all workloads is acknowledged on the downscaler's namespace
+	GetMassDownscalingAcknowledgement(ctx context.Context) (time.Time, error)
+	// AcknowledgeMassDownscaling acknowledges mass downscaling of all workloads until the given time on the downscaler's namespace
+	AcknowledgeMassDownscaling(until time.Time, ctx context.Context) error
 }
 
-// NewClient makes a new Client.
+// NewClient makes a new Client. The original replicas of scaled down workloads are kept in the given state store backend.
 //
 // nolint: cyclop // this function is complex due to the multiple clientsets being created.
-func NewClient(kubeconfig string, dryRun bool, qps float64, burst int) (client, error) {
+func NewClient(kubeconfig string, dryRun bool, qps float64, burst int, stateStoreBackend string) (client, error) {
 	var kubeclient client
 
 	var clientsets scalable.Clientsets
@@ -130,6 +170,11 @@ func NewClient(kubeconfig string, dryRun bool, qps float64, burst int) (client,
 		return kubeclient, fmt.Errorf("failed to get clientset for gateway resources: %w", err)
 	}
 
+	clientsets.Dynamic, err = dynamic.NewForConfig(config)
+	if err != nil {
+		return kubeclient, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
 	scheme, err = NewScheme()
 	if err != nil {
 		return kubeclient, fmt.Errorf("failed to build scheme: %w", err)
@@ -142,6 +187,11 @@ func NewClient(kubeconfig string, dryRun bool, qps float64, burst int) (client,
 		return kubeclient, fmt.Errorf("failed to get controller runtime client: %w", err)
 	}
 
+	kubeclient.stateStore, err = newStateStore(stateStoreBackend, clientsets.Kubernetes, clientsets.Client)
+	if err != nil {
+		return kubeclient, fmt.Errorf("failed to create state store: %w", err)
+	}
+
 	kubeclient.clientsets = &clientsets
 
 	return kubeclient, nil
@@ -170,6 +220,7 @@ func NewScheme() (*runtime.Scheme, error) {
 type client struct {
 	clientsets *scalable.Clientsets
 	dryRun     bool
+	stateStore stateStore
 }
 
 // getNamespaceAnnotations gets the annotations of the workload's namespace.
@@ -182,7 +233,69 @@ func (c client) GetNamespaceAnnotations(namespace string, ctx context.Context) (
 	return ns.Annotations, nil
 }
 
+// AnnotateNamespace adds the annotations to the namespace.
+func (c client) AnnotateNamespace(namespace string, annotations map[string]string, ctx context.Context) error {
+	if c.dryRun {
+		slog.Info("running in dry run mode, would have annotated namespace", "namespace", namespace, "annotations", annotations)
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": annotations}})
+	if err != nil {
+		return fmt.Errorf("failed to create annotations patch: %w", err)
+	}
+
+	_, err = c.clientsets.Kubernetes.CoreV1().Namespaces().Patch(ctx, namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to annotate namespace: %w", err)
+	}
+
+	return nil
+}
+
+// AnnotateWorkload adds the annotations to the workload of the resource type with the given name.
+func (c client) AnnotateWorkload(resourceType, namespace, name string, annotations map[string]string, ctx context.Context) error {
+	workloads, err := scalable.GetWorkloads(strings.ToLower(resourceType), namespace, c.clientsets, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get workloads: %w", err)
+	}
+
+	index := slices.IndexFunc(workloads, func(workload scalable.Workload) bool { return workload.GetName() == name })
+	if index == -1 {
+		return newWorkloadNotFoundError(resourceType, namespace, name)
+	}
+
+	workload := workloads[index]
+
+	if c.dryRun {
+		slog.Info("running in dry run mode, would have annotated workload", "workload", name, "namespace", namespace, "annotations", annotations)
+		return nil
+	}
+
+	original, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	workloadAnnotations := workload.GetAnnotations()
+	if workloadAnnotations == nil {
+		workloadAnnotations = map[string]string{}
+	}
+
+	maps.Copy(workloadAnnotations, annotations)
+	workload.SetAnnotations(workloadAnnotations)
+
+	err = scalable.PatchWorkload(original, workload, c.clientsets, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to annotate workload: %w", err)
+	}
+
+	return nil
+}
+
 // GetWorkloads gets all workloads of the specified resources for the specified namespaces.
+// Failing to list a resource type in a namespace doesn't stop the other ones from being listed.
+// The failures are joined into the returned error, alongside the workloads which could be listed.
 func (c client) GetWorkloads(
 	namespaces,
 	resourceTypes []string,
@@ -193,24 +306,85 @@ func (c client) GetWorkloads(
 
 	var results []scalable.Workload
 
+	var listErrors []error
+
 	if namespaces == nil {
 		namespaces = []string{""}
 	}
 
 	for _, namespace := range namespaces {
 		for _, resourceType := range resourceTypes {
-			slog.Debug("getting workloads from resource type", "resourceType", resourceType)
+			slog.Debug("getting workloads from resource type", "resourceType", resourceType, "namespace", namespace)
 
 			workloads, err := scalable.GetWorkloads(strings.ToLower(resourceType), namespace, c.clientsets, ctx)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get workloads: %w", err)
+				var invalidResourceErr *scalable.InvalidResourceError
+				if stdErrors.As(err, &invalidResourceErr) {
+					return nil, fmt.Errorf("failed to get workloads: %w", err)
+				}
+
+				err = fmt.Errorf("failed to get workloads of resource type %q: %w", resourceType, err)
+				listErrors = append(listErrors, newNamespaceError(namespace, err))
+
+				continue
 			}
 
 			results = append(results, workloads...)
 		}
 	}
 
-	return results, nil
+	results, err := c.reconcileStates(results, GetFailedNamespaces(stdErrors.Join(listErrors...)), ctx)
+	if err != nil {
+		listErrors = append(listErrors, err)
+	}
+
+	return results, stdErrors.Join(listErrors...)
+}
+
+// GetServedResourceTypes splits the resource types into the ones served by the API server and the ones that aren't.
+// This allows skipping resource types whose CRDs aren't installed on the cluster.
+func (c client) GetServedResourceTypes(resourceTypes []string) ([]string, []string, error) {
+	_, apiResourceLists, err := c.clientsets.Kubernetes.Discovery().ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, nil, fmt.Errorf("failed to discover served resources: %w", err)
+		}
+
+		slog.Warn("failed to discover some api groups, treating their resources as not served", "error", err)
+	}
+
+	servedGroupResources := make(map[schema.GroupResource]struct{})
+
+	for _, apiResourceList := range apiResourceLists {
+		groupVersion, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			slog.Warn("failed to parse discovered group version, skipping it", "groupVersion", apiResourceList.GroupVersion, "error", err)
+			continue
+		}
+
+		for i := range apiResourceList.APIResources {
+			servedGroupResources[groupVersion.WithResource(apiResourceList.APIResources[i].Name).GroupResource()] = struct{}{}
+		}
+	}
+
+	served := make([]string, 0, len(resourceTypes))
+	notServed := make([]string, 0, len(resourceTypes))
+
+	for _, resourceType := range resourceTypes {
+		groupResource, err := scalable.GetGroupResource(strings.ToLower(resourceType))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get group resource for resource type: %w", err)
+		}
+
+		if _, ok := servedGroupResources[groupResource]; !ok {
+			notServed = append(notServed, resourceType)
+			continue
+		}
+
+		served = append(served, resourceType)
+	}
+
+	return served, notServed, nil
 }
 
 // GetChildrenWorkloads gets the children workloads of the specified workload.
@@ -239,6 +413,13 @@ func (c client) GetChildrenWorkloads(workload scalable.Workload, ctx context.Con
 			"childrenCount", len(children),
 		)
 
+		if c.stateStore != nil {
+			children, err = c.reconcileNamespaceStates(workload.GetNamespace(), children, false, ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reconcile stored states of children workloads: %w", err)
+			}
+		}
+
 		return children, nil
 	}
 
@@ -252,23 +433,50 @@ func (c client) RegetWorkload(workload scalable.Workload, ctx context.Context) e
 		return fmt.Errorf("failed to get workload: %w", err)
 	}
 
+	if c.stateStore == nil {
+		return nil
+	}
+
+	reconciled, err := c.reconcileNamespaceStates(workload.GetNamespace(), []scalable.Workload{workload}, false, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile stored state of workload: %w", err)
+	}
+
+	if len(reconciled) == 0 {
+		return newWorkloadNotMigratedError(workload.GetNamespace(), workload.GetName())
+	}
+
 	return nil
 }
 
 // DownscaleWorkload downscales the workload to the specified replicas.
-//
-
+// If the status is set, it is updated in the same request, so the status doesn't cause an additional request.
 func (c client) DownscaleWorkload(
 	replicas values.Replicas,
 	workload scalable.Workload,
+	status *scalable.Status,
 	ctx context.Context,
 ) (*metrics.SavedResources, error) {
+	original, err := workload.Copy()
+	if err != nil {
+		return metrics.NewSavedResources(0, 0), fmt.Errorf("failed to copy workload: %w", err)
+	}
+
 	savedResources, isUpdateNeeded, err := workload.ScaleDown(replicas)
 	if err != nil {
 		return metrics.NewSavedResources(0, 0), fmt.Errorf("failed to set the workload into a scaled down state: %w", err)
 	}
 
-	if !isUpdateNeeded {
+	if isUpdateNeeded && !c.dryRun {
+		scalable.SetLastTransition(workload, time.Now())
+	}
+
+	isStatusChanged, err := setStatusIfSet(workload, status)
+	if err != nil {
+		return metrics.NewSavedResources(0, 0), err
+	}
+
+	if !isUpdateNeeded && !isStatusChanged {
 		slog.Debug(
 			"workload is already in a scaled down state, no update needed",
 			"workload", workload.GetName(),
@@ -280,7 +488,7 @@ func (c client) DownscaleWorkload(
 
 	if c.dryRun {
 		slog.Info(
-			"running in dry run mode, would have sent update workload request to scale down workload",
+			"running in dry run mode, would have sent patch workload request to scale down workload",
 			"workload", workload.GetName(),
 			"namespace", workload.GetNamespace(),
 		)
@@ -288,9 +496,9 @@ func (c client) DownscaleWorkload(
 		return metrics.NewSavedResources(0, 0), nil
 	}
 
-	err = workload.Update(c.clientsets, ctx)
+	err = c.patchWorkload(original, workload, ctx)
 	if err != nil {
-		return metrics.NewSavedResources(0, 0), fmt.Errorf("failed to update the workload: %w", err)
+		return metrics.NewSavedResources(0, 0), fmt.Errorf("failed to patch the workload: %w", err)
 	}
 
 	slog.Debug("successfully scaled down workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
@@ -299,13 +507,28 @@ func (c client) DownscaleWorkload(
 }
 
 // UpscaleWorkload upscales the workload to the original replicas.
-func (c client) UpscaleWorkload(workload scalable.Workload, ctx context.Context) error {
+// If the status is set, it is updated in the same request, so the status doesn't cause an additional request.
+func (c client) UpscaleWorkload(workload scalable.Workload, status *scalable.Status, ctx context.Context) error {
+	original, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
 	isUpdateNeeded, err := workload.ScaleUp()
 	if err != nil {
 		return fmt.Errorf("failed to set the workload into a scaled up state: %w", err)
 	}
 
-	if !isUpdateNeeded {
+	if isUpdateNeeded && !c.dryRun {
+		scalable.SetLastTransition(workload, time.Now())
+	}
+
+	isStatusChanged, err := setStatusIfSet(workload, status)
+	if err != nil {
+		return err
+	}
+
+	if !isUpdateNeeded && !isStatusChanged {
 		slog.Debug(
 			"workload is already in a scaled up state, no update needed",
 			"workload", workload.GetName(),
@@ -317,7 +540,7 @@ func (c client) UpscaleWorkload(workload scalable.Workload, ctx context.Context)
 
 	if c.dryRun {
 		slog.Info(
-			"running in dry run mode, would have sent update workload request to scale up workload",
+			"running in dry run mode, would have sent patch workload request to scale up workload",
 			"workload", workload.GetName(),
 			"namespace", workload.GetNamespace(),
 		)
@@ -325,9 +548,9 @@ func (c client) UpscaleWorkload(workload scalable.Workload, ctx context.Context)
 		return nil
 	}
 
-	err = workload.Update(c.clientsets, ctx)
+	err = c.patchWorkload(original, workload, ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update the workload: %w", err)
+		return fmt.Errorf("failed to patch the workload: %w", err)
 	}
 
 	slog.Debug("successfully scaled up workload", "workload", workload.GetName(), "namespace", workload.GetNamespace())
@@ -335,6 +558,91 @@ func (c client) UpscaleWorkload(workload scalable.Workload, ctx context.Context)
 	return nil
 }
 
+// SetManualOverride records the time a manual override of the scaled down workload was detected, or clears it if it is not overridden.
+func (c client) SetManualOverride(workload scalable.Workload, overridden bool, ctx context.Context) error {
+	original, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	if overridden {
+		scalable.SetManualOverrideSince(workload, time.Now())
+	} else {
+		scalable.RemoveManualOverrideSince(workload)
+	}
+
+	if c.dryRun {
+		slog.Info(
+			"running in dry run mode, would have sent patch workload request to update the manual override",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+			"overridden", overridden,
+		)
+
+		return nil
+	}
+
+	err = c.patchWorkload(original, workload, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to patch the workload: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWorkloadStatus updates the status annotation of the workload. No request is sent if the status didn't change.
+func (c client) UpdateWorkloadStatus(workload scalable.Workload, status scalable.Status, ctx context.Context) error {
+	isStatusChanged, err := scalable.IsStatusChanged(workload, status)
+	if err != nil {
+		return fmt.Errorf("failed to check if the status changed: %w", err)
+	}
+
+	if !isStatusChanged {
+		return nil
+	}
+
+	original, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	_, err = scalable.SetStatus(workload, status)
+	if err != nil {
+		return fmt.Errorf("failed to set status: %w", err)
+	}
+
+	if c.dryRun {
+		slog.Info(
+			"running in dry run mode, would have sent patch workload request to update the status",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+		)
+
+		return nil
+	}
+
+	err = c.patchWorkload(original, workload, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to patch the workload: %w", err)
+	}
+
+	return nil
+}
+
+// setStatusIfSet sets the status on the workload if it is set and returns if the status annotation changed.
+func setStatusIfSet(workload scalable.Workload, status *scalable.Status) (bool, error) {
+	if status == nil {
+		return false, nil
+	}
+
+	isStatusChanged, err := scalable.SetStatus(workload, *status)
+	if err != nil {
+		return false, fmt.Errorf("failed to set status: %w", err)
+	}
+
+	return isStatusChanged, nil
+}
+
 // addEvent creates or updates a new event on either a workload or a namespace.
 func (c client) addEvent(
 	eventType, reason, identifier, message string,
@@ -395,6 +703,16 @@ func (c client) addEvent(
 	return nil
 }
 
+// CheckConnection checks if the API server is reachable and ready.
+func (c client) CheckConnection(ctx context.Context) error {
+	err := c.clientsets.Kubernetes.Discovery().RESTClient().Get().AbsPath("/readyz").Do(ctx).Error()
+	if err != nil {
+		return fmt.Errorf("failed to reach the API server: %w", err)
+	}
+
+	return nil
+}
+
 func (c client) CreateLease(leaseName string) (*resourcelock.LeaseLock, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -420,6 +738,83 @@ func (c client) CreateLease(leaseName string) (*resourcelock.LeaseLock, error) {
 	return lease, nil
 }
 
+// GetPauseMode gets the pause mode from the annotations of the downscaler's namespace.
+func (c client) GetPauseMode(ctx context.Context) (values.PauseMode, error) {
+	namespace, err := getCurrentNamespace()
+	if err != nil {
+		return values.PauseModeNone, fmt.Errorf("failed to get namespace or running outside of cluster: %w", err)
+	}
+
+	annotations, err := c.GetNamespaceAnnotations(namespace, ctx)
+	if err != nil {
+		return values.PauseModeNone, fmt.Errorf("failed to get annotations of the downscaler's namespace: %w", err)
+	}
+
+	mode, err := values.GetPauseModeFromAnnotations(annotations)
+	if err != nil {
+		return values.PauseModeNone, fmt.Errorf("failed to parse pause mode: %w", err)
+	}
+
+	return mode, nil
+}
+
+// SetPauseMode sets the pause mode on the downscaler's namespace, removing it if the downscaler isn't paused.
+func (c client) SetPauseMode(mode values.PauseMode, ctx context.Context) error {
+	namespace, err := getCurrentNamespace()
+	if err != nil {
+		return fmt.Errorf("failed to get namespace or running outside of cluster: %w", err)
+	}
+
+	if c.dryRun {
+		slog.Info("running in dry run mode, would have set pause mode", "namespace", namespace, "mode", mode.String())
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": values.GetPauseAnnotations(mode)}})
+	if err != nil {
+		return fmt.Errorf("failed to create pause mode patch: %w", err)
+	}
+
+	_, err = c.clientsets.Kubernetes.CoreV1().Namespaces().Patch(ctx, namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set pause mode on namespace: %w", err)
+	}
+
+	return nil
+}
+
+// GetMassDownscalingAcknowledgement gets until when mass downscaling of all workloads is acknowledged on the downscaler's namespace.
+// The zero time is returned if mass downscaling isn't acknowledged.
+func (c client) GetMassDownscalingAcknowledgement(ctx context.Context) (time.Time, error) {
+	namespace, err := getCurrentNamespace()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get namespace or running outside of cluster: %w", err)
+	}
+
+	annotations, err := c.GetNamespaceAnnotations(namespace, ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get annotations of the downscaler's namespace: %w", err)
+	}
+
+	until, err := values.GetAcknowledgedUntilFromAnnotations(annotations)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse acknowledgement: %w", err)
+	}
+
+	return until, nil
+}
+
+// AcknowledgeMassDownscaling acknowledges mass downscaling of all workloads until the given time on the downscaler's namespace,
+// so the acknowledgement is shared by all replicas of the downscaler and survives restarts.
+func (c client) AcknowledgeMassDownscaling(until time.Time, ctx context.Context) error {
+	namespace, err := getCurrentNamespace()
+	if err != nil {
+		return fmt.Errorf("failed to get namespace or running outside of cluster: %w", err)
+	}
+
+	return c.AnnotateNamespace(namespace, values.GetAcknowledgeAnnotations(until), ctx)
+}
+
 // GetNamespacesAsSet returns all namespaces as a set (map[string]struct{}).
 func (c client) GetNamespacesAsSet() (map[string]struct{}, error) {
 	namespaceList, err := c.clientsets.Kubernetes.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
@@ -437,6 +832,7 @@ func (c client) GetNamespacesAsSet() (map[string]struct{}, error) {
 }
 
 // GetNamespacesScopes gets the namespaces scopes from the namespaces annotations.
+// Namespaces whose scope couldn't be fetched are missing from the result and their errors are joined into the returned error.
 func (c client) GetNamespacesScopes(workloads []scalable.Workload, ctx context.Context) (map[string]*values.Scope, error) {
 	var waitGroup sync.WaitGroup
 
@@ -464,7 +860,7 @@ func (c client) GetNamespacesScopes(workloads []scalable.Workload, ctx context.C
 
 			namespaceScope, err := c.GetNamespaceScope(namespace, ctx)
 			if err != nil {
-				errChan <- fmt.Errorf("failed to get namespace scope for namespace %s: %w", namespace, err)
+				errChan <- newNamespaceError(namespace, fmt.Errorf("failed to get namespace scope: %w", err))
 				return
 			}
 
@@ -478,10 +874,9 @@ func (c client) GetNamespacesScopes(workloads []scalable.Workload, ctx context.C
 	close(resultChan)
 	close(errChan)
 
+	scopeErrors := make([]error, 0, len(errChan))
 	for err := range errChan {
-		if err != nil {
-			return nil, err
-		}
+		scopeErrors = append(scopeErrors, err)
 	}
 
 	for results := range resultChan {
@@ -490,7 +885,7 @@ func (c client) GetNamespacesScopes(workloads []scalable.Workload, ctx context.C
 		}
 	}
 
-	return namespaceScopes, nil
+	return namespaceScopes, stdErrors.Join(scopeErrors...)
 }
 
 func (c client) GetNamespaceScope(namespace string, ctx context.Context) (*values.Scope, error) {
@@ -521,7 +916,7 @@ func (c client) GetNamespaceScope(namespace string, ctx context.Context) (*value
 
 // GetScaledObjects gets all scaledobjects in the specified namespace.
 func (c client) GetScaledObjects(namespace string, ctx context.Context) ([]scalable.Workload, error) {
-	scaledObjects, err := scalable.GetWorkloads("scaledobject", namespace, c.clientsets, ctx)
+	scaledObjects, err := scalable.GetWorkloads("scaledobjects", namespace, c.clientsets, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get scaledobjects: %w", err)
 	}
@@ -529,6 +924,35 @@ func (c client) GetScaledObjects(namespace string, ctx context.Context) ([]scala
 	return scaledObjects, nil
 }
 
+// GetHorizontalPodAutoscalers gets all horizontalpodautoscalers in the specified namespace.
+func (c client) GetHorizontalPodAutoscalers(namespace string, ctx context.Context) ([]scalable.Workload, error) {
+	hpas, err := scalable.GetWorkloads("horizontalpodautoscalers", namespace, c.clientsets, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get horizontalpodautoscalers: %w", err)
+	}
+
+	return hpas, nil
+}
+
+// ParseScaleSubresourceWorkload parses the admission review of a custom resource which is scaled through its scale subresource.
+//
+//nolint:ireturn // this function should return an interface type
+func (c client) ParseScaleSubresourceWorkload(
+	resource schema.GroupVersionResource,
+	rawObject []byte,
+	ctx context.Context,
+) (scalable.Workload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	workload, err := scalable.ParseScaleSubresourceWorkloadFromRawObject(resource, rawObject, c.clientsets, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s from admission request: %w", resource.GroupResource(), err)
+	}
+
+	return workload, nil
+}
+
 // ensureSecret ensures that the secret used for storing TLS certificates exists.
 func (c client) ensureSecret(namespace, secretName string, ctx context.Context) (bool, error) {
 	isPresent := false
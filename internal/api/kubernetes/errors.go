@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NamespaceError is an error which only affects a single namespace. An empty namespace means all namespaces are affected.
+type NamespaceError struct {
+	namespace string
+	err       error
+}
+
+func newNamespaceError(namespace string, err error) error {
+	return &NamespaceError{namespace: namespace, err: err}
+}
+
+func (n *NamespaceError) Error() string {
+	if n.namespace == "" {
+		return n.err.Error()
+	}
+
+	return fmt.Sprintf("namespace %q: %s", n.namespace, n.err.Error())
+}
+
+func (n *NamespaceError) Unwrap() error {
+	return n.err
+}
+
+// WorkloadNotFoundError is an error for when a workload couldn't be found.
+type WorkloadNotFoundError struct {
+	resourceType string
+	namespace    string
+	name         string
+}
+
+func newWorkloadNotFoundError(resourceType, namespace, name string) error {
+	return &WorkloadNotFoundError{resourceType: resourceType, namespace: namespace, name: name}
+}
+
+func (w *WorkloadNotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found in namespace %q", w.resourceType, w.name, w.namespace)
+}
+
+// InvalidStateStoreError is an error for when an unknown state store backend was configured.
+type InvalidStateStoreError struct {
+	backend string
+}
+
+func newInvalidStateStoreError(backend string) error {
+	return &InvalidStateStoreError{backend: backend}
+}
+
+func (i *InvalidStateStoreError) Error() string {
+	return fmt.Sprintf("invalid state store %q: has to be one of %q, %q or %q", i.backend, StateStoreAnnotation, StateStoreConfigMap, StateStoreCRD)
+}
+
+// InvalidWorkloadStateError is an error for when the stored state of a workload couldn't be parsed.
+type InvalidWorkloadStateError struct {
+	uid string
+}
+
+func newInvalidWorkloadStateError(uid string) error {
+	return &InvalidWorkloadStateError{uid: uid}
+}
+
+func (i *InvalidWorkloadStateError) Error() string {
+	return fmt.Sprintf("stored state of workload with uid %q is missing its original replicas", i.uid)
+}
+
+// WorkloadNotMigratedError is an error for when the original replicas annotation of a workload couldn't be migrated into the state store.
+type WorkloadNotMigratedError struct {
+	namespace string
+	name      string
+}
+
+func newWorkloadNotMigratedError(namespace, name string) error {
+	return &WorkloadNotMigratedError{namespace: namespace, name: name}
+}
+
+func (w *WorkloadNotMigratedError) Error() string {
+	return fmt.Sprintf("original replicas annotation of workload %q in namespace %q couldn't be migrated into the state store", w.name, w.namespace)
+}
+
+// GetFailedNamespaces gets the namespaces of all NamespaceErrors joined into err. Errors affecting all namespaces are skipped.
+func GetFailedNamespaces(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var errs []error
+
+	joinedErr, isJoined := err.(interface{ Unwrap() []error }) //nolint:errorlint // errors.Join doesn't provide a way to get the errors
+	if isJoined {
+		errs = joinedErr.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+
+	failedNamespaces := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		var namespaceErr *NamespaceError
+		if !errors.As(err, &namespaceErr) || namespaceErr.namespace == "" {
+			continue
+		}
+
+		failedNamespaces = append(failedNamespaces, namespaceErr.namespace)
+	}
+
+	return failedNamespaces
+}
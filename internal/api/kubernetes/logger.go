@@ -8,7 +8,19 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
-const reasonInvalidConfiguration = "InvalidConfiguration"
+const (
+	reasonInvalidConfiguration = "InvalidConfiguration"
+	reasonManualOverride       = "ManualOverride"
+	reasonCircuitBreaker       = "CircuitBreakerTripped"
+	reasonUpscaleSucceeded     = "UpscaleSucceeded"
+	reasonUpscaleFailed        = "UpscaleFailed"
+	reasonUpscaleNotReady      = "UpscaleNotReady"
+	reasonScaledDown           = "ScaledDown"
+	reasonScaledUp             = "ScaledUp"
+	reasonSkippedExcluded      = "SkippedExcluded"
+	reasonInGracePeriod        = "InGracePeriod"
+	reasonChildrenNotScaledUp  = "ChildrenNotScaledUp"
+)
 
 // Logger handles logging for both namespaces and workloads.
 type ResourceLogger struct {
@@ -51,6 +63,86 @@ func (r ResourceLogger) ErrorIncompatibleFields(message string, ctx context.Cont
 	}
 }
 
+// InfoManualOverride adds an event on the target that its manual changes are respected instead of being reverted.
+func (r ResourceLogger) InfoManualOverride(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonManualOverride, reasonManualOverride, message, ctx)
+	if err != nil {
+		slog.Error("failed to add manual override event", "error", err)
+	}
+}
+
+// ErrorCircuitBreakerTripped adds an event on the target that a scan cycle was aborted by the circuit breaker.
+func (r ResourceLogger) ErrorCircuitBreakerTripped(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeWarning, reasonCircuitBreaker, reasonCircuitBreaker, message, ctx)
+	if err != nil {
+		slog.Error("failed to add circuit breaker event", "error", err)
+	}
+}
+
+// InfoUpscaleSucceeded adds an event on the target that it became ready after being scaled up.
+func (r ResourceLogger) InfoUpscaleSucceeded(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonUpscaleSucceeded, reasonUpscaleSucceeded, message, ctx)
+	if err != nil {
+		slog.Error("failed to add upscale succeeded event", "error", err)
+	}
+}
+
+// ErrorUpscaleFailed adds an event on the target that it didn't become ready after being scaled up.
+func (r ResourceLogger) ErrorUpscaleFailed(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeWarning, reasonUpscaleFailed, reasonUpscaleFailed, message, ctx)
+	if err != nil {
+		slog.Error("failed to add upscale failed event", "error", err)
+	}
+}
+
+// WarnUpscaleNotReady adds an event on the target that it isn't ready yet after being scaled up and is watched again.
+func (r ResourceLogger) WarnUpscaleNotReady(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeWarning, reasonUpscaleNotReady, reasonUpscaleNotReady, message, ctx)
+	if err != nil {
+		slog.Error("failed to add upscale not ready event", "error", err)
+	}
+}
+
+// InfoScaledDown adds an event on the target that it was scaled down.
+func (r ResourceLogger) InfoScaledDown(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonScaledDown, reasonScaledDown, message, ctx)
+	if err != nil {
+		slog.Error("failed to add scaled down event", "error", err)
+	}
+}
+
+// InfoScaledUp adds an event on the target that it was scaled up.
+func (r ResourceLogger) InfoScaledUp(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonScaledUp, reasonScaledUp, message, ctx)
+	if err != nil {
+		slog.Error("failed to add scaled up event", "error", err)
+	}
+}
+
+// InfoSkippedExcluded adds an event on the target that it was skipped because it is excluded.
+func (r ResourceLogger) InfoSkippedExcluded(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonSkippedExcluded, reasonSkippedExcluded, message, ctx)
+	if err != nil {
+		slog.Error("failed to add skipped excluded event", "error", err)
+	}
+}
+
+// InfoInGracePeriod adds an event on the target that it was skipped because it is in its grace period.
+func (r ResourceLogger) InfoInGracePeriod(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeNormal, reasonInGracePeriod, reasonInGracePeriod, message, ctx)
+	if err != nil {
+		slog.Error("failed to add grace period event", "error", err)
+	}
+}
+
+// WarnChildrenNotScaledUp adds an event on the target that it was scaled up before all of its children were scaled up.
+func (r ResourceLogger) WarnChildrenNotScaledUp(message string, ctx context.Context) {
+	err := r.logger.log(v1.EventTypeWarning, reasonChildrenNotScaledUp, reasonChildrenNotScaledUp, message, ctx)
+	if err != nil {
+		slog.Error("failed to add children not scaled up event", "error", err)
+	}
+}
+
 // resourceLogger is the interface that all loggers (namespace and workload) implement.
 type resourceLogger interface {
 	log(eventType, reason, identifier, message string, ctx context.Context) error
@@ -0,0 +1,587 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StateStoreAnnotation keeps the original replicas in an annotation on the workload.
+	StateStoreAnnotation = "annotation"
+	// StateStoreConfigMap keeps the original replicas in a ConfigMap per namespace.
+	StateStoreConfigMap = "configmap"
+	// StateStoreCRD keeps the original replicas in a DownscalerState custom resource per namespace.
+	StateStoreCRD = "crd"
+
+	stateObjectName = "kube-downscaler-state"
+)
+
+var downscalerStateGVK = schema.GroupVersionKind{Group: "kube-downscaler.k8s", Version: "v1alpha1", Kind: "DownscalerState"}
+
+// workloadState is the state of a scaled down workload kept in a stateStore.
+type workloadState struct {
+	// Name is the name of the workload, only used to make the stored state readable
+	Name string `json:"name"`
+	// OriginalReplicas are the replicas the workload had before being scaled down
+	OriginalReplicas string `json:"originalReplicas"`
+	// OriginalState is the state restored besides the original replicas, keyed by the annotation it would be kept in otherwise
+	OriginalState map[string]string `json:"originalState,omitempty"`
+}
+
+// newWorkloadState gets the state of the workload which is kept in a stateStore.
+func newWorkloadState(workload scalable.Workload, originalReplicas string) workloadState {
+	state := workloadState{Name: workload.GetName(), OriginalReplicas: originalReplicas}
+
+	if originalState := scalable.GetOriginalStateValues(workload); len(originalState) > 0 {
+		state.OriginalState = originalState
+	}
+
+	return state
+}
+
+// equal checks if both states would restore the workload the same way.
+func (w workloadState) equal(other workloadState) bool {
+	return w.OriginalReplicas == other.OriginalReplicas && maps.Equal(w.OriginalState, other.OriginalState)
+}
+
+// stateStore keeps the original replicas of scaled down workloads outside of the workloads themselves.
+// The states of a namespace are keyed by the uid of their workload.
+type stateStore interface {
+	// load gets the states of all workloads in the namespace
+	load(namespace string, ctx context.Context) (map[string]workloadState, error)
+	// modify applies the change to the states of the namespace, retrying on conflicts
+	modify(namespace string, change func(states map[string]workloadState), ctx context.Context) error
+}
+
+// newStateStore creates the state store for the backend. The annotation backend doesn't need a store, so nil is returned.
+//
+//nolint:ireturn // the backend is selected at runtime
+func newStateStore(backend string, kubernetesClientset kubernetes.Interface, ctrlClient ctrlclient.Client) (stateStore, error) {
+	switch backend {
+	case StateStoreAnnotation:
+		return nil, nil
+	case StateStoreConfigMap:
+		return &configMapStateStore{clientset: kubernetesClientset}, nil
+	case StateStoreCRD:
+		return &crdStateStore{client: ctrlClient}, nil
+	default:
+		return nil, newInvalidStateStoreError(backend)
+	}
+}
+
+// isStateWriteConflict checks if writing the states failed because they were changed or created concurrently.
+func isStateWriteConflict(err error) bool {
+	return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+}
+
+// configMapStateStore keeps the states of each namespace as json values in a ConfigMap.
+type configMapStateStore struct {
+	clientset kubernetes.Interface
+}
+
+func (c *configMapStateStore) load(namespace string, ctx context.Context) (map[string]workloadState, error) {
+	configMap, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, stateObjectName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return map[string]workloadState{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state configmap: %w", err)
+	}
+
+	return decodeConfigMapStates(configMap)
+}
+
+func (c *configMapStateStore) modify(namespace string, change func(states map[string]workloadState), ctx context.Context) error {
+	err := retry.OnError(retry.DefaultRetry, isStateWriteConflict, func() error {
+		configMap, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, stateObjectName, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get state configmap: %w", err)
+		}
+
+		exists := err == nil
+		if !exists {
+			configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: stateObjectName, Namespace: namespace}}
+		}
+
+		states, err := decodeConfigMapStates(configMap)
+		if err != nil {
+			return err
+		}
+
+		change(states)
+
+		configMap.Data = make(map[string]string, len(states))
+		for uid, state := range states {
+			stateJSON, err := json.Marshal(state)
+			if err != nil {
+				return fmt.Errorf("failed to marshal state of workload %q: %w", state.Name, err)
+			}
+
+			configMap.Data[uid] = string(stateJSON)
+		}
+
+		if !exists {
+			_, err = c.clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{FieldManager: scalable.FieldManager})
+			if err != nil {
+				return fmt.Errorf("failed to create state configmap: %w", err)
+			}
+
+			return nil
+		}
+
+		_, err = c.clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{FieldManager: scalable.FieldManager})
+		if err != nil {
+			return fmt.Errorf("failed to update state configmap: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write states to configmap: %w", err)
+	}
+
+	return nil
+}
+
+// decodeConfigMapStates decodes the states stored in the ConfigMap.
+func decodeConfigMapStates(configMap *corev1.ConfigMap) (map[string]workloadState, error) {
+	states := make(map[string]workloadState, len(configMap.Data))
+
+	for uid, stateJSON := range configMap.Data {
+		var state workloadState
+
+		err := json.Unmarshal([]byte(stateJSON), &state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse state of workload with uid %q: %w", uid, err)
+		}
+
+		states[uid] = state
+	}
+
+	return states, nil
+}
+
+// crdStateStore keeps the states of each namespace in the spec of a DownscalerState custom resource.
+type crdStateStore struct {
+	client ctrlclient.Client
+}
+
+func (c *crdStateStore) load(namespace string, ctx context.Context) (map[string]workloadState, error) {
+	downscalerState := &unstructured.Unstructured{}
+	downscalerState.SetGroupVersionKind(downscalerStateGVK)
+
+	err := c.client.Get(ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: stateObjectName}, downscalerState)
+	if errors.IsNotFound(err) {
+		return map[string]workloadState{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get downscalerstate: %w", err)
+	}
+
+	return decodeCRDStates(downscalerState)
+}
+
+func (c *crdStateStore) modify(namespace string, change func(states map[string]workloadState), ctx context.Context) error {
+	err := retry.OnError(retry.DefaultRetry, isStateWriteConflict, func() error {
+		downscalerState := &unstructured.Unstructured{}
+		downscalerState.SetGroupVersionKind(downscalerStateGVK)
+
+		err := c.client.Get(ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: stateObjectName}, downscalerState)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get downscalerstate: %w", err)
+		}
+
+		exists := err == nil
+		if !exists {
+			downscalerState.SetName(stateObjectName)
+			downscalerState.SetNamespace(namespace)
+		}
+
+		states, err := decodeCRDStates(downscalerState)
+		if err != nil {
+			return err
+		}
+
+		change(states)
+
+		workloads := make(map[string]any, len(states))
+		for uid, state := range states {
+			workload := map[string]any{"name": state.Name, "originalReplicas": state.OriginalReplicas}
+
+			if len(state.OriginalState) > 0 {
+				originalState := make(map[string]any, len(state.OriginalState))
+				for annotation, value := range state.OriginalState {
+					originalState[annotation] = value
+				}
+
+				workload["originalState"] = originalState
+			}
+
+			workloads[uid] = workload
+		}
+
+		err = unstructured.SetNestedMap(downscalerState.Object, workloads, "spec", "workloads")
+		if err != nil {
+			return fmt.Errorf("failed to set workloads on downscalerstate: %w", err)
+		}
+
+		if !exists {
+			err = c.client.Create(ctx, downscalerState, ctrlclient.FieldOwner(scalable.FieldManager))
+			if err != nil {
+				return fmt.Errorf("failed to create downscalerstate: %w", err)
+			}
+
+			return nil
+		}
+
+		err = c.client.Update(ctx, downscalerState, ctrlclient.FieldOwner(scalable.FieldManager))
+		if err != nil {
+			return fmt.Errorf("failed to update downscalerstate: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write states to downscalerstate: %w", err)
+	}
+
+	return nil
+}
+
+// decodeCRDStates decodes the states stored in the DownscalerState custom resource.
+func decodeCRDStates(downscalerState *unstructured.Unstructured) (map[string]workloadState, error) {
+	workloads, _, err := unstructured.NestedMap(downscalerState.Object, "spec", "workloads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workloads from downscalerstate: %w", err)
+	}
+
+	states := make(map[string]workloadState, len(workloads))
+
+	for uid := range workloads {
+		name, _, _ := unstructured.NestedString(workloads, uid, "name")
+
+		originalReplicas, found, err := unstructured.NestedString(workloads, uid, "originalReplicas")
+		if err != nil || !found {
+			return nil, newInvalidWorkloadStateError(uid)
+		}
+
+		originalState, _, err := unstructured.NestedStringMap(workloads, uid, "originalState")
+		if err != nil {
+			return nil, newInvalidWorkloadStateError(uid)
+		}
+
+		state := workloadState{Name: name, OriginalReplicas: originalReplicas}
+		if len(originalState) > 0 {
+			state.OriginalState = originalState
+		}
+
+		states[uid] = state
+	}
+
+	return states, nil
+}
+
+// reconcileStates loads the stored states into the workloads of each namespace.
+// Workloads of namespaces whose states couldn't be reconciled are removed,
+// since scaling them without their state would lose their original replicas.
+// Stale states are only detected in namespaces where all resource types were listed successfully.
+func (c client) reconcileStates(workloads []scalable.Workload, failedNamespaces []string, ctx context.Context) ([]scalable.Workload, error) {
+	if c.stateStore == nil {
+		return workloads, nil
+	}
+
+	namespaceWorkloads := make(map[string][]scalable.Workload)
+	for _, workload := range workloads {
+		namespaceWorkloads[workload.GetNamespace()] = append(namespaceWorkloads[workload.GetNamespace()], workload)
+	}
+
+	results := make([]scalable.Workload, 0, len(workloads))
+
+	var reconcileErrors []error
+
+	for _, namespace := range slices.Sorted(maps.Keys(namespaceWorkloads)) {
+		detectStale := !slices.Contains(failedNamespaces, namespace) && !slices.Contains(failedNamespaces, "")
+
+		reconciled, err := c.reconcileNamespaceStates(namespace, namespaceWorkloads[namespace], detectStale, ctx)
+		if err != nil {
+			reconcileErrors = append(reconcileErrors, newNamespaceError(namespace, fmt.Errorf("failed to reconcile stored states: %w", err)))
+			continue
+		}
+
+		results = append(results, reconciled...)
+	}
+
+	return results, stdErrors.Join(reconcileErrors...)
+}
+
+// reconcileNamespaceStates loads the stored states into the workloads of the namespace.
+// Original replicas annotations are migrated into the store and removed from the workloads.
+// States of workloads which were recreated under the same name are dropped, since they don't belong to the new workload.
+// Workloads whose annotation couldn't be removed are skipped, so they aren't scaled until they were migrated.
+//
+//nolint:cyclop // the reconciliation is easier to follow in one place
+func (c client) reconcileNamespaceStates(
+	namespace string,
+	workloads []scalable.Workload,
+	detectStale bool,
+	ctx context.Context,
+) ([]scalable.Workload, error) {
+	states, err := c.stateStore.load(namespace, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stored states: %w", err)
+	}
+
+	listedUIDs := make(map[string]struct{}, len(workloads))
+	unstoredNames := make(map[string]struct{}, len(workloads))
+	migrations := map[string]workloadState{}
+
+	var annotatedWorkloads []scalable.Workload
+
+	for _, workload := range workloads {
+		uid := string(workload.GetUID())
+		listedUIDs[uid] = struct{}{}
+
+		annotatedReplicas, isAnnotated := scalable.GetOriginalReplicasValue(workload)
+		state, isStored := states[uid]
+
+		if isAnnotated {
+			annotatedWorkloads = append(annotatedWorkloads, workload)
+		}
+
+		switch {
+		case isStored && isAnnotated && !state.equal(newWorkloadState(workload, annotatedReplicas)):
+			slog.Warn(
+				"original replicas annotations differ from the stored state, using the stored state",
+				"workload", workload.GetName(),
+				"namespace", namespace,
+				"annotation", annotatedReplicas,
+				"stored", state.OriginalReplicas,
+			)
+			restoreWorkloadState(workload, state)
+		case isStored:
+			restoreWorkloadState(workload, state)
+		case isAnnotated:
+			slog.Info("migrating original replicas annotations into the state store", "workload", workload.GetName(), "namespace", namespace)
+			migrations[uid] = newWorkloadState(workload, annotatedReplicas)
+		default:
+			unstoredNames[workload.GetName()] = struct{}{}
+		}
+	}
+
+	var staleUIDs []string
+
+	for uid, state := range states {
+		_, isListed := listedUIDs[uid]
+		_, isRecreated := unstoredNames[state.Name]
+
+		if detectStale && !isListed && isRecreated {
+			slog.Warn(
+				"workload was recreated and lost its stored original replicas, dropping the stale state",
+				"workload", state.Name,
+				"namespace", namespace,
+				"originalReplicas", state.OriginalReplicas,
+			)
+
+			staleUIDs = append(staleUIDs, uid)
+		}
+	}
+
+	if c.dryRun {
+		if len(migrations) > 0 || len(staleUIDs) > 0 {
+			slog.Info("running in dry run mode, would have updated the state store", "namespace", namespace)
+		}
+
+		return workloads, nil
+	}
+
+	if len(migrations) > 0 || len(staleUIDs) > 0 {
+		err = c.stateStore.modify(namespace, func(states map[string]workloadState) {
+			maps.Copy(states, migrations)
+
+			for _, uid := range staleUIDs {
+				delete(states, uid)
+			}
+		}, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store migrated states: %w", err)
+		}
+	}
+
+	var unmigrated []scalable.Workload
+
+	for _, workload := range annotatedWorkloads {
+		err = c.removeOriginalReplicasAnnotation(workload, ctx)
+		if err != nil {
+			slog.Warn(
+				"failed to remove the original replicas annotation after storing it, skipping workload",
+				"error", err,
+				"workload", workload.GetName(),
+				"namespace", namespace,
+			)
+
+			unmigrated = append(unmigrated, workload)
+		}
+	}
+
+	return slices.DeleteFunc(workloads, func(workload scalable.Workload) bool { return slices.Contains(unmigrated, workload) }), nil
+}
+
+// restoreWorkloadState sets the stored state on the in-memory workload.
+func restoreWorkloadState(workload scalable.Workload, state workloadState) {
+	scalable.SetOriginalReplicasValue(workload, state.OriginalReplicas)
+	scalable.RemoveOriginalStateValues(workload)
+	scalable.SetOriginalStateValues(workload, state.OriginalState)
+}
+
+// removeOriginalReplicasAnnotation removes the original replicas and the state restored besides them from the workload's
+// annotations on Kubernetes, while keeping them on the in-memory workload.
+func (c client) removeOriginalReplicasAnnotation(workload scalable.Workload, ctx context.Context) error {
+	original, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	stripped, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	stripStoredState(stripped)
+
+	err = scalable.PatchWorkload(original, stripped, c.clientsets, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to remove original replicas annotation: %w", err)
+	}
+
+	workload.SetResourceVersion(stripped.GetResourceVersion())
+
+	return nil
+}
+
+// patchWorkload applies the changes made to the workload since original was copied.
+// When a state store is used the original replicas and their state are kept in the store instead of the workload.
+// They are stored before a workload is scaled down and only removed after it was scaled up, so they can't get lost.
+func (c client) patchWorkload(original, workload scalable.Workload, ctx context.Context) error {
+	if c.stateStore == nil {
+		return scalable.PatchWorkload(original, workload, c.clientsets, ctx) //nolint:wrapcheck // the caller adds the context
+	}
+
+	_, wasScaledDown := scalable.GetOriginalReplicasValue(original)
+	_, isScaledDown := scalable.GetOriginalReplicasValue(workload)
+
+	err := c.storeChangedState(original, workload, ctx)
+	if err != nil {
+		return err
+	}
+
+	stripped, err := workload.Copy()
+	if err != nil {
+		return fmt.Errorf("failed to copy workload: %w", err)
+	}
+
+	// the original replicas and their state only exist in memory, so they mustn't be part of the patch
+	stripStoredState(original)
+	stripStoredState(stripped)
+
+	err = scalable.PatchWorkload(original, stripped, c.clientsets, ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // the caller adds the context
+	}
+
+	// following patches of the workload are locked to the resource version of its patched copy
+	workload.SetResourceVersion(stripped.GetResourceVersion())
+
+	if wasScaledDown && !isScaledDown {
+		err = c.stateStore.modify(workload.GetNamespace(), func(states map[string]workloadState) {
+			delete(states, string(workload.GetUID()))
+		}, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to remove stored original replicas: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// storeChangedState stores the state of the scaled down workload if it changed since original was copied.
+func (c client) storeChangedState(original, workload scalable.Workload, ctx context.Context) error {
+	previousReplicas, wasScaledDown := scalable.GetOriginalReplicasValue(original)
+	originalReplicas, isScaledDown := scalable.GetOriginalReplicasValue(workload)
+
+	state := newWorkloadState(workload, originalReplicas)
+
+	if !isScaledDown || (wasScaledDown && newWorkloadState(original, previousReplicas).equal(state)) {
+		return nil
+	}
+
+	err := c.stateStore.modify(workload.GetNamespace(), func(states map[string]workloadState) {
+		states[string(workload.GetUID())] = state
+	}, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store original replicas: %w", err)
+	}
+
+	return nil
+}
+
+// stripStoredState removes the original replicas and the state restored besides them from the in-memory workload.
+func stripStoredState(workload scalable.Workload) {
+	scalable.RemoveOriginalReplicasValue(workload)
+	scalable.RemoveOriginalStateValues(workload)
+}
+
+// LoadAdmittedWorkloadState loads the stored state of the workload of an admission request into its annotations,
+// so the workload is mutated like one which keeps its state in annotations.
+// Workloads which are being created don't have a uid yet, so their state is kept in annotations until it gets migrated.
+func (c client) LoadAdmittedWorkloadState(workload scalable.Workload, ctx context.Context) error {
+	if c.stateStore == nil || workload.GetUID() == "" {
+		return nil
+	}
+
+	states, err := c.stateStore.load(workload.GetNamespace(), ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load stored states: %w", err)
+	}
+
+	if state, isStored := states[string(workload.GetUID())]; isStored {
+		restoreWorkloadState(workload, state)
+	}
+
+	return nil
+}
+
+// StoreAdmittedWorkloadState stores the state the mutated workload of an admission request got
+// and removes it from both workloads, so it isn't part of the patch generated from them.
+// Workloads which are being created don't have a uid yet, so their state is kept in annotations until it gets migrated.
+func (c client) StoreAdmittedWorkloadState(original, mutated scalable.Workload, ctx context.Context) error {
+	if c.stateStore == nil || mutated.GetUID() == "" {
+		return nil
+	}
+
+	err := c.storeChangedState(original, mutated, ctx)
+	if err != nil {
+		return err
+	}
+
+	stripStoredState(original)
+	stripStoredState(mutated)
+
+	return nil
+}
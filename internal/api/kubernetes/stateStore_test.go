@@ -0,0 +1,285 @@
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/scalable"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestDeployment(t *testing.T, name, uid string, annotations string) scalable.Workload {
+	t.Helper()
+
+	rawDeployment := fmt.Sprintf(
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":%q,"namespace":"default","uid":%q,"annotations":{%s}}}`,
+		name, uid, annotations,
+	)
+
+	workload, err := scalable.ParseWorkloadFromRawObject("deployment", []byte(rawDeployment))
+	require.NoError(t, err)
+
+	return workload
+}
+
+func TestConfigMapStateStore(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Empty(t, states)
+
+	err = store.modify("default", func(states map[string]workloadState) {
+		states["uid-1"] = workloadState{Name: "app-1", OriginalReplicas: "3"}
+		states["uid-2"] = workloadState{Name: "app-2", OriginalReplicas: "1"}
+	}, t.Context())
+	require.NoError(t, err)
+
+	err = store.modify("default", func(states map[string]workloadState) {
+		delete(states, "uid-2")
+	}, t.Context())
+	require.NoError(t, err)
+
+	states, err = store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]workloadState{"uid-1": {Name: "app-1", OriginalReplicas: "3"}}, states)
+}
+
+func TestReconcileNamespaceStates(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	err := store.modify("default", func(states map[string]workloadState) {
+		states["stored-uid"] = workloadState{Name: "stored", OriginalReplicas: "2"}
+		states["old-uid"] = workloadState{Name: "recreated", OriginalReplicas: "4"}
+		states["child-uid"] = workloadState{Name: "child", OriginalReplicas: "1"}
+	}, t.Context())
+	require.NoError(t, err)
+
+	stored := newTestDeployment(t, "stored", "stored-uid", "")
+	annotated := newTestDeployment(t, "annotated", "annotated-uid", `"downscaler/original-replicas":"5"`)
+	recreated := newTestDeployment(t, "recreated", "new-uid", "")
+
+	kubeclient := client{stateStore: store, dryRun: true}
+
+	workloads, err := kubeclient.reconcileNamespaceStates(
+		"default",
+		[]scalable.Workload{stored, annotated, recreated},
+		true,
+		t.Context(),
+	)
+	require.NoError(t, err)
+	require.Len(t, workloads, 3)
+
+	originalReplicas, isScaledDown := scalable.GetOriginalReplicasValue(stored)
+	require.True(t, isScaledDown)
+	require.Equal(t, "2", originalReplicas)
+
+	originalReplicas, isScaledDown = scalable.GetOriginalReplicasValue(annotated)
+	require.True(t, isScaledDown)
+	require.Equal(t, "5", originalReplicas)
+
+	_, isScaledDown = scalable.GetOriginalReplicasValue(recreated)
+	require.False(t, isScaledDown, "the state of a recreated workload mustn't be applied to the new workload")
+
+	// dry run mode mustn't change the store
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+}
+
+func TestReconcileNamespaceStates_DropsStatesOfRecreatedWorkloads(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	err := store.modify("default", func(states map[string]workloadState) {
+		states["old-uid"] = workloadState{Name: "recreated", OriginalReplicas: "4"}
+		states["child-uid"] = workloadState{Name: "child", OriginalReplicas: "1"}
+	}, t.Context())
+	require.NoError(t, err)
+
+	kubeclient := client{stateStore: store}
+
+	_, err = kubeclient.reconcileNamespaceStates(
+		"default",
+		[]scalable.Workload{newTestDeployment(t, "recreated", "new-uid", "")},
+		true,
+		t.Context(),
+	)
+	require.NoError(t, err)
+
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]workloadState{"child-uid": {Name: "child", OriginalReplicas: "1"}}, states)
+}
+
+func TestReconcileNamespaceStates_RestoresOriginalState(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	err := store.modify("default", func(states map[string]workloadState) {
+		states["stored-uid"] = workloadState{
+			Name:             "stored",
+			OriginalReplicas: "2",
+			OriginalState:    map[string]string{"downscaler/original-hpa-replicas": "3"},
+		}
+	}, t.Context())
+	require.NoError(t, err)
+
+	stored := newTestDeployment(t, "stored", "stored-uid", "")
+
+	kubeclient := client{stateStore: store, dryRun: true}
+
+	_, err = kubeclient.reconcileNamespaceStates("default", []scalable.Workload{stored}, true, t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"downscaler/original-hpa-replicas": "3"}, scalable.GetOriginalStateValues(stored))
+}
+
+func TestDecodeCRDStates(t *testing.T) {
+	t.Parallel()
+
+	downscalerState := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"workloads": map[string]any{
+				"uid-1": map[string]any{"name": "app-1", "originalReplicas": "3"},
+				"uid-2": map[string]any{
+					"name":             "app-2",
+					"originalReplicas": "0",
+					"originalState":    map[string]any{"downscaler/original-automated-sync-policy": `{"prune":true}`},
+				},
+			},
+		},
+	}}
+
+	states, err := decodeCRDStates(downscalerState)
+	require.NoError(t, err)
+	require.Equal(t, map[string]workloadState{
+		"uid-1": {Name: "app-1", OriginalReplicas: "3"},
+		"uid-2": {
+			Name:             "app-2",
+			OriginalReplicas: "0",
+			OriginalState:    map[string]string{"downscaler/original-automated-sync-policy": `{"prune":true}`},
+		},
+	}, states)
+}
+
+func TestStoreAdmittedWorkloadState(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	kubeclient := client{stateStore: store}
+
+	original := newTestDeployment(t, "admitted", "admitted-uid", "")
+	mutated := newTestDeployment(t, "admitted", "admitted-uid", `"downscaler/original-replicas":"3"`)
+
+	err := kubeclient.StoreAdmittedWorkloadState(original, mutated, t.Context())
+	require.NoError(t, err)
+
+	_, isAnnotated := scalable.GetOriginalReplicasValue(mutated)
+	require.False(t, isAnnotated, "the stored state mustn't be part of the admission patch")
+
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]workloadState{"admitted-uid": {Name: "admitted", OriginalReplicas: "3"}}, states)
+
+	created := newTestDeployment(t, "created", "", `"downscaler/original-replicas":"2"`)
+
+	err = kubeclient.StoreAdmittedWorkloadState(newTestDeployment(t, "created", "", ""), created, t.Context())
+	require.NoError(t, err)
+
+	_, isAnnotated = scalable.GetOriginalReplicasValue(created)
+	require.True(t, isAnnotated, "workloads without a uid keep their state in annotations until it gets migrated")
+
+	loaded := newTestDeployment(t, "admitted", "admitted-uid", "")
+
+	err = kubeclient.LoadAdmittedWorkloadState(loaded, t.Context())
+	require.NoError(t, err)
+
+	originalReplicas, isScaledDown := scalable.GetOriginalReplicasValue(loaded)
+	require.True(t, isScaledDown)
+	require.Equal(t, "3", originalReplicas)
+}
+
+func TestStoreAdmittedWorkloadState_ArgoCDApplication(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	kubeclient := client{stateStore: store}
+
+	application, err := scalable.ParseWorkloadFromRawObject("application", []byte(
+		`{"apiVersion":"argoproj.io/v1alpha1","kind":"Application",`+
+			`"metadata":{"name":"guestbook","namespace":"default","uid":"app-uid","annotations":{}},`+
+			`"spec":{"syncPolicy":{"automated":{"selfHeal":true}}}}`,
+	))
+	require.NoError(t, err)
+
+	original, err := application.Copy()
+	require.NoError(t, err)
+
+	_, _, err = application.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+
+	err = kubeclient.StoreAdmittedWorkloadState(original, application, t.Context())
+	require.NoError(t, err)
+	require.Empty(t, scalable.GetOriginalStateValues(application), "the automated sync policy mustn't be kept in an annotation")
+
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"downscaler/original-automated-sync-policy": `{"selfHeal":true}`}, states["app-uid"].OriginalState)
+
+	err = kubeclient.LoadAdmittedWorkloadState(application, t.Context())
+	require.NoError(t, err)
+
+	_, err = application.ScaleUp()
+	require.NoError(t, err)
+
+	patch, err := original.Compare(application)
+	require.NoError(t, err)
+	require.Empty(t, patch, "the automated sync policy should be restored from the state store")
+}
+
+func TestStoreAdmittedWorkloadState_KnativeService(t *testing.T) {
+	t.Parallel()
+
+	store := &configMapStateStore{clientset: fake.NewClientset()}
+	kubeclient := client{stateStore: store}
+
+	service, err := scalable.ParseKnativeWorkloadFromRawObject("services", []byte(
+		`{"apiVersion":"serving.knative.dev/v1","kind":"Service",`+
+			`"metadata":{"name":"hello","namespace":"default","uid":"service-uid","annotations":{},"labels":{}},`+
+			`"spec":{"template":{"metadata":{"annotations":{"autoscaling.knative.dev/max-scale":"3"}}}}}`,
+	))
+	require.NoError(t, err)
+
+	original, err := service.Copy()
+	require.NoError(t, err)
+
+	_, _, err = service.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+
+	err = kubeclient.StoreAdmittedWorkloadState(original, service, t.Context())
+	require.NoError(t, err)
+	require.Empty(t, scalable.GetOriginalStateValues(service), "the original scale mustn't be kept in an annotation")
+
+	states, err := store.load("default", t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"downscaler/original-knative-scale":      ",3",
+		"downscaler/original-knative-visibility": "",
+	}, states["service-uid"].OriginalState)
+
+	err = kubeclient.LoadAdmittedWorkloadState(service, t.Context())
+	require.NoError(t, err)
+
+	_, err = service.ScaleUp()
+	require.NoError(t, err)
+
+	patch, err := original.Compare(service)
+	require.NoError(t, err)
+	require.Empty(t, patch, "the original scale should be restored from the state store")
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Source is the component which made the scaling decision.
+type Source string
+
+const (
+	// SourceScan is used for scaling decisions made while scanning workloads.
+	SourceScan Source = "scan"
+	// SourceAdmission is used for scaling decisions made while admitting workloads.
+	SourceAdmission Source = "admission"
+)
+
+// Action is the scaling action taken on the workload.
+type Action string
+
+const (
+	// ActionScaleDown is used when the workload was scaled down.
+	ActionScaleDown Action = "scale-down"
+	// ActionScaleUp is used when the workload was scaled up.
+	ActionScaleUp Action = "scale-up"
+)
+
+// Record describes a single scaling decision.
+type Record struct {
+	// Time is the time the decision was made.
+	Time time.Time `json:"time"`
+	// CycleID identifies the scan cycle or admission request the decision was made in.
+	CycleID string `json:"cycleID"`
+	// Source is the component which made the decision.
+	Source Source `json:"source"`
+	// Kind is the kind of the scaled workload.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the scaled workload.
+	Namespace string `json:"namespace"`
+	// Name is the name of the scaled workload.
+	Name string `json:"name"`
+	// Action is the scaling action taken on the workload.
+	Action Action `json:"action"`
+	// OldReplicas are the replicas of the workload before the scaling, it is empty for workloads which aren't scaled by their replicas.
+	OldReplicas string `json:"oldReplicas,omitempty"`
+	// NewReplicas are the replicas of the workload after the scaling, it is empty for workloads which aren't scaled by their replicas.
+	NewReplicas string `json:"newReplicas,omitempty"`
+	// Scope is the scope which decided the scaling.
+	Scope string `json:"scope,omitempty"`
+	// DryRun is true if the scaling was only simulated.
+	DryRun bool `json:"dryRun"`
+}
+
+// Sink stores the records of scaling decisions.
+type Sink interface {
+	// Write stores the record in the sink
+	Write(record Record, ctx context.Context) error
+	// Close releases the resources held by the sink
+	Close() error
+}
+
+// NewSink creates the sink described by the spec. The spec is either empty to disable the audit log,
+// "stdout", "file:<path>" or a http(s) url of a webhook.
+// The maximum file size in megabytes and the amount of backups are only used by file sinks.
+//
+//nolint:ireturn // this function should return an interface type
+func NewSink(spec string, maxFileSize, maxFileBackups int) (Sink, error) {
+	switch {
+	case spec == "":
+		return nopSink{}, nil
+	case spec == "stdout":
+		return newStdoutSink(), nil
+	case strings.HasPrefix(spec, "file:"):
+		return newFileSink(strings.TrimPrefix(spec, "file:"), int64(maxFileSize)*bytesPerMegabyte, maxFileBackups)
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return newWebhookSink(spec, webhookTimeout), nil
+	default:
+		return nil, newInvalidSinkError(spec)
+	}
+}
+
+// nopSink discards all records, it is used when the audit log is disabled.
+type nopSink struct{}
+
+func (nopSink) Write(Record, context.Context) error {
+	return nil
+}
+
+func (nopSink) Close() error {
+	return nil
+}
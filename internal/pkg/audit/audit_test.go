@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestRecord() Record {
+	return Record{
+		Time:        time.Date(2024, time.March, 4, 18, 0, 0, 0, time.UTC),
+		CycleID:     "cycle",
+		Source:      SourceScan,
+		Kind:        "Deployment",
+		Namespace:   "test-namespace",
+		Name:        "test-workload",
+		Action:      ActionScaleDown,
+		OldReplicas: "3",
+		NewReplicas: "0",
+		Scope:       "namespace",
+		DryRun:      false,
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     string
+		wantSink Sink
+		wantErr  bool
+	}{
+		{name: "disabled", spec: "", wantSink: nopSink{}},
+		{name: "stdout", spec: "stdout", wantSink: &stdoutSink{}},
+		{name: "file", spec: "file:" + filepath.Join(t.TempDir(), "audit.jsonl"), wantSink: &fileSink{}},
+		{name: "http webhook", spec: "http://audit.example.com", wantSink: &webhookSink{}},
+		{name: "https webhook", spec: "https://audit.example.com/records", wantSink: &webhookSink{}},
+		{name: "invalid", spec: "syslog", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			sink, err := NewSink(test.spec, 1, 1)
+			if test.wantErr {
+				var invalidSinkErr *InvalidSinkError
+				require.ErrorAs(t, err, &invalidSinkErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, test.wantSink, sink)
+			require.NoError(t, sink.Close())
+		})
+	}
+}
+
+func TestStdoutSink(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	sink := &stdoutSink{writer: &buffer}
+	require.NoError(t, sink.Write(getTestRecord(), t.Context()))
+
+	assert.JSONEq(t, `{"stream":"audit","time":"2024-03-04T18:00:00Z","cycleID":"cycle","source":"scan","kind":"Deployment",`+
+		`"namespace":"test-namespace","name":"test-workload","action":"scale-down","oldReplicas":"3","newReplicas":"0",`+
+		`"scope":"namespace","dryRun":false}`, buffer.String())
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	line, err := json.Marshal(getTestRecord())
+	require.NoError(t, err)
+
+	lineSize := int64(len(line) + 1)
+
+	tests := []struct {
+		name        string
+		maxSize     int64
+		maxBackups  int
+		writes      int
+		wantLines   int
+		wantBackups []int
+	}{
+		{name: "without rotation", maxSize: 0, maxBackups: 2, writes: 5, wantLines: 5, wantBackups: []int{}},
+		{name: "rotation", maxSize: 2 * lineSize, maxBackups: 2, writes: 5, wantLines: 1, wantBackups: []int{2, 2}},
+		{name: "rotation without backups", maxSize: 2 * lineSize, maxBackups: 0, writes: 3, wantLines: 1, wantBackups: []int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+			sink, err := newFileSink(path, test.maxSize, test.maxBackups)
+			require.NoError(t, err)
+
+			for range test.writes {
+				require.NoError(t, sink.Write(getTestRecord(), t.Context()))
+			}
+
+			require.NoError(t, sink.Close())
+
+			assert.Equal(t, test.wantLines, countLines(t, path))
+
+			for i, wantLines := range test.wantBackups {
+				assert.Equal(t, wantLines, countLines(t, sink.getBackupPath(i+1)))
+			}
+
+			assert.NoFileExists(t, sink.getBackupPath(len(test.wantBackups)+1))
+		})
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Count(string(content), "\n")
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "accepted", statusCode: http.StatusNoContent, wantErr: false},
+		{name: "rejected", statusCode: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received Record
+
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				assert.Equal(t, http.MethodPost, request.Method)
+				assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(request.Body).Decode(&received))
+				writer.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			sink := newWebhookSink(server.URL, time.Second)
+			defer sink.Close()
+
+			err := sink.Write(getTestRecord(), t.Context())
+			if test.wantErr {
+				var statusCodeErr *UnexpectedStatusCodeError
+				require.ErrorAs(t, err, &statusCodeErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, getTestRecord(), received)
+		})
+	}
+}
+
+// blockingSink collects the records written to it, blocking each write until it is released.
+type blockingSink struct {
+	release chan struct{}
+	records []Record
+	closed  bool
+}
+
+func (b *blockingSink) Write(record Record, _ context.Context) error {
+	<-b.release
+
+	b.records = append(b.records, record)
+
+	return nil
+}
+
+func (b *blockingSink) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestBufferedSink(t *testing.T) {
+	t.Parallel()
+
+	blocking := &blockingSink{release: make(chan struct{})}
+	sink := newBufferedSink(blocking, 1, time.Second)
+
+	// the first record is taken by the writer, the second one waits in the buffer
+	require.NoError(t, sink.Write(getTestRecord(), t.Context()))
+	require.Eventually(t, func() bool { return len(sink.records) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, sink.Write(getTestRecord(), t.Context()))
+
+	var bufferFullErr *BufferFullError
+	require.ErrorAs(t, sink.Write(getTestRecord(), t.Context()), &bufferFullErr, "writing to a full buffer should not block")
+
+	close(blocking.release)
+	require.NoError(t, sink.Close())
+
+	assert.Len(t, blocking.records, 2)
+	assert.True(t, blocking.closed)
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// bufferedSinkSize is the amount of records which can wait to be written before new records are dropped.
+	bufferedSinkSize = 1000
+	// bufferedWriteTimeout is how long writing a single buffered record may take.
+	bufferedWriteTimeout = 2 * time.Second
+)
+
+// bufferedSink writes the records to the wrapped sink in the background, so writing a record never blocks the caller.
+// If the buffer is full, e.g. because a webhook is unreachable, new records are dropped.
+type bufferedSink struct {
+	sink    Sink
+	records chan Record
+	done    chan struct{}
+	timeout time.Duration
+}
+
+// NewBufferedSink wraps the sink, so records are written asynchronously with a short timeout each.
+// It is used where writing records must not delay the caller, e.g. while answering admission requests.
+//
+//nolint:ireturn // this function should return an interface type
+func NewBufferedSink(sink Sink) Sink {
+	return newBufferedSink(sink, bufferedSinkSize, bufferedWriteTimeout)
+}
+
+// newBufferedSink creates a new bufferedSink and starts writing its records.
+func newBufferedSink(sink Sink, size int, timeout time.Duration) *bufferedSink {
+	buffered := &bufferedSink{
+		sink:    sink,
+		records: make(chan Record, size),
+		done:    make(chan struct{}),
+		timeout: timeout,
+	}
+
+	go buffered.run()
+
+	return buffered
+}
+
+// run writes the buffered records to the wrapped sink until the sink is closed.
+func (b *bufferedSink) run() {
+	defer close(b.done)
+
+	for record := range b.records {
+		ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+
+		err := b.sink.Write(record, ctx)
+		if err != nil {
+			slog.Error("failed to write audit record", "error", err, "workload", record.Name, "namespace", record.Namespace)
+		}
+
+		cancel()
+	}
+}
+
+func (b *bufferedSink) Write(record Record, _ context.Context) error {
+	select {
+	case b.records <- record:
+		return nil
+	default:
+		return newBufferFullError(cap(b.records))
+	}
+}
+
+// Close writes the remaining buffered records before closing the wrapped sink.
+func (b *bufferedSink) Close() error {
+	close(b.records)
+	<-b.done
+
+	return b.sink.Close() //nolint:wrapcheck // the error is already wrapped by the wrapped sink
+}
@@ -0,0 +1,39 @@
+package audit
+
+import "fmt"
+
+type InvalidSinkError struct {
+	spec string
+}
+
+func newInvalidSinkError(spec string) error {
+	return &InvalidSinkError{spec: spec}
+}
+
+func (i *InvalidSinkError) Error() string {
+	return fmt.Sprintf("invalid audit sink %q: expected \"stdout\", \"file:<path>\" or a http(s) url", i.spec)
+}
+
+type UnexpectedStatusCodeError struct {
+	statusCode int
+}
+
+func newUnexpectedStatusCodeError(statusCode int) error {
+	return &UnexpectedStatusCodeError{statusCode: statusCode}
+}
+
+func (u *UnexpectedStatusCodeError) Error() string {
+	return fmt.Sprintf("audit webhook responded with unexpected status code %d", u.statusCode)
+}
+
+type BufferFullError struct {
+	size int
+}
+
+func newBufferFullError(size int) error {
+	return &BufferFullError{size: size}
+}
+
+func (b *BufferFullError) Error() string {
+	return fmt.Sprintf("audit record dropped, %d records are already waiting to be written", b.size)
+}
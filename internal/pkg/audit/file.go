@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+const (
+	bytesPerMegabyte = 1024 * 1024
+	filePermissions  = 0o600
+)
+
+// fileSink writes the records as json lines to a file.
+// Once the file would exceed its maximum size it is rotated, keeping the configured amount of backups.
+type fileSink struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// newFileSink creates a new fileSink, appending to the file if it already exists.
+// A maxSize of zero or less disables the rotation.
+func newFileSink(path string, maxSize int64, maxBackups int) (*fileSink, error) {
+	sink := &fileSink{path: path, maxSize: maxSize, maxBackups: maxBackups}
+
+	err := sink.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (f *fileSink) Write(record Record, _ context.Context) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(line)) > f.maxSize {
+		err = f.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	written, err := f.file.Write(line)
+	f.size += int64(written)
+
+	if err != nil {
+		return fmt.Errorf("failed to write audit record to file: %w", err)
+	}
+
+	return nil
+}
+
+func (f *fileSink) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	err := f.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+
+	return nil
+}
+
+// open opens the file for appending and gets its current size.
+func (f *fileSink) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to get size of audit file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// rotate moves the current file to the first backup, shifting the existing backups and removing the oldest one.
+// Without any backups the current file is truncated.
+func (f *fileSink) rotate() error {
+	err := f.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+
+	if f.maxBackups < 1 {
+		err = os.Remove(f.path)
+	} else {
+		err = f.shiftBackups()
+	}
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to rotate audit file: %w", err)
+	}
+
+	return f.open()
+}
+
+// shiftBackups moves each backup to the next index and the current file to the first backup.
+func (f *fileSink) shiftBackups() error {
+	err := os.Remove(f.getBackupPath(f.maxBackups))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err //nolint:wrapcheck // the error is wrapped by the caller
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err = os.Rename(f.getBackupPath(i), f.getBackupPath(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err //nolint:wrapcheck // the error is wrapped by the caller
+		}
+	}
+
+	return os.Rename(f.path, f.getBackupPath(1)) //nolint:wrapcheck // the error is wrapped by the caller
+}
+
+// getBackupPath gets the path of the backup with the index, the most recent backup has the index 1.
+func (f *fileSink) getBackupPath(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// auditStream marks the lines of the stdout sink, so they can be told apart from the logs of the downscaler.
+const auditStream = "audit"
+
+// stdoutSink writes the records as json lines to stdout.
+type stdoutSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// stdoutRecord is a record written by the stdout sink.
+type stdoutRecord struct {
+	Stream string `json:"stream"`
+	Record
+}
+
+// newStdoutSink creates a new stdoutSink.
+func newStdoutSink() *stdoutSink {
+	return &stdoutSink{writer: os.Stdout}
+}
+
+func (s *stdoutSink) Write(record Record, _ context.Context) error {
+	line, err := json.Marshal(stdoutRecord{Stream: auditStream, Record: record})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.writer.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write audit record to stdout: %w", err)
+	}
+
+	return nil
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"github.com/prometheus/client_golang/prometheus"
 	k8smetrics "k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -12,6 +13,9 @@ const (
 	invalidScalingValueErrors = "invalid_scaling_value_errors"
 	conflictErrors            = "conflict_errors"
 	genericErrors             = "generic_errors"
+	namespaceErrors           = "namespace_errors"
+	succeededUpscales         = "succeeded"
+	failedUpscales            = "failed"
 )
 
 type Metrics struct {
@@ -19,10 +23,15 @@ type Metrics struct {
 	upscaledWorkloadGauge          *k8smetrics.GaugeVec
 	scalingErrorWorkloadGauge      *k8smetrics.GaugeVec
 	excludedWorkloadGauge          *k8smetrics.GaugeVec
+	manualOverrideWorkloadGauge    *k8smetrics.GaugeVec
 	savedMemoryGauge               *k8smetrics.GaugeVec
 	savedCPUGauge                  *k8smetrics.GaugeVec
 	downscalerCycleDurationSeconds *k8smetrics.Gauge
 	downscalerExecutionsTotal      *k8smetrics.Counter
+	activeResourceTypesGauge       *k8smetrics.GaugeVec
+	circuitBreakerTrippedGauge     *k8smetrics.Gauge
+	pausedGauge                    *k8smetrics.GaugeVec
+	upscaleVerificationGauge       *k8smetrics.GaugeVec
 }
 
 func NewMetrics(dryRun bool) *Metrics {
@@ -45,6 +54,13 @@ func NewMetrics(dryRun bool) *Metrics {
 				Help: helperDescription("workloads excluded from kubedownscaler management broken down by namespace.", dryRun),
 			}, []string{namespace},
 		),
+		manualOverrideWorkloadGauge: k8smetrics.NewGaugeVec(
+			&k8smetrics.GaugeOpts{
+				Name: metricName("manual_override_workloads", dryRun),
+				Help: helperDescription("scaled down workloads whose replicas were changed manually and are temporarily not scaled down"+
+					" broken down by namespace.", dryRun),
+			}, []string{namespace},
+		),
 		savedMemoryGauge: k8smetrics.NewGaugeVec(
 			&k8smetrics.GaugeOpts{
 				Name: metricName("current_saved_memory_bytes", dryRun),
@@ -77,6 +93,31 @@ func NewMetrics(dryRun bool) *Metrics {
 				Help: "Number of cycles completed by kubedownscaler since being instantiated.",
 			},
 		),
+		activeResourceTypesGauge: k8smetrics.NewGaugeVec(
+			&k8smetrics.GaugeOpts{
+				Name: "kubedownscaler_active_resource_types",
+				Help: "Included resource types broken down by whether they are served by the cluster (1) or skipped (0).",
+			}, []string{"resource_type"},
+		),
+		circuitBreakerTrippedGauge: k8smetrics.NewGauge(
+			&k8smetrics.GaugeOpts{
+				Name: "kubedownscaler_circuit_breaker_tripped",
+				Help: "Whether the last scan cycle was aborted by the circuit breaker (1) or not (0).",
+			},
+		),
+		pausedGauge: k8smetrics.NewGaugeVec(
+			&k8smetrics.GaugeOpts{
+				Name: "kubedownscaler_paused",
+				Help: "Whether the downscaler is paused (1) or not (0) broken down by pause mode.",
+			}, []string{"mode"},
+		),
+		upscaleVerificationGauge: k8smetrics.NewGaugeVec(
+			&k8smetrics.GaugeOpts{
+				Name: "kubedownscaler_upscale_verifications",
+				Help: "Number of upscaled workloads which became ready (succeeded) or stayed unready (failed) within the verification timeout" +
+					" broken down by namespace.",
+			}, []string{namespace, "result"},
+		),
 	}
 }
 
@@ -84,11 +125,61 @@ func (m *Metrics) RegisterAll() {
 	legacyregistry.MustRegister(m.downscaledWorkloadGauge)
 	legacyregistry.MustRegister(m.upscaledWorkloadGauge)
 	legacyregistry.MustRegister(m.excludedWorkloadGauge)
+	legacyregistry.MustRegister(m.manualOverrideWorkloadGauge)
 	legacyregistry.MustRegister(m.savedMemoryGauge)
 	legacyregistry.MustRegister(m.savedCPUGauge)
 	legacyregistry.MustRegister(m.scalingErrorWorkloadGauge)
 	legacyregistry.MustRegister(m.downscalerCycleDurationSeconds)
 	legacyregistry.MustRegister(m.downscalerExecutionsTotal)
+	legacyregistry.MustRegister(m.activeResourceTypesGauge)
+	legacyregistry.MustRegister(m.circuitBreakerTrippedGauge)
+	legacyregistry.MustRegister(m.pausedGauge)
+	legacyregistry.MustRegister(m.upscaleVerificationGauge)
+}
+
+// UpdateActiveResourceTypes sets which of the included resource types are currently served by the cluster.
+func (m *Metrics) UpdateActiveResourceTypes(metricsEnabled bool, activeResourceTypes, inactiveResourceTypes []string) {
+	if !metricsEnabled {
+		return
+	}
+
+	for _, resourceType := range activeResourceTypes {
+		m.activeResourceTypesGauge.WithLabelValues(resourceType).Set(1)
+	}
+
+	for _, resourceType := range inactiveResourceTypes {
+		m.activeResourceTypesGauge.WithLabelValues(resourceType).Set(0)
+	}
+}
+
+// UpdateCircuitBreaker sets whether the last scan cycle was aborted by the circuit breaker.
+func (m *Metrics) UpdateCircuitBreaker(metricsEnabled bool, tripped bool) {
+	if !metricsEnabled {
+		return
+	}
+
+	if tripped {
+		m.circuitBreakerTrippedGauge.Set(1)
+		return
+	}
+
+	m.circuitBreakerTrippedGauge.Set(0)
+}
+
+// UpdatePauseMode sets which pause mode the downscaler is currently paused with.
+func (m *Metrics) UpdatePauseMode(metricsEnabled bool, mode values.PauseMode) {
+	if !metricsEnabled {
+		return
+	}
+
+	for _, pauseMode := range values.PauseModes {
+		if pauseMode == mode {
+			m.pausedGauge.WithLabelValues(pauseMode.String()).Set(1)
+			continue
+		}
+
+		m.pausedGauge.WithLabelValues(pauseMode.String()).Set(0)
+	}
 }
 
 func (m *Metrics) UpdateMetrics(
@@ -111,8 +202,10 @@ func (m *Metrics) UpdateMetrics(
 		m.upscaledWorkloadGauge.DeleteLabelValues(previousNamespace)
 		m.scalingErrorWorkloadGauge.DeletePartialMatch(prometheus.Labels{namespace: previousNamespace})
 		m.excludedWorkloadGauge.DeleteLabelValues(previousNamespace)
+		m.manualOverrideWorkloadGauge.DeleteLabelValues(previousNamespace)
 		m.savedMemoryGauge.DeleteLabelValues(previousNamespace)
 		m.savedCPUGauge.DeleteLabelValues(previousNamespace)
+		m.upscaleVerificationGauge.DeletePartialMatch(prometheus.Labels{namespace: previousNamespace})
 	}
 
 	// update metrics for current namespaces
@@ -120,11 +213,15 @@ func (m *Metrics) UpdateMetrics(
 		m.downscaledWorkloadGauge.WithLabelValues(currentNamespace).Set(metricsRecord.DownscaledWorkloads())
 		m.upscaledWorkloadGauge.WithLabelValues(currentNamespace).Set(metricsRecord.UpscaledWorkloads())
 		m.excludedWorkloadGauge.WithLabelValues(currentNamespace).Set(metricsRecord.ExcludedWorkloads())
+		m.manualOverrideWorkloadGauge.WithLabelValues(currentNamespace).Set(metricsRecord.ManualOverrides())
 		m.scalingErrorWorkloadGauge.WithLabelValues(currentNamespace, invalidScalingValueErrors).Set(metricsRecord.InvalidScalingValueErrors())
 		m.scalingErrorWorkloadGauge.WithLabelValues(currentNamespace, conflictErrors).Set(metricsRecord.ConflictErrors())
 		m.scalingErrorWorkloadGauge.WithLabelValues(currentNamespace, genericErrors).Set(metricsRecord.GenericErrors())
+		m.scalingErrorWorkloadGauge.WithLabelValues(currentNamespace, namespaceErrors).Set(metricsRecord.NamespaceErrors())
 		m.savedMemoryGauge.WithLabelValues(currentNamespace).Set(metricsRecord.SavedMemoryBytes())
 		m.savedCPUGauge.WithLabelValues(currentNamespace).Set(metricsRecord.SavedCPUCores())
+		m.upscaleVerificationGauge.WithLabelValues(currentNamespace, succeededUpscales).Set(metricsRecord.SucceededUpscales())
+		m.upscaleVerificationGauge.WithLabelValues(currentNamespace, failedUpscales).Set(metricsRecord.FailedUpscales())
 	}
 
 	m.downscalerCycleDurationSeconds.Set(cycleDuration)
@@ -8,8 +8,12 @@ type NamespaceMetricsHolder struct {
 	invalidScalingValueErrors float64
 	conflictErrors            float64
 	genericErrors             float64
+	namespaceErrors           float64
+	manualOverrides           float64
 	savedMemoryBytes          float64
 	savedCPUcores             float64
+	succeededUpscales         float64
+	failedUpscales            float64
 }
 
 func NewNamespaceMetricsHolder() *NamespaceMetricsHolder {
@@ -20,8 +24,12 @@ func NewNamespaceMetricsHolder() *NamespaceMetricsHolder {
 		invalidScalingValueErrors: 0,
 		conflictErrors:            0,
 		genericErrors:             0,
+		namespaceErrors:           0,
+		manualOverrides:           0,
 		savedMemoryBytes:          0,
 		savedCPUcores:             0,
+		succeededUpscales:         0,
+		failedUpscales:            0,
 	}
 }
 
@@ -49,6 +57,14 @@ func (m *NamespaceMetricsHolder) GenericErrors() float64 {
 	return m.genericErrors
 }
 
+func (m *NamespaceMetricsHolder) NamespaceErrors() float64 {
+	return m.namespaceErrors
+}
+
+func (m *NamespaceMetricsHolder) ManualOverrides() float64 {
+	return m.manualOverrides
+}
+
 func (m *NamespaceMetricsHolder) SavedMemoryBytes() float64 {
 	return m.savedMemoryBytes
 }
@@ -57,6 +73,14 @@ func (m *NamespaceMetricsHolder) SavedCPUCores() float64 {
 	return m.savedCPUcores
 }
 
+func (m *NamespaceMetricsHolder) SucceededUpscales() float64 {
+	return m.succeededUpscales
+}
+
+func (m *NamespaceMetricsHolder) FailedUpscales() float64 {
+	return m.failedUpscales
+}
+
 func (m *NamespaceMetricsHolder) IncrementDownscaledWorkloadsCount() {
 	if m != nil {
 		m.downscaledWorkloads++
@@ -93,9 +117,41 @@ func (m *NamespaceMetricsHolder) IncrementGenericErrorsCount() {
 	}
 }
 
+func (m *NamespaceMetricsHolder) IncrementNamespaceErrorsCount() {
+	if m != nil {
+		m.namespaceErrors++
+	}
+}
+
+func (m *NamespaceMetricsHolder) IncrementManualOverridesCount() {
+	if m != nil {
+		m.manualOverrides++
+	}
+}
+
+func (m *NamespaceMetricsHolder) IncrementSucceededUpscalesCount() {
+	if m != nil {
+		m.succeededUpscales++
+	}
+}
+
+func (m *NamespaceMetricsHolder) IncrementFailedUpscalesCount() {
+	if m != nil {
+		m.failedUpscales++
+	}
+}
+
 func (m *NamespaceMetricsHolder) IncrementSavedResources(savedResources *SavedResources) {
 	if m != nil {
 		m.savedMemoryBytes += savedResources.TotalMemory()
 		m.savedCPUcores += savedResources.TotalCPU()
 	}
 }
+
+// AddUpscaleVerifications adds the upscale verification results of another holder to the holder.
+func (m *NamespaceMetricsHolder) AddUpscaleVerifications(results *NamespaceMetricsHolder) {
+	if m != nil && results != nil {
+		m.succeededUpscales += results.succeededUpscales
+		m.failedUpscales += results.failedUpscales
+	}
+}
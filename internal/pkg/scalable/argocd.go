@@ -0,0 +1,207 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// annotationOriginalAutomatedSyncPolicy keeps the original automated sync policy while the application is scaled down.
+// When a state store is used the policy is only kept in the annotation in memory and stored with the original replicas.
+const annotationOriginalAutomatedSyncPolicy = "downscaler/original-automated-sync-policy"
+
+//nolint:gochecknoglobals // package-level resources required for the dynamic client
+var argoCDApplicationResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+
+//nolint:gochecknoglobals // package-level path required for the unstructured object
+var automatedSyncPolicyPath = []string{"spec", "syncPolicy", "automated"}
+
+// getArgoCDApplications is the getResourceFunc for Argo CD Applications.
+func getArgoCDApplications(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+	return listDynamicWorkloads(argoCDApplicationResource, namespace, clientsets, ctx, func(item *unstructured.Unstructured) Workload {
+		return &suspendScaledWorkload{&argoCDApplication{item}}
+	})
+}
+
+// parseArgoCDApplicationFromBytes parses the admission review and returns the Argo CD Application.
+func parseArgoCDApplicationFromBytes(rawObject []byte) (Workload, error) {
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode application: %w", err)
+	}
+
+	return &suspendScaledWorkload{&argoCDApplication{&u}}, nil
+}
+
+// argoCDApplication is an Argo CD Application, which is scaled by pausing its automated sync.
+// While scaled down its automated sync policy is removed and kept in an annotation, so self-heal doesn't revert its scaled down children.
+type argoCDApplication struct {
+	*unstructured.Unstructured
+}
+
+// getSuspend gets if the automated sync of the application is paused and the target downscale state for it.
+// An automated sync policy which is explicitly disabled counts as paused.
+//
+//nolint:nonamedreturns // required to better understand the function
+func (a *argoCDApplication) getSuspend() (currentValue, targetDownscaleState values.Replicas) {
+	automated, found, _ := unstructured.NestedMap(a.Object, automatedSyncPolicyPath...)
+
+	enabled, isSet := automated["enabled"].(bool)
+	paused := !found || (isSet && !enabled)
+
+	currentValue = values.BooleanReplicas(paused)
+	targetDownscaleState = values.BooleanReplicas(true)
+
+	return currentValue, targetDownscaleState
+}
+
+// setSuspend pauses the automated sync by moving the automated sync policy to an annotation, or restores it from the annotation.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (a *argoCDApplication) setSuspend(suspend bool) {
+	annotations := a.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if suspend {
+		automated, found, _ := unstructured.NestedMap(a.Object, automatedSyncPolicyPath...)
+		if !found {
+			return
+		}
+
+		originalPolicy, err := json.Marshal(automated)
+		if err != nil {
+			slog.Error("failed to save the automated sync policy", "workload", a.GetName(), "namespace", a.GetNamespace(), "error", err)
+			return
+		}
+
+		annotations[annotationOriginalAutomatedSyncPolicy] = string(originalPolicy)
+		a.SetAnnotations(annotations)
+		unstructured.RemoveNestedField(a.Object, automatedSyncPolicyPath...)
+
+		return
+	}
+
+	originalPolicy, found := annotations[annotationOriginalAutomatedSyncPolicy]
+	if !found {
+		return
+	}
+
+	var automated map[string]any
+	if err := json.Unmarshal([]byte(originalPolicy), &automated); err != nil {
+		slog.Error("failed to restore the automated sync policy", "workload", a.GetName(), "namespace", a.GetNamespace(), "error", err)
+		return
+	}
+
+	if err := unstructured.SetNestedMap(a.Object, automated, automatedSyncPolicyPath...); err != nil {
+		slog.Error("failed to restore the automated sync policy", "workload", a.GetName(), "namespace", a.GetNamespace(), "error", err)
+		return
+	}
+
+	delete(annotations, annotationOriginalAutomatedSyncPolicy)
+	a.SetAnnotations(annotations)
+}
+
+// getSavedResourcesRequests returns the saved CPU and memory requests for the application.
+// Pausing the automated sync doesn't stop any pods, so no resources are reported; they are reported by its children instead.
+func (a *argoCDApplication) getSavedResourcesRequests() *metrics.SavedResources {
+	return metrics.NewSavedResources(0, 0)
+}
+
+// GetChildren gets the supported workloads among the resources of the application listed in its status.
+func (a *argoCDApplication) GetChildren(ctx context.Context, clientsets *Clientsets) ([]Workload, error) {
+	resources, _, err := unstructured.NestedSlice(a.Object, "status", "resources")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources of application %s/%s: %w", a.GetNamespace(), a.GetName(), err)
+	}
+
+	children := make([]childReference, 0, len(resources))
+
+	for _, resource := range resources {
+		resourceMap, ok := resource.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		group, _ := resourceMap["group"].(string)
+		kind, _ := resourceMap["kind"].(string)
+		namespace, _ := resourceMap["namespace"].(string)
+		name, _ := resourceMap["name"].(string)
+
+		resourceType, supported := managedResourceTypes[schema.GroupKind{Group: group, Kind: kind}]
+		if !supported || namespace == "" {
+			continue
+		}
+
+		children = append(children, childReference{namespace: namespace, name: name, resourceType: resourceType})
+	}
+
+	return getReferencedWorkloads(children, a.GetUID(), clientsets, ctx)
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (a *argoCDApplication) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(argoCDApplicationResource).Namespace(a.GetNamespace()).Get(ctx, a.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get application %s/%s: %w", a.GetNamespace(), a.GetName(), err)
+	}
+
+	a.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (a *argoCDApplication) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(argoCDApplicationResource).Namespace(a.GetNamespace()).
+		Patch(ctx, a.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch application %s/%s: %w", a.GetNamespace(), a.GetName(), err)
+	}
+
+	a.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the workload.
+func (a *argoCDApplication) Copy() (Workload, error) {
+	if a.Object == nil {
+		return nil, newNilUnderlyingObjectError(a.GetKind())
+	}
+
+	return &suspendScaledWorkload{&argoCDApplication{a.DeepCopy()}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (a *argoCDApplication) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	suspendScaledCopy, ok := workloadCopy.(*suspendScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*suspendScaledWorkload)(nil), workloadCopy)
+	}
+
+	applicationCopy, ok := suspendScaledCopy.suspendScaledResource.(*argoCDApplication)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*argoCDApplication)(nil), suspendScaledCopy.suspendScaledResource)
+	}
+
+	if a.Object == nil || applicationCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(a.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(a.Object, applicationCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare applications: %w", err)
+	}
+
+	return diff, nil
+}
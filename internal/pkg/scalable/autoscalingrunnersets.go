@@ -10,6 +10,7 @@ import (
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"github.com/wI2L/jsondiff"
+	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -57,10 +58,11 @@ func (a *autoscalingRunnerSet) Reget(clientsets *Clientsets, ctx context.Context
 	return nil
 }
 
-func (a *autoscalingRunnerSet) Update(clientsets *Clientsets, ctx context.Context) error {
-	err := clientsets.Client.Update(ctx, a.AutoscalingRunnerSet)
+// Patch applies the json patch to the resource.
+func (a *autoscalingRunnerSet) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	err := clientsets.Client.Patch(ctx, a.AutoscalingRunnerSet, ctrlclient.RawPatch(types.JSONPatchType, patch), getPatchOwner())
 	if err != nil {
-		return fmt.Errorf("failed to update autoscalingrunnerset: %w", err)
+		return fmt.Errorf("failed to patch autoscalingrunnerset: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,156 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	CNPGGroup = "postgresql.cnpg.io"
+
+	annotationCNPGHibernation = "cnpg.io/hibernation"
+	cnpgHibernationOn         = "on"
+)
+
+//nolint:gochecknoglobals // package-level resources required for the dynamic client
+var cnpgClusterResource = schema.GroupVersionResource{Group: CNPGGroup, Version: "v1", Resource: "clusters"}
+
+// getCNPGClusters is the getResourceFunc for CloudNativePG Clusters.
+func getCNPGClusters(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+	return listDynamicWorkloads(cnpgClusterResource, namespace, clientsets, ctx, func(item *unstructured.Unstructured) Workload {
+		return &suspendScaledWorkload{&cnpgCluster{item}}
+	})
+}
+
+// ParseCNPGWorkloadFromRawObject parses the admission review of a CloudNativePG resource.
+//
+//nolint:ireturn // this function should return an interface type
+func ParseCNPGWorkloadFromRawObject(resource string, rawObject []byte) (Workload, error) {
+	if resource != cnpgClusterResource.Resource {
+		return nil, newInvalidResourceError(resource)
+	}
+
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode cnpg cluster: %w", err)
+	}
+
+	return &suspendScaledWorkload{&cnpgCluster{&u}}, nil
+}
+
+// cnpgCluster is a CloudNativePG Cluster, which is scaled through its declarative hibernation.
+// While hibernated the operator deletes the pods of the cluster but keeps its PVCs, so it can be rehydrated with its data.
+type cnpgCluster struct {
+	*unstructured.Unstructured
+}
+
+// getSuspend gets if the cluster is hibernated and the target downscale state for it.
+//
+//nolint:nonamedreturns // required to better understand the function
+func (c *cnpgCluster) getSuspend() (currentValue, targetDownscaleState values.Replicas) {
+	currentValue = values.BooleanReplicas(c.GetAnnotations()[annotationCNPGHibernation] == cnpgHibernationOn)
+	targetDownscaleState = values.BooleanReplicas(true)
+
+	return currentValue, targetDownscaleState
+}
+
+// setSuspend hibernates the cluster or removes the hibernation annotation to rehydrate it.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (c *cnpgCluster) setSuspend(suspend bool) {
+	annotations := c.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if suspend {
+		annotations[annotationCNPGHibernation] = cnpgHibernationOn
+	} else {
+		delete(annotations, annotationCNPGHibernation)
+	}
+
+	c.SetAnnotations(annotations)
+}
+
+// getSavedResourcesRequests calculates the resource requests saved by hibernating the cluster.
+// The requests are set once for all instances, so they are multiplied by the amount of instances.
+func (c *cnpgCluster) getSavedResourcesRequests() *metrics.SavedResources {
+	instances, _, _ := unstructured.NestedInt64(c.Object, "spec", "instances")
+	cpu, memory := getCustomResourceRequests(c.Object, "{.spec.resources.requests}")
+
+	return metrics.NewSavedResources(cpu*float64(instances), memory*float64(instances))
+}
+
+// IsReady checks if all instances of the cluster are ready.
+func (c *cnpgCluster) IsReady() bool {
+	instances, _, _ := unstructured.NestedInt64(c.Object, "spec", "instances")
+	readyInstances, _, _ := unstructured.NestedInt64(c.Object, "status", "readyInstances")
+
+	return readyInstances >= instances
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (c *cnpgCluster) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(cnpgClusterResource).Namespace(c.GetNamespace()).Get(ctx, c.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get cnpg cluster %s/%s: %w", c.GetNamespace(), c.GetName(), err)
+	}
+
+	c.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (c *cnpgCluster) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(cnpgClusterResource).Namespace(c.GetNamespace()).
+		Patch(ctx, c.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch cnpg cluster %s/%s: %w", c.GetNamespace(), c.GetName(), err)
+	}
+
+	c.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the workload.
+func (c *cnpgCluster) Copy() (Workload, error) {
+	if c.Object == nil {
+		return nil, newNilUnderlyingObjectError(c.GetKind())
+	}
+
+	return &suspendScaledWorkload{&cnpgCluster{c.DeepCopy()}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (c *cnpgCluster) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	suspendScaledCopy, ok := workloadCopy.(*suspendScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*suspendScaledWorkload)(nil), workloadCopy)
+	}
+
+	clusterCopy, ok := suspendScaledCopy.suspendScaledResource.(*cnpgCluster)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*cnpgCluster)(nil), suspendScaledCopy.suspendScaledResource)
+	}
+
+	if c.Object == nil || clusterCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(c.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(c.Object, clusterCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare cnpg clusters: %w", err)
+	}
+
+	return diff, nil
+}
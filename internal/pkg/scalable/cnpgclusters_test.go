@@ -0,0 +1,32 @@
+package scalable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCNPGCluster_IsReady(t *testing.T) {
+	t.Parallel()
+
+	cluster := newTestCNPGCluster(nil, 2, nil)
+	assert.False(t, cluster.IsReady())
+
+	require.NoError(t, unstructured.SetNestedField(cluster.Object, int64(2), "status", "readyInstances"))
+	assert.True(t, cluster.IsReady())
+}
+
+func TestParseCNPGWorkloadFromRawObject(t *testing.T) {
+	t.Parallel()
+
+	rawObject := []byte(`{"apiVersion":"postgresql.cnpg.io/v1","kind":"Cluster","metadata":{"name":"db","namespace":"test"}}`)
+
+	workload, err := ParseCNPGWorkloadFromRawObject("clusters", rawObject)
+	require.NoError(t, err)
+	assert.Equal(t, "db", workload.GetName())
+
+	_, err = ParseCNPGWorkloadFromRawObject("backups", rawObject)
+	require.Error(t, err)
+}
@@ -14,6 +14,7 @@ import (
 	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getCronJobs is the getResourceFunc for CronJobs.
@@ -148,13 +149,15 @@ func (c *cronJob) setSuspend(suspend bool) {
 	c.Spec.Suspend = &suspend
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (c *cronJob) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.BatchV1().CronJobs(c.Namespace).Update(ctx, c.CronJob, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (c *cronJob) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.BatchV1().CronJobs(c.Namespace).Patch(ctx, c.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update cronjob: %w", err)
+		return fmt.Errorf("failed to patch cronjob: %w", err)
 	}
 
+	c.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
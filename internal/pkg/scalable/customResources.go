@@ -0,0 +1,390 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// CustomResource declares a resource type which is scaled by setting the field at a JSONPath.
+type CustomResource struct {
+	// Name is the resource type the custom resource is included as, e.g. in the include resources
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Path is the JSONPath to the field which is scaled
+	Path string `json:"path"`
+	// DownscaleValue is the value the field is set to while scaled down.
+	// If it isn't set, the field is scaled like replicas, using the downscale replicas
+	DownscaleValue any `json:"downscaleValue,omitempty"`
+	// ResourceRequestsPath is an optional JSONPath to the resource requests saved by scaling down.
+	// The requests are saved per replica for replica scaled custom resources
+	ResourceRequestsPath string `json:"resourceRequestsPath,omitempty"`
+
+	fieldPath []string
+}
+
+//nolint:gochecknoglobals // custom resources are loaded once on startup and used by all workload lookups
+var customResources = map[string]*CustomResource{}
+
+// LoadCustomResources loads the custom resources declared in the yaml file and makes them available as resource types.
+func LoadCustomResources(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read custom resources config: %w", err)
+	}
+
+	var declared []*CustomResource
+
+	err = yaml.Unmarshal(content, &declared)
+	if err != nil {
+		return fmt.Errorf("failed to parse custom resources config: %w", err)
+	}
+
+	loaded := make(map[string]*CustomResource, len(declared))
+
+	for _, customResource := range declared {
+		err = customResource.validate()
+		if err != nil {
+			return err
+		}
+
+		if _, exists := loaded[customResource.Name]; exists {
+			return newInvalidCustomResourceError(customResource.Name, "name is declared more than once")
+		}
+
+		loaded[customResource.Name] = customResource
+	}
+
+	customResources = loaded
+
+	return nil
+}
+
+// validate checks the custom resource and parses its path.
+func (c *CustomResource) validate() error {
+	if c.Name == "" || c.Name != strings.ToLower(c.Name) || strings.Contains(c.Name, ".") {
+		return newInvalidCustomResourceError(c.Name, "name has to be set, lowercase and without dots")
+	}
+
+	if _, err := GetGroupResource(c.Name); err == nil && customResources[c.Name] == nil {
+		return newInvalidCustomResourceError(c.Name, "name is already used by a built-in resource type")
+	}
+
+	if c.Version == "" || c.Resource == "" {
+		return newInvalidCustomResourceError(c.Name, "version and resource have to be set")
+	}
+
+	fieldPath, ok := parseFieldPath(c.Path)
+	if !ok {
+		return newInvalidCustomResourceError(c.Name, fmt.Sprintf("path %q has to only select fields, e.g. {.spec.replicas}", c.Path))
+	}
+
+	c.fieldPath = fieldPath
+
+	if c.ResourceRequestsPath != "" {
+		if err := jsonpath.New(c.Name).Parse(toJSONPathTemplate(c.ResourceRequestsPath)); err != nil {
+			return newInvalidCustomResourceError(c.Name, fmt.Sprintf("invalid resource requests path: %s", err))
+		}
+	}
+
+	return nil
+}
+
+// groupVersionResource gets the group version resource of the custom resource.
+func (c *CustomResource) groupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: c.Resource}
+}
+
+// newWorkload wraps the object of the custom resource in a Workload.
+//
+//nolint:ireturn // this function should return an interface type
+func (c *CustomResource) newWorkload(object *unstructured.Unstructured) Workload {
+	if c.DownscaleValue == nil {
+		return &replicaScaledWorkload{&specReplicasWorkload{
+			Unstructured: object,
+			resource:     c.groupVersionResource(),
+			replicasPath: c.fieldPath,
+			requestsPath: c.ResourceRequestsPath,
+		}}
+	}
+
+	return &valueScaledWorkload{&fieldValueWorkload{
+		Unstructured:   object,
+		resource:       c.groupVersionResource(),
+		valuePath:      c.fieldPath,
+		downscaleValue: fieldValueToReplicas(c.DownscaleValue),
+		requestsPath:   c.ResourceRequestsPath,
+	}}
+}
+
+// parseFieldPath parses a JSONPath which only selects fields, like "{.spec.suspend}", into its fields.
+// Other JSONPath expressions can't be used, as the field at the path has to be set. The bool is false for them.
+func parseFieldPath(path string) ([]string, bool) {
+	path = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(path), "{"), "}")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	fields := strings.Split(path, ".")
+	for _, field := range fields {
+		if field == "" || strings.ContainsAny(field, "[]*?@()'\" ") {
+			return nil, false
+		}
+	}
+
+	return fields, true
+}
+
+// toJSONPathTemplate wraps the JSONPath in braces if it isn't already, as the jsonpath package treats text outside of them literally.
+func toJSONPathTemplate(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+
+	return "{" + path + "}"
+}
+
+// getCustomResourceRequests sums the cpu and memory requests found at the JSONPath in the object.
+// Requests which can't be found or parsed are ignored, as they only affect the savings metrics.
+//
+//nolint:nonamedreturns // required to better understand the function
+func getCustomResourceRequests(object map[string]any, requestsPath string) (cpu, memory float64) {
+	if requestsPath == "" {
+		return 0, 0
+	}
+
+	parser := jsonpath.New("requests").AllowMissingKeys(true)
+	if err := parser.Parse(toJSONPathTemplate(requestsPath)); err != nil {
+		slog.Debug("failed to parse resource requests path", "error", err)
+		return 0, 0
+	}
+
+	results, err := parser.FindResults(object)
+	if err != nil {
+		slog.Debug("failed to find resource requests", "error", err)
+		return 0, 0
+	}
+
+	for _, result := range results {
+		for _, value := range result {
+			requests, ok := value.Interface().(map[string]any)
+			if !ok {
+				continue
+			}
+
+			cpu += parseQuantity(requests["cpu"])
+			memory += parseQuantity(requests["memory"])
+		}
+	}
+
+	return cpu, memory
+}
+
+// parseQuantity parses the quantity of a resource request. Missing or invalid quantities are 0.
+func parseQuantity(value any) float64 {
+	if value == nil {
+		return 0
+	}
+
+	quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+	if err != nil {
+		return 0
+	}
+
+	return quantity.AsApproximateFloat64()
+}
+
+// getCustomResourceWorkloadsFunc gets the getResourceFunc for the objects of the custom resource.
+func getCustomResourceWorkloadsFunc(customResource *CustomResource) getResourceFunc {
+	return func(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+		list, err := clientsets.Dynamic.Resource(customResource.groupVersionResource()).
+			Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", customResource.Name, err)
+		}
+
+		results := make([]Workload, 0, len(list.Items))
+		for i := range list.Items {
+			results = append(results, customResource.newWorkload(&list.Items[i]))
+		}
+
+		return results, nil
+	}
+}
+
+// GetCustomResourceName gets the name of the custom resource declared for the group resource.
+func GetCustomResourceName(groupResource schema.GroupResource) (string, bool) {
+	for name, customResource := range customResources {
+		if customResource.groupVersionResource().GroupResource() == groupResource {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// ParseCustomResourceWorkloadFromRawObject parses the admission review of a declared custom resource.
+//
+//nolint:ireturn // this function should return an interface type
+func ParseCustomResourceWorkloadFromRawObject(name string, rawObject []byte) (Workload, error) {
+	customResource, exists := customResources[name]
+	if !exists {
+		return nil, newInvalidResourceError(name)
+	}
+
+	var object unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &object); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return customResource.newWorkload(&object), nil
+}
+
+// fieldValueToReplicas converts the value of a field to the values.Replicas it is compared and stored as.
+func fieldValueToReplicas(value any) values.Replicas {
+	switch typedValue := value.(type) {
+	case nil:
+		return values.StringReplicas("")
+	case bool:
+		return values.BooleanReplicas(typedValue)
+	case string:
+		return values.StringReplicas(typedValue)
+	}
+
+	if replicas, ok := unstructuredReplicasToInt32(value); ok {
+		return values.AbsoluteReplicas(replicas)
+	}
+
+	return values.StringReplicas(fmt.Sprint(value))
+}
+
+// replicasToFieldValue converts the values.Replicas back to the value of a field.
+func replicasToFieldValue(replicas values.Replicas) any {
+	switch typedReplicas := replicas.(type) {
+	case values.BooleanReplicas:
+		return bool(typedReplicas)
+	case values.AbsoluteReplicas:
+		return int64(typedReplicas)
+	}
+
+	return replicas.String()
+}
+
+// fieldValueWorkload is a custom resource which is scaled by setting the field at its path to the downscale value.
+type fieldValueWorkload struct {
+	*unstructured.Unstructured
+	resource       schema.GroupVersionResource
+	valuePath      []string
+	downscaleValue values.Replicas
+	requestsPath   string
+}
+
+// setValue sets the value of the field. An empty value removes the field, as it wasn't set before scaling down.
+func (f *fieldValueWorkload) setValue(value values.Replicas) error {
+	if value.String() == "" {
+		unstructured.RemoveNestedField(f.Object, f.valuePath...)
+		return nil
+	}
+
+	if err := unstructured.SetNestedField(f.Object, replicasToFieldValue(value), f.valuePath...); err != nil {
+		return fmt.Errorf("failed to set value for %s %s/%s: %w", f.GetKind(), f.GetNamespace(), f.GetName(), err)
+	}
+
+	return nil
+}
+
+// getValue gets the current value of the field and the value used for downscaling.
+//
+//nolint:nonamedreturns //required to better understand the function
+func (f *fieldValueWorkload) getValue() (currentValue, downscalingValue values.Replicas, err error) {
+	value, _, err := unstructured.NestedFieldNoCopy(f.Object, f.valuePath...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get value for %s %s/%s: %w", f.GetKind(), f.GetNamespace(), f.GetName(), err)
+	}
+
+	return fieldValueToReplicas(value), f.downscaleValue, nil
+}
+
+// getSavedResourcesRequests gets the resource requests at the requests path, which are saved by downscaling the resource.
+func (f *fieldValueWorkload) getSavedResourcesRequests() *metrics.SavedResources {
+	return metrics.NewSavedResources(getCustomResourceRequests(f.Object, f.requestsPath))
+}
+
+// Copy creates a deep copy of the workload.
+func (f *fieldValueWorkload) Copy() (Workload, error) {
+	if f.Object == nil {
+		return nil, newNilUnderlyingObjectError(f.GetKind())
+	}
+
+	return &valueScaledWorkload{&fieldValueWorkload{
+		Unstructured:   f.DeepCopy(),
+		resource:       f.resource,
+		valuePath:      f.valuePath,
+		downscaleValue: f.downscaleValue,
+		requestsPath:   f.requestsPath,
+	}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (f *fieldValueWorkload) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	valueScaledCopy, ok := workloadCopy.(*valueScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*valueScaledWorkload)(nil), workloadCopy)
+	}
+
+	fieldValueCopy, ok := valueScaledCopy.valueScaledResource.(*fieldValueWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*fieldValueWorkload)(nil), valueScaledCopy.valueScaledResource)
+	}
+
+	if f.Object == nil || fieldValueCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(f.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(f.Object, fieldValueCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", f.GetKind(), err)
+	}
+
+	return diff, nil
+}
+
+// Reget regets the workload to ensure the latest state.
+func (f *fieldValueWorkload) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(f.resource).Namespace(f.GetNamespace()).Get(ctx, f.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s/%s: %w", f.resource.Resource, f.GetNamespace(), f.GetName(), err)
+	}
+
+	f.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (f *fieldValueWorkload) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(f.resource).Namespace(f.GetNamespace()).
+		Patch(ctx, f.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s/%s: %w", f.resource.Resource, f.GetNamespace(), f.GetName(), err)
+	}
+
+	f.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
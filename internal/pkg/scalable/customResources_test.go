@@ -0,0 +1,188 @@
+package scalable
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantFields []string
+		wantOk     bool
+	}{
+		{name: "jsonpath template", path: "{.spec.suspend}", wantFields: []string{"spec", "suspend"}, wantOk: true},
+		{name: "without braces", path: ".spec.size.replicas", wantFields: []string{"spec", "size", "replicas"}, wantOk: true},
+		{name: "root object", path: "$.spec.replicas", wantFields: []string{"spec", "replicas"}, wantOk: true},
+		{name: "empty", path: "", wantOk: false},
+		{name: "array index", path: "{.spec.nodes[0].replicas}", wantOk: false},
+		{name: "wildcard", path: "{.spec.*}", wantOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields, ok := parseFieldPath(test.path)
+			assert.Equal(t, test.wantOk, ok)
+			assert.Equal(t, test.wantFields, fields)
+		})
+	}
+}
+
+//nolint:paralleltest // loading custom resources replaces the package-level custom resources
+func TestLoadCustomResources(t *testing.T) {
+	previous := customResources
+
+	t.Cleanup(func() { customResources = previous })
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name: "valid custom resources",
+			config: `
+- name: widgets
+  group: example.com
+  version: v1
+  resource: widgets
+  path: "{.spec.size}"
+  resourceRequestsPath: "{.spec.template.resources.requests}"
+- name: pipelines
+  group: example.com
+  version: v1
+  resource: pipelines
+  path: .spec.suspend
+  downscaleValue: true
+`,
+			wantErr: false,
+		},
+		{
+			name: "duplicate name",
+			config: `
+- {name: widgets, group: example.com, version: v1, resource: widgets, path: .spec.size}
+- {name: widgets, group: example.com, version: v1, resource: gadgets, path: .spec.size}
+`,
+			wantErr: true,
+		},
+		{
+			name:    "name of a built-in resource type",
+			config:  `- {name: deployments, group: example.com, version: v1, resource: widgets, path: .spec.size}`,
+			wantErr: true,
+		},
+		{
+			name:    "path selecting more than fields",
+			config:  `- {name: widgets, group: example.com, version: v1, resource: widgets, path: "{.spec.nodes[*].size}"}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing version",
+			config:  `- {name: widgets, group: example.com, resource: widgets, path: .spec.size}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "custom-resources.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(test.config), 0o600))
+
+			err := LoadCustomResources(path)
+			if test.wantErr {
+				var invalidCustomResourceErr *InvalidCustomResourceError
+				require.ErrorAs(t, err, &invalidCustomResourceErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			groupResource, err := GetGroupResource("widgets")
+			require.NoError(t, err)
+			assert.Equal(t, schema.GroupResource{Group: "example.com", Resource: "widgets"}, groupResource)
+
+			name, found := GetCustomResourceName(schema.GroupResource{Group: "example.com", Resource: "pipelines"})
+			assert.True(t, found)
+			assert.Equal(t, "pipelines", name)
+		})
+	}
+}
+
+func TestFieldValueWorkload_ScaleDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	customResource := &CustomResource{
+		Name:                 "widgets",
+		Group:                "example.com",
+		Version:              "v1",
+		Resource:             "widgets",
+		Path:                 "{.spec.suspend}",
+		DownscaleValue:       true,
+		ResourceRequestsPath: "{.spec.containers[*].resources.requests}",
+		fieldPath:            []string{"spec", "suspend"},
+	}
+
+	workload := customResource.newWorkload(newTestWidget(map[string]any{
+		"containers": []any{
+			map[string]any{"resources": map[string]any{"requests": map[string]any{"cpu": "500m", "memory": "1Gi"}}},
+			map[string]any{"resources": map[string]any{"requests": map[string]any{"cpu": "1"}}},
+		},
+	}))
+
+	valueScaled, ok := workload.(*valueScaledWorkload)
+	require.True(t, ok)
+
+	fieldValue, ok := valueScaled.valueScaledResource.(*fieldValueWorkload)
+	require.True(t, ok)
+
+	savedResources, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.InDelta(t, 1.5, savedResources.TotalCPU(), 0.001)
+	assert.InDelta(t, 1024*1024*1024, savedResources.TotalMemory(), 0.001)
+
+	suspended, found, err := unstructured.NestedBool(fieldValue.Object, "spec", "suspend")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, suspended)
+
+	updated, err = workload.ScaleUp()
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	_, found, err = unstructured.NestedFieldNoCopy(fieldValue.Object, "spec", "suspend")
+	require.NoError(t, err)
+	assert.False(t, found, "a field which wasn't set before scaling down should be removed again")
+}
+
+func TestSpecReplicasWorkload_SavedResources(t *testing.T) {
+	t.Parallel()
+
+	workload := &replicaScaledWorkload{&specReplicasWorkload{
+		Unstructured: newTestWidget(map[string]any{
+			"size":     int64(3),
+			"requests": map[string]any{"cpu": "250m", "memory": "128Mi"},
+		}),
+		resource:     testWidgetResource,
+		replicasPath: []string{"spec", "size"},
+		requestsPath: ".spec.requests",
+	}}
+
+	savedResources, updated, err := workload.ScaleDown(values.AbsoluteReplicas(1))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.InDelta(t, 0.5, savedResources.TotalCPU(), 0.001)
+	assert.InDelta(t, 2*128*1024*1024, savedResources.TotalMemory(), 0.001)
+}
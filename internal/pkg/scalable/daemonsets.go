@@ -13,6 +13,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -139,13 +140,15 @@ func (d *daemonSet) getResourcesRequests(_ int32) *metrics.SavedResources {
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (d *daemonSet) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.AppsV1().DaemonSets(d.Namespace).Update(ctx, d.DaemonSet, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (d *daemonSet) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.AppsV1().DaemonSets(d.Namespace).Patch(ctx, d.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update daemonset: %w", err)
+		return fmt.Errorf("failed to patch daemonset: %w", err)
 	}
 
+	d.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getDeployments is the getResourceFunc for Deployments.
@@ -44,7 +45,7 @@ type deployment struct {
 	*appsv1.Deployment
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (d *deployment) setReplicas(replicas int32) error {
 	d.Spec.Replicas = &replicas
 	return nil
@@ -60,6 +61,16 @@ func (d *deployment) getReplicas() (values.Replicas, error) {
 	return values.AbsoluteReplicas(*replicas), nil
 }
 
+// IsReady checks if all replicas of the latest generation of the deployment are ready.
+func (d *deployment) IsReady() bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	return d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedReplicas >= replicas && d.Status.ReadyReplicas >= replicas
+}
+
 // Reget regets the resource from the Kubernetes API.
 func (d *deployment) Reget(clientsets *Clientsets, ctx context.Context) error {
 	var err error
@@ -94,13 +105,15 @@ func (d *deployment) getSavedResourcesRequests(diffReplicas int32) *metrics.Save
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (d *deployment) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.AppsV1().Deployments(d.Namespace).Update(ctx, d.Deployment, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (d *deployment) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+		return fmt.Errorf("failed to patch deployment: %w", err)
 	}
 
+	d.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
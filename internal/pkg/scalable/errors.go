@@ -2,6 +2,8 @@ package scalable
 
 import (
 	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type NoReplicasError struct {
@@ -41,6 +43,23 @@ func (o *OriginalReplicasUnsetError) Error() string {
 	return o.reason
 }
 
+type OriginalReplicasMissingError struct {
+	kind string
+	name string
+}
+
+func newOriginalReplicasMissingError(kind, name string) error {
+	return &OriginalReplicasMissingError{kind: kind, name: name}
+}
+
+func (o *OriginalReplicasMissingError) Error() string {
+	return fmt.Sprintf(
+		"error: %q %q was scaled down by the downscaler, but its original replicas are missing, e.g. because its stored state was lost; "+
+			"set the %q annotation to the replicas it should be scaled up to",
+		o.kind, o.name, annotationOriginalReplicas,
+	)
+}
+
 type UnexpectedOriginalReplicasError struct {
 	allowedValues string
 	actual        any
@@ -114,3 +133,28 @@ func newUnexpectedReplicasTypeError(val any, kind, namespace, name string) error
 func (e *UnexpectedReplicasTypeError) Error() string {
 	return fmt.Sprintf("unexpected type %s for spec.replicas on %s %s/%s", e.valType, e.kind, e.namespace, e.name)
 }
+
+type NoScaleSubresourceError struct {
+	resource schema.GroupVersionResource
+}
+
+func newNoScaleSubresourceError(resource schema.GroupVersionResource) error {
+	return &NoScaleSubresourceError{resource: resource}
+}
+
+func (n *NoScaleSubresourceError) Error() string {
+	return fmt.Sprintf("error: version %q of %q has no scale subresource", n.resource.Version, n.resource.GroupResource())
+}
+
+type InvalidCustomResourceError struct {
+	name   string
+	reason string
+}
+
+func newInvalidCustomResourceError(name, reason string) error {
+	return &InvalidCustomResourceError{name: name, reason: reason}
+}
+
+func (i *InvalidCustomResourceError) Error() string {
+	return fmt.Sprintf("error: invalid custom resource %q: %s", i.name, i.reason)
+}
@@ -0,0 +1,225 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	fluxHelmGroup      = "helm.toolkit.fluxcd.io"
+	fluxKustomizeGroup = "kustomize.toolkit.fluxcd.io"
+)
+
+//nolint:gochecknoglobals // package-level resources required for the dynamic client
+var (
+	fluxHelmReleaseResource   = schema.GroupVersionResource{Group: fluxHelmGroup, Version: "v2", Resource: "helmreleases"}
+	fluxKustomizationResource = schema.GroupVersionResource{Group: fluxKustomizeGroup, Version: "v1", Resource: "kustomizations"}
+
+	// fluxLabeledChildResourceTypes are the resource types searched for children by the labels Flux sets on the objects it applies.
+	// Only built-in resource types are searched, as the CRDs of the others aren't guaranteed to be installed.
+	fluxLabeledChildResourceTypes = []string{"deployments", "statefulsets", "daemonsets", "cronjobs"}
+)
+
+// getFluxWorkloadsFunc gets the getResourceFunc for the Flux resource.
+func getFluxWorkloadsFunc(resource schema.GroupVersionResource) getResourceFunc {
+	return func(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+		return listDynamicWorkloads(resource, namespace, clientsets, ctx, func(item *unstructured.Unstructured) Workload {
+			return &suspendScaledWorkload{&fluxResource{Unstructured: item, resource: resource}}
+		})
+	}
+}
+
+// parseHelmReleaseFromBytes parses the admission review and returns the Flux HelmRelease.
+func parseHelmReleaseFromBytes(rawObject []byte) (Workload, error) {
+	return parseFluxResourceFromBytes(rawObject, fluxHelmReleaseResource)
+}
+
+// parseKustomizationFromBytes parses the admission review and returns the Flux Kustomization.
+func parseKustomizationFromBytes(rawObject []byte) (Workload, error) {
+	return parseFluxResourceFromBytes(rawObject, fluxKustomizationResource)
+}
+
+// parseFluxResourceFromBytes parses the admission review of a Flux resource.
+func parseFluxResourceFromBytes(rawObject []byte, resource schema.GroupVersionResource) (Workload, error) {
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode flux %s: %w", resource.Resource, err)
+	}
+
+	return &suspendScaledWorkload{&fluxResource{Unstructured: &u, resource: resource}}, nil
+}
+
+// fluxResource is a Flux HelmRelease or Kustomization, which is scaled by suspending its reconciliation.
+// Its children are the objects it applied, so Flux doesn't revert them while they are scaled down.
+type fluxResource struct {
+	*unstructured.Unstructured
+	resource schema.GroupVersionResource
+}
+
+// getSuspend gets the current value of the suspend field on the flux resource and the target downscale state for it.
+//
+//nolint:nonamedreturns // required to better understand the function
+func (f *fluxResource) getSuspend() (currentValue, targetDownscaleState values.Replicas) {
+	current, _, _ := unstructured.NestedBool(f.Object, "spec", "suspend")
+
+	currentValue = values.BooleanReplicas(current)
+	targetDownscaleState = values.BooleanReplicas(true)
+
+	return currentValue, targetDownscaleState
+}
+
+// setSuspend sets the value of the suspend field on the flux resource.
+func (f *fluxResource) setSuspend(suspend bool) {
+	_ = unstructured.SetNestedField(f.Object, suspend, "spec", "suspend")
+}
+
+// getSavedResourcesRequests returns the saved CPU and memory requests for the flux resource.
+// Suspending the reconciliation doesn't stop any pods, so no resources are reported; they are reported by its children instead.
+func (f *fluxResource) getSavedResourcesRequests() *metrics.SavedResources {
+	return metrics.NewSavedResources(0, 0)
+}
+
+// GetChildren gets the workloads applied by the flux resource.
+// The objects of a Kustomization are taken from its inventory, while the objects of a HelmRelease and of Kustomizations
+// without an inventory are matched by the labels Flux sets on them.
+func (f *fluxResource) GetChildren(ctx context.Context, clientsets *Clientsets) ([]Workload, error) {
+	children, hasInventory := f.getInventoryChildren()
+	if hasInventory {
+		// a Kustomization can list itself in its inventory, so it is skipped
+		return getReferencedWorkloads(children, f.GetUID(), clientsets, ctx)
+	}
+
+	namespace, _, _ := unstructured.NestedString(f.Object, "spec", "targetNamespace")
+	if namespace == "" {
+		namespace = f.GetNamespace()
+	}
+
+	group := fluxKustomizeGroup
+	if f.resource == fluxHelmReleaseResource {
+		group = fluxHelmGroup
+	}
+
+	var results []Workload
+
+	for _, resourceType := range fluxLabeledChildResourceTypes {
+		workloads, err := getChildWorkloads(resourceType, namespace, clientsets, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get children of flux %s %s/%s: %w", f.resource.Resource, f.GetNamespace(), f.GetName(), err)
+		}
+
+		for _, workload := range workloads {
+			labels := workload.GetLabels()
+			if labels[group+"/name"] == f.GetName() && labels[group+"/namespace"] == f.GetNamespace() {
+				results = append(results, workload)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// getInventoryChildren gets the supported objects listed in the inventory of a Kustomization and if it has an inventory.
+// The ids of the inventory entries have the format "<namespace>_<name>_<group>_<kind>".
+func (f *fluxResource) getInventoryChildren() ([]childReference, bool) {
+	if f.resource != fluxKustomizationResource {
+		return nil, false
+	}
+
+	entries, found, err := unstructured.NestedSlice(f.Object, "status", "inventory", "entries")
+	if err != nil || !found {
+		return nil, false
+	}
+
+	children := make([]childReference, 0, len(entries))
+
+	for _, entry := range entries {
+		entryMap, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		id, _ := entryMap["id"].(string)
+
+		parts := strings.Split(id, "_")
+		if len(parts) != 4 || parts[0] == "" {
+			continue // cluster-scoped objects can't be scaled
+		}
+
+		resourceType, supported := managedResourceTypes[schema.GroupKind{Group: parts[2], Kind: parts[3]}]
+		if !supported {
+			continue
+		}
+
+		children = append(children, childReference{namespace: parts[0], name: parts[1], resourceType: resourceType})
+	}
+
+	return children, true
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (f *fluxResource) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(f.resource).Namespace(f.GetNamespace()).Get(ctx, f.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get flux %s %s/%s: %w", f.resource.Resource, f.GetNamespace(), f.GetName(), err)
+	}
+
+	f.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (f *fluxResource) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(f.resource).Namespace(f.GetNamespace()).
+		Patch(ctx, f.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch flux %s %s/%s: %w", f.resource.Resource, f.GetNamespace(), f.GetName(), err)
+	}
+
+	f.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the workload.
+func (f *fluxResource) Copy() (Workload, error) {
+	if f.Object == nil {
+		return nil, newNilUnderlyingObjectError(f.GetKind())
+	}
+
+	return &suspendScaledWorkload{&fluxResource{Unstructured: f.DeepCopy(), resource: f.resource}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (f *fluxResource) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	suspendScaledCopy, ok := workloadCopy.(*suspendScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*suspendScaledWorkload)(nil), workloadCopy)
+	}
+
+	fluxCopy, ok := suspendScaledCopy.suspendScaledResource.(*fluxResource)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*fluxResource)(nil), suspendScaledCopy.suspendScaledResource)
+	}
+
+	if f.Object == nil || fluxCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(f.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(f.Object, fluxCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare flux %s: %w", f.resource.Resource, err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,172 @@
+package scalable
+
+import (
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// newTestFluxResource builds a flux resource with the given spec and status.
+func newTestFluxResource(resource schema.GroupVersionResource, spec, status map[string]any) *fluxResource {
+	return &fluxResource{
+		Unstructured: &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{
+				"name":      "podinfo",
+				"namespace": "flux-system",
+				"uid":       "podinfo-uid",
+			},
+			"spec":   spec,
+			"status": status,
+		}},
+		resource: resource,
+	}
+}
+
+func TestFluxResource_ScaleDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	resource := newTestFluxResource(fluxHelmReleaseResource, map[string]any{"interval": "5m"}, nil)
+	workload := &suspendScaledWorkload{resource}
+
+	_, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	suspended, _, err := unstructured.NestedBool(resource.Object, "spec", "suspend")
+	require.NoError(t, err)
+	assert.True(t, suspended)
+
+	updated, err = workload.ScaleUp()
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	suspended, _, err = unstructured.NestedBool(resource.Object, "spec", "suspend")
+	require.NoError(t, err)
+	assert.False(t, suspended)
+}
+
+func TestFluxResource_GetInventoryChildren(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		resource         *fluxResource
+		wantChildren     []childReference
+		wantHasInventory bool
+	}{
+		{
+			name: "kustomization inventory",
+			resource: newTestFluxResource(fluxKustomizationResource, map[string]any{}, map[string]any{
+				"inventory": map[string]any{"entries": []any{
+					map[string]any{"id": "apps_frontend_apps_Deployment", "v": "v1"},
+					map[string]any{"id": "apps_frontend_networking.k8s.io_Ingress", "v": "v1"},
+					map[string]any{"id": "_apps__Namespace", "v": "v1"},
+					map[string]any{"id": "apps_backend_helm.toolkit.fluxcd.io_HelmRelease", "v": "v2"},
+				}},
+			}),
+			wantChildren: []childReference{
+				{namespace: "apps", name: "frontend", resourceType: "deployments"},
+				{namespace: "apps", name: "backend", resourceType: "helmreleases"},
+			},
+			wantHasInventory: true,
+		},
+		{
+			name:             "kustomization without inventory",
+			resource:         newTestFluxResource(fluxKustomizationResource, map[string]any{}, map[string]any{}),
+			wantChildren:     nil,
+			wantHasInventory: false,
+		},
+		{
+			name:             "helmrelease",
+			resource:         newTestFluxResource(fluxHelmReleaseResource, map[string]any{}, map[string]any{"history": []any{}}),
+			wantChildren:     nil,
+			wantHasInventory: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			children, hasInventory := test.resource.getInventoryChildren()
+			assert.Equal(t, test.wantHasInventory, hasInventory)
+			assert.Equal(t, test.wantChildren, children)
+		})
+	}
+}
+
+func TestScalesUpChildrenFirst(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		workload Workload
+		want     bool
+	}{
+		{
+			name:     "kustomization",
+			workload: &suspendScaledWorkload{newTestFluxResource(fluxKustomizationResource, map[string]any{}, map[string]any{})},
+			want:     true,
+		},
+		{
+			name:     "helmrelease",
+			workload: &suspendScaledWorkload{newTestFluxResource(fluxHelmReleaseResource, map[string]any{}, map[string]any{})},
+			want:     true,
+		},
+		{
+			name:     "deployment",
+			workload: &replicaScaledWorkload{&deployment{&appsv1.Deployment{}}},
+			want:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, ScalesUpChildrenFirst(test.workload))
+		})
+	}
+}
+
+func TestGetReferencedWorkloads(t *testing.T) {
+	t.Parallel()
+
+	parent := newTestFluxResource(fluxKustomizationResource, map[string]any{}, map[string]any{})
+	helmRelease := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2",
+		"kind":       "HelmRelease",
+		"metadata":   map[string]any{"name": "backend", "namespace": "apps"},
+	}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			fluxHelmReleaseResource:   "HelmReleaseList",
+			fluxKustomizationResource: "KustomizationList",
+		},
+		helmRelease,
+	)
+	dynamicClient.PrependReactor("list", "kustomizations", func(_ clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(fluxKustomizationResource.GroupResource(), "", nil)
+	})
+
+	children := []childReference{
+		{namespace: "apps", name: "backend", resourceType: "helmreleases"},
+		{namespace: "apps", name: "infra", resourceType: "kustomizations"},
+	}
+
+	workloads, err := getReferencedWorkloads(children, parent.GetUID(), &Clientsets{Dynamic: dynamicClient}, t.Context())
+	require.NoError(t, err, "resource types which can't be listed should have no children")
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "backend", workloads[0].GetName())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -52,7 +53,7 @@ type gateway struct {
 	*gatewayv1.Gateway
 }
 
-// setValue sets the value on the resource. Changes won't be made on Kubernetes until update() is called.
+// setValue sets the value on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (g *gateway) setValue(targetReplicas values.Replicas) error {
 	g.Spec.GatewayClassName = gatewayv1.ObjectName(targetReplicas.String())
 
@@ -86,13 +87,15 @@ func (g *gateway) Reget(clientsets *Clientsets, ctx context.Context) error {
 	return nil
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (g *gateway) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Gateway.GatewayV1().Gateways(g.Namespace).Update(ctx, g.Gateway, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (g *gateway) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Gateway.GatewayV1().Gateways(g.Namespace).Patch(ctx, g.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update gateway: %w", err)
+		return fmt.Errorf("failed to patch gateway: %w", err)
 	}
 
+	g.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
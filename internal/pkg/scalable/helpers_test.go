@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func boolAsPointer(value bool) *bool {
@@ -25,3 +26,15 @@ func assertBoolPointerEqual(t *testing.T, expected, actual *bool) {
 		assert.Equal(t, *expected, *actual)
 	}
 }
+
+// clearEmptyMetadata unsets the annotations and labels of the resource if they are empty,
+// as scaling a resource up empties the maps which were created when scaling it down.
+func clearEmptyMetadata(resource scalableResource) {
+	if len(resource.GetAnnotations()) == 0 {
+		resource.SetAnnotations(nil)
+	}
+
+	if object, ok := resource.(metav1.Object); ok && len(object.GetLabels()) == 0 {
+		object.SetLabels(nil)
+	}
+}
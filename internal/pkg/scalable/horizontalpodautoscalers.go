@@ -13,6 +13,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	appsv1 "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 var errMinReplicasBoundsExceeded = errors.New("error: an HPAs minReplicas can only be set to int32 values larger than 1")
@@ -48,7 +49,7 @@ type horizontalPodAutoscaler struct {
 	*appsv1.HorizontalPodAutoscaler
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (h *horizontalPodAutoscaler) setReplicas(replicas int32) error {
 	if replicas < 1 {
 		return errMinReplicasBoundsExceeded
@@ -104,16 +105,18 @@ func (h *horizontalPodAutoscaler) getSavedResourcesRequests(_ int32) *metrics.Sa
 	return metrics.NewSavedResources(0, 0)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (h *horizontalPodAutoscaler) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Update(
-		ctx, h.HorizontalPodAutoscaler,
-		metav1.UpdateOptions{},
+// Patch applies the json patch to the resource.
+func (h *horizontalPodAutoscaler) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Patch(
+		ctx, h.Name, types.JSONPatchType, patch,
+		getPatchOptions(),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update horizontalpodautoscaler: %w", err)
+		return fmt.Errorf("failed to patch horizontalpodautoscaler: %w", err)
 	}
 
+	h.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -0,0 +1,290 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const annotationOriginalHPAReplicas = "downscaler/original-hpa-replicas"
+
+// RedirectToHorizontalPodAutoscalers redirects the scaling of all workloads controlled by one of the given HorizontalPodAutoscalers
+// to their HorizontalPodAutoscaler. The HorizontalPodAutoscalers controlling a workload are removed from the workloads,
+// so they are only scaled through the workload they control and its annotations.
+// The autoscalers are listed independently of the workloads, so they don't have to be included to be detected.
+func RedirectToHorizontalPodAutoscalers(workloads, autoscalers []Workload) []Workload {
+	hpas := make(map[workloadIdentifier]*horizontalPodAutoscaler)
+
+	for _, autoscaler := range autoscalers {
+		hpa := getWorkloadAsHorizontalPodAutoscaler(autoscaler)
+		if hpa == nil {
+			continue
+		}
+
+		hpas[getHorizontalPodAutoscalerTarget(hpa)] = hpa
+	}
+
+	redirectedHPAs := make(map[types.NamespacedName]struct{}, len(hpas))
+	results := make([]Workload, 0, len(workloads))
+
+	for _, workload := range workloads {
+		hpa := getControllingHorizontalPodAutoscaler(workload, hpas)
+		if hpa == nil {
+			results = append(results, workload)
+			continue
+		}
+
+		slog.Debug(
+			"redirecting scaling of workload to its horizontalpodautoscaler",
+			"workload", workload.GetName(),
+			"namespace", workload.GetNamespace(),
+			"horizontalPodAutoscaler", hpa.Name,
+		)
+
+		redirectedHPAs[types.NamespacedName{Namespace: hpa.Namespace, Name: hpa.Name}] = struct{}{}
+		results = append(results, newHPARedirectedWorkload(workload, hpa))
+	}
+
+	return slices.DeleteFunc(results, func(workload Workload) bool {
+		hpa := getWorkloadAsHorizontalPodAutoscaler(workload)
+		if hpa == nil {
+			return false
+		}
+
+		_, redirected := redirectedHPAs[types.NamespacedName{Namespace: hpa.Namespace, Name: hpa.Name}]
+
+		return redirected
+	})
+}
+
+// getHorizontalPodAutoscalerTarget gets the identifier of the workload the HorizontalPodAutoscaler scales.
+func getHorizontalPodAutoscalerTarget(hpa *horizontalPodAutoscaler) workloadIdentifier {
+	return newScaleTargetIdentifier(
+		hpa.Spec.ScaleTargetRef.APIVersion,
+		hpa.Spec.ScaleTargetRef.Kind,
+		hpa.Spec.ScaleTargetRef.Name,
+		hpa.Namespace,
+	)
+}
+
+// getControllingHorizontalPodAutoscaler gets the HorizontalPodAutoscaler scaling the workload, or nil if there is none.
+// Only workloads which are scaled by their replicas can be redirected to their HorizontalPodAutoscaler.
+func getControllingHorizontalPodAutoscaler(
+	workload Workload,
+	hpas map[workloadIdentifier]*horizontalPodAutoscaler,
+) *horizontalPodAutoscaler {
+	if _, ok := workload.(*replicaScaledWorkload); !ok || getWorkloadAsHorizontalPodAutoscaler(workload) != nil {
+		return nil
+	}
+
+	for target, hpa := range hpas {
+		if matchesWorkloadIdentifier(&target, workload.GetName(), workload.GetNamespace(), workload.GroupVersionKind()) {
+			return hpa
+		}
+	}
+
+	return nil
+}
+
+// hpaRedirectedWorkload is a workload controlled by a HorizontalPodAutoscaler.
+// It is scaled down by pinning the minReplicas and maxReplicas of its HorizontalPodAutoscaler to the downscale replicas,
+// so the HorizontalPodAutoscaler doesn't scale it back up.
+type hpaRedirectedWorkload struct {
+	Workload
+	hpa *horizontalPodAutoscaler
+	// patchedHPA is the state of the HorizontalPodAutoscaler on Kubernetes, used to only patch the changes made to it
+	patchedHPA *autoscalingv2.HorizontalPodAutoscaler
+}
+
+// newHPARedirectedWorkload creates a new hpaRedirectedWorkload.
+func newHPARedirectedWorkload(workload Workload, hpa *horizontalPodAutoscaler) *hpaRedirectedWorkload {
+	return &hpaRedirectedWorkload{
+		Workload:   workload,
+		hpa:        hpa,
+		patchedHPA: hpa.DeepCopy(),
+	}
+}
+
+// ScaleDown scales down the workload and pins its HorizontalPodAutoscaler to the downscale replicas.
+// HorizontalPodAutoscalers can't be scaled to zero replicas, so with zero downscale replicas
+// only the workload is scaled down, which deactivates its HorizontalPodAutoscaler until it is scaled up again.
+func (h *hpaRedirectedWorkload) ScaleDown(downscaleReplicas values.Replicas) (*metrics.SavedResources, bool, error) {
+	savedResources, updated, err := h.Workload.ScaleDown(downscaleReplicas)
+	if err != nil || !updated {
+		return savedResources, updated, err //nolint:wrapcheck // the error is already wrapped by the workload
+	}
+
+	replicas, err := downscaleReplicas.AsInt32()
+	if err != nil {
+		return savedResources, false, fmt.Errorf("failed to convert replicas to int32: %w", err)
+	}
+
+	if replicas < 1 {
+		return savedResources, true, nil
+	}
+
+	if _, isSet := h.GetAnnotations()[annotationOriginalHPAReplicas]; !isSet {
+		annotations := h.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[annotationOriginalHPAReplicas] = fmt.Sprintf("%d,%d", derefInt32(h.hpa.Spec.MinReplicas, 1), h.hpa.Spec.MaxReplicas)
+		h.SetAnnotations(annotations)
+	}
+
+	h.hpa.Spec.MinReplicas = &replicas
+	h.hpa.Spec.MaxReplicas = replicas
+
+	return savedResources, true, nil
+}
+
+// ScaleUp scales up the workload and restores the minReplicas and maxReplicas of its HorizontalPodAutoscaler.
+func (h *hpaRedirectedWorkload) ScaleUp() (bool, error) {
+	updated, err := h.Workload.ScaleUp()
+	if err != nil {
+		return false, err //nolint:wrapcheck // the error is already wrapped by the workload
+	}
+
+	originalHPAReplicas, isSet := h.GetAnnotations()[annotationOriginalHPAReplicas]
+	if !isSet {
+		return updated, nil
+	}
+
+	minReplicas, maxReplicas, err := parseOriginalHPAReplicas(originalHPAReplicas)
+	if err != nil {
+		return false, err
+	}
+
+	h.hpa.Spec.MinReplicas = &minReplicas
+	h.hpa.Spec.MaxReplicas = maxReplicas
+
+	annotations := h.GetAnnotations()
+	delete(annotations, annotationOriginalHPAReplicas)
+	h.SetAnnotations(annotations)
+
+	return true, nil
+}
+
+// IsManuallyOverridden checks if the replicas of the workload were changed manually while it was scaled down.
+func (h *hpaRedirectedWorkload) IsManuallyOverridden() (bool, error) {
+	overridable, ok := h.Workload.(OverridableWorkload)
+	if !ok {
+		return false, nil
+	}
+
+	return overridable.IsManuallyOverridden() //nolint:wrapcheck // the error is already wrapped by the workload
+}
+
+// IsReady checks if the workload is ready after being scaled up.
+func (h *hpaRedirectedWorkload) IsReady() bool {
+	ready, ok := h.Workload.(ReadyWorkload)
+	if !ok {
+		return true
+	}
+
+	return ready.IsReady()
+}
+
+// Reget regets the workload and its HorizontalPodAutoscaler from the Kubernetes API.
+func (h *hpaRedirectedWorkload) Reget(clientsets *Clientsets, ctx context.Context) error {
+	err := h.Workload.Reget(clientsets, ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // the error is already wrapped by the workload
+	}
+
+	err = h.hpa.Reget(clientsets, ctx)
+	if err != nil {
+		return err
+	}
+
+	h.patchedHPA = h.hpa.DeepCopy()
+
+	return nil
+}
+
+// Patch applies the json patch to the workload and the changes made to its HorizontalPodAutoscaler.
+// The HorizontalPodAutoscaler is patched first, so it doesn't scale the workload back up after the workload was scaled down.
+func (h *hpaRedirectedWorkload) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	hpaPatch, err := jsondiff.Compare(h.patchedHPA, h.hpa.HorizontalPodAutoscaler)
+	if err != nil {
+		return fmt.Errorf("failed to compare horizontalpodautoscalers: %w", err)
+	}
+
+	if len(hpaPatch) != 0 {
+		hpaPatchBytes, err := json.Marshal(lockResourceVersion(hpaPatch, h.patchedHPA.GetResourceVersion()))
+		if err != nil {
+			return fmt.Errorf("failed to marshal horizontalpodautoscaler patch: %w", err)
+		}
+
+		err = h.hpa.Patch(hpaPatchBytes, clientsets, ctx)
+		if err != nil {
+			return err
+		}
+
+		h.patchedHPA = h.hpa.DeepCopy()
+	}
+
+	return h.Workload.Patch(patch, clientsets, ctx) //nolint:wrapcheck // the error is already wrapped by the workload
+}
+
+// Copy creates a deep copy of the workload and its HorizontalPodAutoscaler.
+func (h *hpaRedirectedWorkload) Copy() (Workload, error) {
+	copied, err := h.Workload.Copy()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the error is already wrapped by the workload
+	}
+
+	if h.hpa.HorizontalPodAutoscaler == nil {
+		return nil, newNilUnderlyingObjectError(h.hpa.Kind)
+	}
+
+	return &hpaRedirectedWorkload{
+		Workload:   copied,
+		hpa:        &horizontalPodAutoscaler{h.hpa.DeepCopy()},
+		patchedHPA: h.patchedHPA.DeepCopy(),
+	}, nil
+}
+
+// Compare compares the workload with another hpaRedirectedWorkload and returns the differences of the workloads as a jsondiff.Patch.
+func (h *hpaRedirectedWorkload) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	redirectedCopy, ok := workloadCopy.(*hpaRedirectedWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*hpaRedirectedWorkload)(nil), workloadCopy)
+	}
+
+	return h.Workload.Compare(redirectedCopy.Workload) //nolint:wrapcheck // the error is already wrapped by the workload
+}
+
+// parseOriginalHPAReplicas parses the original minReplicas and maxReplicas of a HorizontalPodAutoscaler.
+//
+//nolint:nonamedreturns // using named return values for clarity
+func parseOriginalHPAReplicas(value string) (minReplicas, maxReplicas int32, err error) {
+	minString, maxString, found := strings.Cut(value, ",")
+	if !found {
+		return 0, 0, newUnexpectedOriginalReplicasError("<minReplicas>,<maxReplicas>", value)
+	}
+
+	parsedMin, err := strconv.ParseInt(minString, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse original minReplicas of horizontalpodautoscaler: %w", err)
+	}
+
+	parsedMax, err := strconv.ParseInt(maxString, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse original maxReplicas of horizontalpodautoscaler: %w", err)
+	}
+
+	// #nosec G115
+	return int32(parsedMin), int32(parsedMax), nil
+}
@@ -0,0 +1,149 @@
+package scalable
+
+import (
+	"testing"
+
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestHPA(name, apiVersion, kind, target string, minReplicas, maxReplicas int32) *replicaScaledWorkload {
+	return &replicaScaledWorkload{&horizontalPodAutoscaler{&autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: apiVersion, Kind: kind, Name: target},
+			MinReplicas:    int32Ptr(minReplicas),
+			MaxReplicas:    maxReplicas,
+		},
+	}}}
+}
+
+func TestRedirectToHorizontalPodAutoscalers(t *testing.T) {
+	t.Parallel()
+
+	deploymentWorkload := &replicaScaledWorkload{&deployment{&appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "test-namespace"},
+	}}}
+	rolloutWorkload := &replicaScaledWorkload{&rollout{&argov1alpha1.Rollout{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: "rollout", Namespace: "test-namespace"},
+	}}}
+	statefulSetWorkload := &replicaScaledWorkload{&statefulSet{&appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "test-namespace"},
+	}}}
+	deploymentHPA := newTestHPA("deployment-hpa", "apps/v1", "Deployment", "deployment", 2, 10)
+	rolloutHPA := newTestHPA("rollout-hpa", "argoproj.io/v1alpha1", "Rollout", "rollout", 2, 10)
+	orphanedHPA := newTestHPA("orphaned-hpa", "apps/v1", "Deployment", "missing", 2, 10)
+
+	got := RedirectToHorizontalPodAutoscalers(
+		[]Workload{deploymentWorkload, rolloutWorkload, statefulSetWorkload, deploymentHPA, rolloutHPA, orphanedHPA},
+		[]Workload{deploymentHPA, rolloutHPA, orphanedHPA},
+	)
+
+	require.Len(t, got, 4)
+	assert.IsType(t, &hpaRedirectedWorkload{}, got[0])
+	assert.IsType(t, &hpaRedirectedWorkload{}, got[1])
+	assert.Same(t, statefulSetWorkload, got[2])
+	assert.Same(t, orphanedHPA, got[3])
+
+	got = RedirectToHorizontalPodAutoscalers([]Workload{deploymentWorkload}, []Workload{deploymentHPA})
+
+	require.Len(t, got, 1, "hpas which aren't included should still be detected")
+	assert.IsType(t, &hpaRedirectedWorkload{}, got[0])
+}
+
+func TestFilterExcluded_AutoscalersNotIncluded(t *testing.T) {
+	t.Parallel()
+
+	statefulSetWorkload := &replicaScaledWorkload{&statefulSet{&appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "test-namespace"},
+	}}}
+	hpas := []Workload{newTestHPA("hpa", "apps/v1", "StatefulSet", "statefulset", 1, 5)}
+
+	got := FilterExcluded([]Workload{statefulSetWorkload}, hpas, nil, nil, nil, nil)
+
+	assert.Empty(t, got)
+	assert.Len(t, FilterExcluded([]Workload{statefulSetWorkload}, nil, nil, nil, nil, nil), 1)
+}
+
+func TestGetExternallyScaledByHPA(t *testing.T) {
+	t.Parallel()
+
+	statefulSetWorkload := &replicaScaledWorkload{&statefulSet{&appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "test-namespace"},
+	}}}
+	rolloutWorkload := &replicaScaledWorkload{&rollout{&argov1alpha1.Rollout{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "test-namespace"},
+	}}}
+
+	hpas := []Workload{newTestHPA("hpa", "apps/v1", "StatefulSet", "statefulset", 1, 5)}
+
+	assert.True(t, IsWorkloadExternallyManaged(statefulSetWorkload, hpas))
+	assert.False(t, IsWorkloadExternallyManaged(rolloutWorkload, hpas))
+}
+
+func TestHPARedirectedWorkload_Scaling(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		downscaleReplicas values.Replicas
+		wantMinReplicas   int32
+		wantMaxReplicas   int32
+	}{
+		{
+			name:              "pins the hpa to the downscale replicas",
+			downscaleReplicas: values.AbsoluteReplicas(1),
+			wantMinReplicas:   1,
+			wantMaxReplicas:   1,
+		},
+		{
+			name:              "keeps the hpa when scaling to zero",
+			downscaleReplicas: values.AbsoluteReplicas(0),
+			wantMinReplicas:   2,
+			wantMaxReplicas:   10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := &replicaScaledWorkload{&deployment{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "test-namespace"},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+			}}}
+			hpa := getWorkloadAsHorizontalPodAutoscaler(newTestHPA("hpa", "apps/v1", "Deployment", "deployment", 2, 10))
+			workload := newHPARedirectedWorkload(target, hpa)
+
+			_, updated, err := workload.ScaleDown(test.downscaleReplicas)
+			require.NoError(t, err)
+			assert.True(t, updated)
+			assert.Equal(t, test.wantMinReplicas, *hpa.Spec.MinReplicas)
+			assert.Equal(t, test.wantMaxReplicas, hpa.Spec.MaxReplicas)
+
+			overridden, err := workload.IsManuallyOverridden()
+			require.NoError(t, err)
+			assert.False(t, overridden)
+
+			updated, err = workload.ScaleUp()
+			require.NoError(t, err)
+			assert.True(t, updated)
+			assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+			assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+			assert.Equal(t, int32(4), *target.replicaScaledResource.(*deployment).Spec.Replicas)
+			assert.NotContains(t, workload.GetAnnotations(), annotationOriginalHPAReplicas)
+		})
+	}
+}
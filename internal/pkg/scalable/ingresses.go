@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -53,7 +54,7 @@ func (i *ingress) getSavedResourcesRequests() *metrics.SavedResources {
 	return metrics.NewSavedResources(0, 0)
 }
 
-// setValue sets the value on the resource. Changes won't be made on Kubernetes until update() is called.
+// setValue sets the value on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (i *ingress) setValue(targetReplicas values.Replicas) error {
 	targetValue := targetReplicas.String()
 	i.Spec.IngressClassName = &targetValue
@@ -87,13 +88,18 @@ func (i *ingress) Reget(clientsets *Clientsets, ctx context.Context) error {
 	return nil
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (i *ingress) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.NetworkingV1().Ingresses(i.Namespace).Update(ctx, i.Ingress, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (i *ingress) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.NetworkingV1().Ingresses(i.Namespace).Patch(
+		ctx, i.Name, types.JSONPatchType, patch,
+		getPatchOptions(),
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update ingress: %w", err)
+		return fmt.Errorf("failed to patch ingress: %w", err)
 	}
 
+	i.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	batch "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getDeployments is the getResourceFunc for Jobs.
@@ -98,13 +99,15 @@ func (j *job) getSavedResourcesRequests() *metrics.SavedResources {
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (j *job) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.BatchV1().Jobs(j.Namespace).Update(ctx, j.Job, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (j *job) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.BatchV1().Jobs(j.Namespace).Patch(ctx, j.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update job: %w", err)
+		return fmt.Errorf("failed to patch job: %w", err)
 	}
 
+	j.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -13,6 +13,7 @@ import (
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -93,6 +94,11 @@ func (k *kafkaBridge) setReplicas(replicas int32) error {
 	return nil
 }
 
+// IsReady checks if the latest generation of the resource was reconciled and is ready.
+func (k *kafkaBridge) IsReady() bool {
+	return isStrimziResourceReady(k.Unstructured)
+}
+
 // getSavedResourcesRequests returns the saved CPU and memory requests.
 // Strimzi pod templates are not accessible at this abstraction level, consistent with scaledobjects.go.
 func (k *kafkaBridge) getSavedResourcesRequests(_ int32) *metrics.SavedResources {
@@ -155,11 +161,11 @@ func (k *kafkaBridge) Reget(clientsets *Clientsets, ctx context.Context) error {
 	return nil
 }
 
-// Update updates the resource with all changes made to it.
-func (k *kafkaBridge) Update(clientsets *Clientsets, ctx context.Context) error {
-	err := clientsets.Client.Update(ctx, k.Unstructured)
+// Patch applies the json patch to the resource.
+func (k *kafkaBridge) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	err := clientsets.Client.Patch(ctx, k.Unstructured, ctrlclient.RawPatch(types.JSONPatchType, patch), getPatchOwner())
 	if err != nil {
-		return fmt.Errorf("failed to update %s %s/%s: %w", k.GetKind(), k.GetNamespace(), k.GetName(), err)
+		return fmt.Errorf("failed to patch %s %s/%s: %w", k.GetKind(), k.GetNamespace(), k.GetName(), err)
 	}
 
 	return nil
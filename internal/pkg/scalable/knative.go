@@ -0,0 +1,345 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	KnativeServingGroup = "serving.knative.dev"
+
+	annotationKnativeMinScale = "autoscaling.knative.dev/min-scale"
+	annotationKnativeMaxScale = "autoscaling.knative.dev/max-scale"
+	// annotationOriginalKnativeScale keeps the original min-scale and max-scale annotations while the resource is scaled down
+	annotationOriginalKnativeScale = "downscaler/original-knative-scale"
+	// annotationOriginalKnativeVisibility keeps the original visibility label while the service is blocked from activation
+	annotationOriginalKnativeVisibility = "downscaler/original-knative-visibility"
+
+	labelKnativeVisibility        = "networking.knative.dev/visibility"
+	knativeVisibilityClusterLocal = "cluster-local"
+)
+
+//nolint:gochecknoglobals // package-level resources required for the dynamic client
+var (
+	knativeServiceResource  = schema.GroupVersionResource{Group: KnativeServingGroup, Version: "v1", Resource: "services"}
+	knativeRevisionResource = schema.GroupVersionResource{Group: KnativeServingGroup, Version: "v1", Resource: "revisions"}
+)
+
+// getKnativeScaleAnnotationsPath gets the path of the annotations Knative reads the scale bounds of the resource from.
+// Services pass them on to their revisions through the annotations of their template.
+func getKnativeScaleAnnotationsPath(resource schema.GroupVersionResource) []string {
+	if resource == knativeServiceResource {
+		return []string{"spec", "template", "metadata", "annotations"}
+	}
+
+	return []string{"metadata", "annotations"}
+}
+
+// getKnativeWorkloadsFunc gets the getResourceFunc for the Knative resource.
+func getKnativeWorkloadsFunc(resource schema.GroupVersionResource) getResourceFunc {
+	return func(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+		return listDynamicWorkloads(resource, namespace, clientsets, ctx, func(item *unstructured.Unstructured) Workload {
+			return &replicaScaledWorkload{newKnativeResource(item, resource)}
+		})
+	}
+}
+
+// ParseKnativeWorkloadFromRawObject parses the admission review of a Knative resource.
+//
+//nolint:ireturn // this function should return an interface type
+func ParseKnativeWorkloadFromRawObject(resource string, rawObject []byte) (Workload, error) {
+	var groupVersionResource schema.GroupVersionResource
+
+	switch resource {
+	case knativeServiceResource.Resource:
+		groupVersionResource = knativeServiceResource
+	case knativeRevisionResource.Resource:
+		groupVersionResource = knativeRevisionResource
+	default:
+		return nil, newInvalidResourceError(resource)
+	}
+
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode knative %s: %w", resource, err)
+	}
+
+	return &replicaScaledWorkload{newKnativeResource(&u, groupVersionResource)}, nil
+}
+
+// knativeResource is a Knative Service or Revision, which is scaled through the min-scale and max-scale annotations.
+// While scaled down both are pinned to the downscale replicas, which scales its revisions down and stops them from scaling
+// back up. Knative treats a max-scale of 0 as unbounded, so for 0 downscale replicas the max-scale is pinned to 1,
+// which lets the revisions scale to zero but would still activate a single pod for incoming requests.
+// To block the activation the routes of a service are made cluster-local, which removes them from the external ingress.
+type knativeResource struct {
+	*unstructured.Unstructured
+	resource        schema.GroupVersionResource
+	annotationsPath []string
+}
+
+// newKnativeResource creates a new knativeResource.
+func newKnativeResource(object *unstructured.Unstructured, resource schema.GroupVersionResource) *knativeResource {
+	return &knativeResource{
+		Unstructured:    object,
+		resource:        resource,
+		annotationsPath: getKnativeScaleAnnotationsPath(resource),
+	}
+}
+
+// getScaleAnnotation gets the scale annotation and if it is set.
+func (k *knativeResource) getScaleAnnotation(annotation string) (string, bool) {
+	annotations, _, _ := unstructured.NestedStringMap(k.Object, k.annotationsPath...)
+	value, ok := annotations[annotation]
+
+	return value, ok
+}
+
+// setScaleAnnotation sets the scale annotation. An empty value removes it.
+func (k *knativeResource) setScaleAnnotation(annotation, value string) error {
+	annotations, _, err := unstructured.NestedStringMap(k.Object, k.annotationsPath...)
+	if err != nil {
+		return fmt.Errorf("failed to get annotations of knative %s %s/%s: %w", k.resource.Resource, k.GetNamespace(), k.GetName(), err)
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if value == "" {
+		delete(annotations, annotation)
+	} else {
+		annotations[annotation] = value
+	}
+
+	err = unstructured.SetNestedStringMap(k.Object, annotations, k.annotationsPath...)
+	if err != nil {
+		return fmt.Errorf("failed to set annotations of knative %s %s/%s: %w", k.resource.Resource, k.GetNamespace(), k.GetName(), err)
+	}
+
+	return nil
+}
+
+// getReplicas gets the pinned min-scale while the resource is scaled down and its max-scale otherwise.
+// A missing or zero max-scale is unbounded, so the replicas are undefined.
+func (k *knativeResource) getReplicas() (values.Replicas, error) {
+	annotation := annotationKnativeMaxScale
+	if _, isScaledDown := k.GetAnnotations()[annotationOriginalKnativeScale]; isScaledDown {
+		annotation = annotationKnativeMinScale
+	}
+
+	value, ok := k.getScaleAnnotation(annotation)
+	if !ok {
+		return values.AbsoluteReplicas(util.Undefined), nil
+	}
+
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for annotation %q: %w", annotation, err)
+	}
+
+	if replicas == 0 && annotation == annotationKnativeMaxScale {
+		return values.AbsoluteReplicas(util.Undefined), nil
+	}
+
+	// #nosec G115
+	return values.AbsoluteReplicas(int32(replicas)), nil
+}
+
+// setReplicas pins the min-scale and max-scale annotations to the replicas, keeping their original values.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (k *knativeResource) setReplicas(replicas int32) error {
+	annotations := k.GetAnnotations()
+
+	if _, isScaledDown := annotations[annotationOriginalKnativeScale]; !isScaledDown {
+		minScale, _ := k.getScaleAnnotation(annotationKnativeMinScale)
+		maxScale, _ := k.getScaleAnnotation(annotationKnativeMaxScale)
+
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[annotationOriginalKnativeScale] = minScale + "," + maxScale
+		k.SetAnnotations(annotations)
+	}
+
+	err := k.setScaleAnnotation(annotationKnativeMinScale, strconv.Itoa(int(replicas)))
+	if err != nil {
+		return err
+	}
+
+	err = k.setScaleAnnotation(annotationKnativeMaxScale, strconv.Itoa(int(max(replicas, 1))))
+	if err != nil {
+		return err
+	}
+
+	k.setActivationBlocked(replicas == 0 && k.resource == knativeServiceResource)
+
+	return nil
+}
+
+// setActivationBlocked makes the routes of the service cluster-local, keeping the original visibility label,
+// or restores the original visibility label.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (k *knativeResource) setActivationBlocked(blocked bool) {
+	annotations := k.GetAnnotations()
+	originalVisibility, isBlocked := annotations[annotationOriginalKnativeVisibility]
+
+	if blocked == isBlocked {
+		return
+	}
+
+	labels := k.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	if blocked {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[annotationOriginalKnativeVisibility] = labels[labelKnativeVisibility]
+		labels[labelKnativeVisibility] = knativeVisibilityClusterLocal
+	} else {
+		delete(annotations, annotationOriginalKnativeVisibility)
+		delete(labels, labelKnativeVisibility)
+
+		if originalVisibility != "" {
+			labels[labelKnativeVisibility] = originalVisibility
+		}
+	}
+
+	k.SetLabels(labels)
+	k.SetAnnotations(annotations)
+}
+
+// restoreReplicas restores the original min-scale and max-scale annotations and unblocks the activation.
+// If they weren't kept, the pinned min-scale is removed and the max-scale is set to the original replicas instead.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (k *knativeResource) restoreReplicas(originalReplicas int32) error {
+	k.setActivationBlocked(false)
+
+	originalScale, found := k.GetAnnotations()[annotationOriginalKnativeScale]
+	if !found {
+		maxScale := ""
+		if originalReplicas != util.Undefined {
+			maxScale = strconv.Itoa(int(originalReplicas))
+		}
+
+		return k.restoreScaleAnnotations("," + maxScale)
+	}
+
+	return k.restoreScaleAnnotations(originalScale)
+}
+
+// restoreScaleAnnotations restores the original min-scale and max-scale annotations.
+func (k *knativeResource) restoreScaleAnnotations(originalScale string) error {
+	minScale, maxScale, found := strings.Cut(originalScale, ",")
+	if !found {
+		return newUnexpectedOriginalReplicasError("<min-scale>,<max-scale>", originalScale)
+	}
+
+	err := k.setScaleAnnotation(annotationKnativeMinScale, minScale)
+	if err != nil {
+		return err
+	}
+
+	err = k.setScaleAnnotation(annotationKnativeMaxScale, maxScale)
+	if err != nil {
+		return err
+	}
+
+	annotations := k.GetAnnotations()
+	delete(annotations, annotationOriginalKnativeScale)
+	k.SetAnnotations(annotations)
+
+	return nil
+}
+
+// getSavedResourcesRequests calculates the resource requests saved by scaling down the pods of the resource.
+// Resources with an unbounded max-scale have no known amount of pods, so no resources are reported for them.
+func (k *knativeResource) getSavedResourcesRequests(diffReplicas int32) *metrics.SavedResources {
+	if diffReplicas <= 0 {
+		return metrics.NewSavedResources(0, 0)
+	}
+
+	containersPath := "{.spec.containers[*].resources.requests}"
+	if k.resource == knativeServiceResource {
+		containersPath = "{.spec.template.spec.containers[*].resources.requests}"
+	}
+
+	cpu, memory := getCustomResourceRequests(k.Object, containersPath)
+
+	return metrics.NewSavedResources(cpu*float64(diffReplicas), memory*float64(diffReplicas))
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (k *knativeResource) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(k.resource).Namespace(k.GetNamespace()).Get(ctx, k.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get knative %s %s/%s: %w", k.resource.Resource, k.GetNamespace(), k.GetName(), err)
+	}
+
+	k.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (k *knativeResource) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(k.resource).Namespace(k.GetNamespace()).
+		Patch(ctx, k.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch knative %s %s/%s: %w", k.resource.Resource, k.GetNamespace(), k.GetName(), err)
+	}
+
+	k.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the workload.
+func (k *knativeResource) Copy() (Workload, error) {
+	if k.Object == nil {
+		return nil, newNilUnderlyingObjectError(k.GetKind())
+	}
+
+	return &replicaScaledWorkload{newKnativeResource(k.DeepCopy(), k.resource)}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (k *knativeResource) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	replicaScaledCopy, ok := workloadCopy.(*replicaScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*replicaScaledWorkload)(nil), workloadCopy)
+	}
+
+	knativeCopy, ok := replicaScaledCopy.replicaScaledResource.(*knativeResource)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*knativeResource)(nil), replicaScaledCopy.replicaScaledResource)
+	}
+
+	if k.Object == nil || knativeCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(k.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(k.Object, knativeCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare knative %s: %w", k.resource.Resource, err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,90 @@
+package scalable
+
+import (
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestKnativeResource_ScaleDownToOriginalReplicas(t *testing.T) {
+	t.Parallel()
+
+	resource := newTestKnativeResource(t, knativeServiceResource, map[string]string{annotationKnativeMaxScale: "3"})
+	workload := &replicaScaledWorkload{resource}
+
+	_, _, err := workload.ScaleDown(values.AbsoluteReplicas(1))
+	require.NoError(t, err)
+
+	// the pinned min-scale was overridden manually, so the resource is scaled down again
+	require.NoError(t, resource.setScaleAnnotation(annotationKnativeMinScale, "5"))
+
+	_, updated, err := workload.ScaleDown(values.AbsoluteReplicas(3))
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	minScale, _ := resource.getScaleAnnotation(annotationKnativeMinScale)
+	maxScale, _ := resource.getScaleAnnotation(annotationKnativeMaxScale)
+	assert.Equal(t, "3", minScale, "downscale replicas matching the original replicas mustn't restore the original scale")
+	assert.Equal(t, "3", maxScale)
+	assert.Contains(t, resource.GetAnnotations(), annotationOriginalKnativeScale)
+}
+
+func TestKnativeResource_BlocksActivation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		resource          schema.GroupVersionResource
+		labels            map[string]string
+		downscaleReplicas int32
+		wantVisibility    string
+	}{
+		{
+			name:              "service scaled to zero",
+			resource:          knativeServiceResource,
+			downscaleReplicas: 0,
+			wantVisibility:    knativeVisibilityClusterLocal,
+		},
+		{
+			name:              "service with visibility label scaled to zero",
+			resource:          knativeServiceResource,
+			labels:            map[string]string{labelKnativeVisibility: "external"},
+			downscaleReplicas: 0,
+			wantVisibility:    knativeVisibilityClusterLocal,
+		},
+		{
+			name:              "service scaled to one",
+			resource:          knativeServiceResource,
+			downscaleReplicas: 1,
+			wantVisibility:    "",
+		},
+		{
+			name:              "revision scaled to zero",
+			resource:          knativeRevisionResource,
+			downscaleReplicas: 0,
+			wantVisibility:    "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := newTestKnativeResource(t, test.resource, map[string]string{annotationKnativeMaxScale: "3"})
+			resource.SetLabels(test.labels)
+			workload := &replicaScaledWorkload{resource}
+
+			_, _, err := workload.ScaleDown(values.AbsoluteReplicas(test.downscaleReplicas))
+			require.NoError(t, err)
+			assert.Equal(t, test.wantVisibility, resource.GetLabels()[labelKnativeVisibility])
+
+			_, err = workload.ScaleUp()
+			require.NoError(t, err)
+			assert.Equal(t, test.labels[labelKnativeVisibility], resource.GetLabels()[labelKnativeVisibility])
+			assert.NotContains(t, resource.GetAnnotations(), annotationOriginalKnativeVisibility)
+		})
+	}
+}
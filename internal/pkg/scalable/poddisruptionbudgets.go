@@ -13,6 +13,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	policy "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getPodDisruptionBudgets is the getResourceFunc for podDisruptionBudget.
@@ -162,13 +163,18 @@ func (p *podDisruptionBudget) Reget(clientsets *Clientsets, ctx context.Context)
 	return nil
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (p *podDisruptionBudget) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.PolicyV1().PodDisruptionBudgets(p.Namespace).Update(ctx, p.PodDisruptionBudget, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (p *podDisruptionBudget) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.PolicyV1().PodDisruptionBudgets(p.Namespace).Patch(
+		ctx, p.Name, types.JSONPatchType, patch,
+		getPatchOptions(),
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update poddisruptionbudget: %w", err)
+		return fmt.Errorf("failed to patch poddisruptionbudget: %w", err)
 	}
 
+	p.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	acidv1 "github.com/zalando/postgres-operator/pkg/apis/acid.zalan.do/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,6 +48,11 @@ type postgresql struct {
 	*acidv1.Postgresql
 }
 
+// IsReady checks if the postgres cluster is running.
+func (p *postgresql) IsReady() bool {
+	return p.Status.Running()
+}
+
 func (p *postgresql) Reget(clientsets *Clientsets, ctx context.Context) error {
 	err := clientsets.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: p.Namespace, Name: p.Name}, p.Postgresql)
 	if err != nil {
@@ -58,18 +64,17 @@ func (p *postgresql) Reget(clientsets *Clientsets, ctx context.Context) error {
 	return nil
 }
 
-// Update writes the spec changes back. It updates the main resource only; the postgresql status is a
-// subresource and is left untouched by the apiserver on a main-resource update.
-func (p *postgresql) Update(clientsets *Clientsets, ctx context.Context) error {
-	err := clientsets.Client.Update(ctx, p.Postgresql)
+// Patch applies the json patch to the main resource; the postgresql status is a subresource and is left untouched.
+func (p *postgresql) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	err := clientsets.Client.Patch(ctx, p.Postgresql, ctrlclient.RawPatch(types.JSONPatchType, patch), getPatchOwner())
 	if err != nil {
-		return fmt.Errorf("failed to update postgresql: %w", err)
+		return fmt.Errorf("failed to patch postgresql: %w", err)
 	}
 
 	return nil
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (p *postgresql) setReplicas(replicas int32) error {
 	p.Spec.NumberOfInstances = replicas
 	return nil
@@ -11,6 +11,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/wI2L/jsondiff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getPrometheuses is the getResourceFunc for Prometheuses.
@@ -44,7 +45,7 @@ type prometheus struct {
 	*monitoringv1.Prometheus
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (p *prometheus) setReplicas(replicas int32) error {
 	p.Spec.Replicas = &replicas
 	return nil
@@ -94,13 +95,18 @@ func (p *prometheus) getSavedResourcesRequests(diffReplicas int32) *metrics.Save
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (p *prometheus) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Monitoring.MonitoringV1().Prometheuses(p.Namespace).Update(ctx, p.Prometheus, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (p *prometheus) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Monitoring.MonitoringV1().Prometheuses(p.Namespace).Patch(
+		ctx, p.Name, types.JSONPatchType, patch,
+		getPatchOptions(),
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update prometheus: %w", err)
+		return fmt.Errorf("failed to patch prometheus: %w", err)
 	}
 
+	p.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
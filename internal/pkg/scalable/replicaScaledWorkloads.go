@@ -15,8 +15,8 @@ import (
 // replicaScaledResource provides all the functions needed to scale a resource which is scaled by setting the replica count.
 type replicaScaledResource interface {
 	scalableResource
-	// Update updates the resource with all changes made to it. It should only be called once on a resource
-	Update(clientsets *Clientsets, ctx context.Context) error
+	// Patch applies the json patch to the resource
+	Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error
 	// setReplicas sets the replicas of the workload
 	setReplicas(replicas int32) error
 	// getReplicas gets the replicas of the workload
@@ -29,18 +29,43 @@ type replicaScaledResource interface {
 	Compare(workloadCopy Workload) (jsondiff.Patch, error)
 }
 
+// replicaRestoringResource is implemented by replicaScaledResources which keep more of their original state than the
+// original replicas while they are scaled down, so they restore it themselves instead of setting the original replicas.
+type replicaRestoringResource interface {
+	// restoreReplicas restores the original state of the scaled down resource
+	restoreReplicas(originalReplicas int32) error
+}
+
 // replicaScaledWorkload is a wrapper for all resources which are scaled by setting the replica count.
 type replicaScaledWorkload struct {
 	replicaScaledResource
 }
 
+// IsReady delegates the readiness check to the wrapped resource when it supports ReadyWorkload.
+// Resources which can't report their readiness are always considered ready.
+func (r *replicaScaledWorkload) IsReady() bool {
+	ready, ok := r.replicaScaledResource.(ReadyWorkload)
+	if !ok {
+		return true
+	}
+
+	return ready.IsReady()
+}
+
 // ScaleUp scales up the underlying replicaScaledResource.
 func (r *replicaScaledWorkload) ScaleUp() (bool, error) {
 	originalReplicas, err := getOriginalReplicas(r)
 	if err != nil {
 		var originalReplicasUnsetErr *OriginalReplicasUnsetError
 		if ok := errors.As(err, &originalReplicasUnsetErr); ok {
+			// the applied replicas are only set while the downscaler keeps the workload scaled down,
+			// so the workload would stay scaled down forever without its original replicas
+			if _, isAppliedReplicasSet := r.GetAnnotations()[annotationAppliedReplicas]; isAppliedReplicasSet {
+				return false, newOriginalReplicasMissingError(r.GroupVersionKind().Kind, r.GetName())
+			}
+
 			slog.Debug("original replicas is not set, skipping", "workload", r.GetName(), "namespace", r.GetNamespace())
+
 			return false, nil
 		}
 
@@ -52,16 +77,51 @@ func (r *replicaScaledWorkload) ScaleUp() (bool, error) {
 		return false, fmt.Errorf("failed to convert original replicas to int32: %w", err)
 	}
 
-	err = r.setReplicas(originalReplicasInt32)
+	if restoring, ok := r.replicaScaledResource.(replicaRestoringResource); ok {
+		err = restoring.restoreReplicas(originalReplicasInt32)
+	} else {
+		err = r.setReplicas(originalReplicasInt32)
+	}
+
 	if err != nil {
 		return false, fmt.Errorf("failed to set original replicas for workload: %w", err)
 	}
 
 	removeOriginalReplicas(r)
+	removeScaledDownState(r)
 
 	return true, nil
 }
 
+// IsManuallyOverridden checks if the replicas were changed after the downscaler scaled the workload down.
+// Workloads which were scaled down before the applied replicas were recorded are never considered overridden.
+func (r *replicaScaledWorkload) IsManuallyOverridden() (bool, error) {
+	if !IsScaledDown(r) {
+		return false, nil
+	}
+
+	appliedReplicas, isAppliedReplicasSet, err := getAppliedReplicas(r)
+	if err != nil {
+		return false, err
+	}
+
+	if !isAppliedReplicasSet {
+		return false, nil
+	}
+
+	currentReplicas, err := r.getReplicas()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current replicas for workload: %w", err)
+	}
+
+	currentReplicasInt32, err := currentReplicas.AsInt32()
+	if err != nil {
+		return false, fmt.Errorf("failed to convert current replicas to int32: %w", err)
+	}
+
+	return currentReplicasInt32 != util.Undefined && currentReplicasInt32 != appliedReplicas, nil
+}
+
 // ScaleDown scales down the underlying replicaScaledResource.
 //
 
@@ -107,11 +167,30 @@ func (r *replicaScaledWorkload) ScaleDown(downscaleReplicas values.Replicas) (*m
 		return savedResources, false, nil
 	}
 
+	isOverridden, err := r.IsManuallyOverridden()
+	if err != nil {
+		return savedResources, false, fmt.Errorf("failed to check for a manual override: %w", err)
+	}
+
+	originalReplicasInt32, _, err := getOriginalReplicasInt32(r)
+	if err != nil {
+		return savedResources, false, err
+	}
+
 	err = r.setReplicas(downscaleReplicasInt32)
 	if err != nil {
 		return savedResources, false, fmt.Errorf("failed to set replicas for workload: %w", err)
 	}
 
+	removeScaledDownState(r)
+	setAppliedReplicas(downscaleReplicasInt32, r)
+
+	// the replicas of a manually overridden workload are only temporary, so they mustn't replace its original replicas
+	if isOverridden {
+		savedResources = r.getSavedResourcesRequests(originalReplicasInt32 - downscaleReplicasInt32)
+		return savedResources, true, nil
+	}
+
 	savedResources = r.getSavedResourcesRequests(currentReplicasInt32 - downscaleReplicasInt32)
 
 	setOriginalReplicas(currentReplicas, r)
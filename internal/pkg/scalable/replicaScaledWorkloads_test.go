@@ -12,8 +12,43 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// newTestScaledJob builds a scaledJob with the given maxReplicaCount and annotations.
+func newTestScaledJob(maxReplicaCount *int32, annotations map[string]string) *scaledJob {
+	return &scaledJob{&kedav1alpha1.ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-scaledjob",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: kedav1alpha1.ScaledJobSpec{MaxReplicaCount: maxReplicaCount},
+	}}
+}
+
+// newTestKnativeResource builds a Knative resource with the given scale annotations.
+func newTestKnativeResource(t *testing.T, resource schema.GroupVersionResource, scaleAnnotations map[string]string) *knativeResource {
+	t.Helper()
+
+	knative := newKnativeResource(&unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "serving.knative.dev/v1",
+		"metadata": map[string]any{
+			"name":      "test-knative",
+			"namespace": "default",
+		},
+		"spec": map[string]any{},
+	}}, resource)
+
+	for annotation, value := range scaleAnnotations {
+		require.NoError(t, knative.setScaleAnnotation(annotation, value))
+	}
+
+	return knative
+}
+
 func TestReplicaScaledWorkload_ScaleUp(t *testing.T) {
 	t.Parallel()
 
@@ -100,6 +135,21 @@ func TestReplicaScaledWorkload_ScaleUp(t *testing.T) {
 	}
 }
 
+func TestReplicaScaledWorkload_ScaleUpWithMissingOriginalReplicas(t *testing.T) {
+	t.Parallel()
+
+	deployment := &replicaScaledWorkload{&deployment{&appsv1.Deployment{}}}
+	_ = deployment.setReplicas(0)
+	setAppliedReplicas(0, deployment)
+
+	updateNeeded, err := deployment.ScaleUp()
+
+	var missingErr *OriginalReplicasMissingError
+
+	require.ErrorAs(t, err, &missingErr)
+	assert.False(t, updateNeeded)
+}
+
 func TestReplicaScaledWorkload_ScaleDown(t *testing.T) {
 	t.Parallel()
 
@@ -300,3 +350,248 @@ func TestReplicaScaledWorkload_ScaleDown_ScaledObjectUndefinedReplicas(t *testin
 		})
 	}
 }
+
+// TestReplicaScaledWorkload_ScaleDownAndUp scales the replica scaled resources down and back up,
+// checking that scaling up restores the exact state they had before.
+func TestReplicaScaledWorkload_ScaleDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                   string
+		newResource            func(t *testing.T) replicaScaledResource
+		downscaleReplicas      int32
+		wantReplicas           values.Replicas
+		wantDownscaledReplicas values.Replicas
+		wantUpdated            bool
+	}{
+		{
+			name: "scaledjob paused with undefined max replica count",
+			newResource: func(*testing.T) replicaScaledResource {
+				return newTestScaledJob(nil, nil)
+			},
+			downscaleReplicas:      0,
+			wantReplicas:           values.AbsoluteReplicas(util.Undefined),
+			wantDownscaledReplicas: values.AbsoluteReplicas(0),
+			wantUpdated:            true,
+		},
+		{
+			name: "scaledjob paused with max replica count",
+			newResource: func(*testing.T) replicaScaledResource {
+				return newTestScaledJob(int32Ptr(10), nil)
+			},
+			downscaleReplicas:      0,
+			wantReplicas:           values.AbsoluteReplicas(10),
+			wantDownscaledReplicas: values.AbsoluteReplicas(0),
+			wantUpdated:            true,
+		},
+		{
+			name: "scaledjob with lowered max replica count",
+			newResource: func(*testing.T) replicaScaledResource {
+				return newTestScaledJob(int32Ptr(10), nil)
+			},
+			downscaleReplicas:      2,
+			wantReplicas:           values.AbsoluteReplicas(10),
+			wantDownscaledReplicas: values.AbsoluteReplicas(2),
+			wantUpdated:            true,
+		},
+		{
+			name: "scaledjob paused by its owner",
+			newResource: func(*testing.T) replicaScaledResource {
+				return newTestScaledJob(int32Ptr(10), map[string]string{kedav1alpha1.PausedAnnotation: "true"})
+			},
+			downscaleReplicas:      0,
+			wantReplicas:           values.AbsoluteReplicas(0),
+			wantDownscaledReplicas: values.AbsoluteReplicas(0),
+			wantUpdated:            false,
+		},
+		{
+			name: "knative service scaled to zero",
+			newResource: func(t *testing.T) replicaScaledResource {
+				t.Helper()
+
+				return newTestKnativeResource(t, knativeServiceResource, map[string]string{
+					annotationKnativeMinScale: "1",
+					annotationKnativeMaxScale: "10",
+				})
+			},
+			downscaleReplicas:      0,
+			wantReplicas:           values.AbsoluteReplicas(10),
+			wantDownscaledReplicas: values.AbsoluteReplicas(0),
+			wantUpdated:            true,
+		},
+		{
+			name: "knative revision without scale annotations",
+			newResource: func(t *testing.T) replicaScaledResource {
+				t.Helper()
+
+				return newTestKnativeResource(t, knativeRevisionResource, map[string]string{})
+			},
+			downscaleReplicas:      2,
+			wantReplicas:           values.AbsoluteReplicas(util.Undefined),
+			wantDownscaledReplicas: values.AbsoluteReplicas(2),
+			wantUpdated:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := test.newResource(t)
+			workload := &replicaScaledWorkload{resource}
+
+			replicas, err := workload.getReplicas()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantReplicas, replicas)
+
+			_, updated, err := workload.ScaleDown(values.AbsoluteReplicas(test.downscaleReplicas))
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+
+			replicas, err = workload.getReplicas()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantDownscaledReplicas, replicas)
+
+			_, updated, err = workload.ScaleDown(values.AbsoluteReplicas(test.downscaleReplicas))
+			require.NoError(t, err)
+			assert.False(t, updated, "a scaled down workload shouldn't be scaled down again")
+
+			updated, err = workload.ScaleUp()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+
+			clearEmptyMetadata(resource)
+			assert.Equal(t, test.newResource(t), resource, "scaling up should restore the state before scaling down")
+		})
+	}
+}
+
+func TestReplicaScaledWorkload_IsManuallyOverridden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		replicas        int32
+		annotations     map[string]string
+		wantOverridden  bool
+		wantErrContains string
+	}{
+		{
+			name:           "not scaled down",
+			replicas:       3,
+			annotations:    map[string]string{},
+			wantOverridden: false,
+		},
+		{
+			name:           "replicas unchanged since scaled down",
+			replicas:       0,
+			annotations:    map[string]string{annotationOriginalReplicas: "3", annotationAppliedReplicas: "0"},
+			wantOverridden: false,
+		},
+		{
+			name:           "replicas changed since scaled down",
+			replicas:       2,
+			annotations:    map[string]string{annotationOriginalReplicas: "3", annotationAppliedReplicas: "0"},
+			wantOverridden: true,
+		},
+		{
+			name:           "scaled down without applied replicas",
+			replicas:       2,
+			annotations:    map[string]string{annotationOriginalReplicas: "3"},
+			wantOverridden: false,
+		},
+		{
+			name:            "invalid applied replicas",
+			replicas:        2,
+			annotations:     map[string]string{annotationOriginalReplicas: "3", annotationAppliedReplicas: "zero"},
+			wantErrContains: "failed to parse applied replicas annotation",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			deploy := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &test.replicas}}
+			deploy.SetAnnotations(test.annotations)
+			workload := &replicaScaledWorkload{&deployment{deploy}}
+
+			overridden, err := workload.IsManuallyOverridden()
+			if test.wantErrContains != "" {
+				require.ErrorContains(t, err, test.wantErrContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.wantOverridden, overridden)
+		})
+	}
+}
+
+func TestReplicaScaledWorkload_ScaleDown_KeepsOriginalReplicasOfManualOverride(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	deploy := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+	deploy.SetAnnotations(map[string]string{
+		annotationOriginalReplicas:    "5",
+		annotationAppliedReplicas:     "0",
+		annotationManualOverrideSince: "2026-01-01T00:00:00Z",
+	})
+	workload := &replicaScaledWorkload{&deployment{deploy}}
+
+	_, updateNeeded, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+	assert.True(t, updateNeeded)
+
+	gotReplicas, err := workload.getReplicas()
+	require.NoError(t, err)
+	assert.Equal(t, values.AbsoluteReplicas(0), gotReplicas)
+
+	gotOriginal, err := getOriginalReplicas(workload)
+	require.NoError(t, err)
+	assert.Equal(t, values.AbsoluteReplicas(5), gotOriginal, "the manually set replicas mustn't replace the original replicas")
+
+	overriddenSince, err := GetManualOverrideSince(workload)
+	require.NoError(t, err)
+	assert.True(t, overriddenSince.IsZero(), "the expired manual override should be removed")
+}
+
+func TestCanReportReadiness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		workload Workload
+		want     bool
+	}{
+		{
+			name:     "replica scaled resource with readiness",
+			workload: &replicaScaledWorkload{&deployment{&appsv1.Deployment{}}},
+			want:     true,
+		},
+		{
+			name:     "replica scaled resource without readiness",
+			workload: &replicaScaledWorkload{&scaledObject{&kedav1alpha1.ScaledObject{}}},
+			want:     false,
+		},
+		{
+			name:     "suspend scaled resource without readiness",
+			workload: &suspendScaledWorkload{&cronJob{}},
+			want:     false,
+		},
+		{
+			name:     "redirected resource with readiness",
+			workload: &hpaRedirectedWorkload{Workload: &replicaScaledWorkload{&deployment{&appsv1.Deployment{}}}},
+			want:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, CanReportReadiness(test.workload))
+		})
+	}
+}
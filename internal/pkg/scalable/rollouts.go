@@ -11,6 +11,7 @@ import (
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"github.com/wI2L/jsondiff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getRollouts is the getResourceFunc for Argo Rollouts.
@@ -44,7 +45,7 @@ type rollout struct {
 	*argov1alpha1.Rollout
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (r *rollout) setReplicas(replicas int32) error {
 	r.Spec.Replicas = &replicas
 	return nil
@@ -60,6 +61,16 @@ func (r *rollout) getReplicas() (values.Replicas, error) {
 	return values.AbsoluteReplicas(*replicas), nil
 }
 
+// IsReady checks if the rollout is healthy and all of its replicas are ready.
+func (r *rollout) IsReady() bool {
+	replicas := int32(1)
+	if r.Spec.Replicas != nil {
+		replicas = *r.Spec.Replicas
+	}
+
+	return r.Status.Phase == argov1alpha1.RolloutPhaseHealthy && r.Status.ReadyReplicas >= replicas
+}
+
 // Reget regets the resource from the Kubernetes API.
 func (r *rollout) Reget(clientsets *Clientsets, ctx context.Context) error {
 	var err error
@@ -94,13 +105,15 @@ func (r *rollout) getSavedResourcesRequests(diffReplicas int32) *metrics.SavedRe
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (r *rollout) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Argo.ArgoprojV1alpha1().Rollouts(r.Namespace).Update(ctx, r.Rollout, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (r *rollout) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Argo.ArgoprojV1alpha1().Rollouts(r.Namespace).Patch(ctx, r.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update rollout: %w", err)
+		return fmt.Errorf("failed to patch rollout: %w", err)
 	}
 
+	r.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
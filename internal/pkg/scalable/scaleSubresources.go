@@ -0,0 +1,472 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	scaleSubresource = "scale"
+	// patchResourcePrefix and patchScalePrefix are the paths the custom resource and its scale subresource
+	// are compared under, so a single patch covers the changes of both
+	patchResourcePrefix = "/resource"
+	patchScalePrefix    = "/scale"
+)
+
+// getScaleSubresourceGroupResource gets the group resource of a resource type which is scaled through its scale subresource.
+// These resource types are given as "<resource>.<group>", e.g. "widgets.example.com". The bool is false for other resource types.
+func getScaleSubresourceGroupResource(resourceType string) (schema.GroupResource, bool) {
+	resource, group, found := strings.Cut(resourceType, ".")
+	if !found || resource == "" || group == "" {
+		return schema.GroupResource{}, false
+	}
+
+	return schema.GroupResource{Group: group, Resource: resource}, true
+}
+
+// getScaleSubresourceWorkloadsFunc gets the getResourceFunc for the custom resources of the group resource.
+func getScaleSubresourceWorkloadsFunc(groupResource schema.GroupResource) getResourceFunc {
+	return func(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+		resource, err := clientsets.Client.RESTMapper().ResourceFor(groupResource.WithVersion(""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get served version of %s: %w", groupResource, err)
+		}
+
+		return getScaleSubresourceWorkloads(resource, namespace, clientsets, ctx)
+	}
+}
+
+// getScaleSubresourceWorkloads gets the custom resources of the resource in the namespace.
+// Their replicas are read at the spec replicas path from their CustomResourceDefinition, so their scale subresources only have
+// to be requested one by one if the CustomResourceDefinition can't be read, e.g. because the downscaler is constrained to namespaces.
+// Custom resources whose replicas can't be read are skipped.
+func getScaleSubresourceWorkloads(
+	resource schema.GroupVersionResource,
+	namespace string,
+	clientsets *Clientsets,
+	ctx context.Context,
+) ([]Workload, error) {
+	list, err := clientsets.Dynamic.Resource(resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", resource.GroupResource(), err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	replicasPath, err := getSpecReplicasPath(resource, clientsets, ctx)
+	if err != nil {
+		slog.Debug("failed to get spec replicas path, getting the replicas from the scale subresources instead",
+			"resource", resource.GroupResource().String(), "error", err)
+	}
+
+	results := make([]Workload, 0, len(list.Items))
+
+	for i := range list.Items {
+		item := &list.Items[i]
+
+		var replicas int32
+		if replicasPath != nil {
+			replicas, err = getSpecReplicas(item, replicasPath)
+		} else {
+			replicas, err = getScaleReplicas(resource, item.GetNamespace(), item.GetName(), clientsets, ctx)
+		}
+
+		if err != nil {
+			slog.Error("failed to get replicas of custom resource, skipping",
+				"workload", item.GetName(), "namespace", item.GetNamespace(), "error", err)
+			continue
+		}
+
+		results = append(results, &replicaScaledWorkload{&scaleSubresourceWorkload{
+			Unstructured:    item,
+			resource:        resource,
+			replicas:        replicas,
+			patchedReplicas: replicas,
+		}})
+	}
+
+	return results, nil
+}
+
+// getSpecReplicas gets the replicas of the custom resource at the spec replicas path of its scale subresource.
+func getSpecReplicas(item *unstructured.Unstructured, replicasPath []string) (int32, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(item.Object, replicasPath...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get replicas of %s %s/%s: %w", item.GetKind(), item.GetNamespace(), item.GetName(), err)
+	}
+
+	// like on the scale subresource, missing replicas are zero
+	if !found {
+		return 0, nil
+	}
+
+	replicas, ok := unstructuredReplicasToInt32(val)
+	if !ok {
+		return 0, newUnexpectedReplicasTypeError(val, item.GetKind(), item.GetNamespace(), item.GetName())
+	}
+
+	return replicas, nil
+}
+
+// getScaleReplicas gets the replicas from the scale subresource of the custom resource.
+func getScaleReplicas(
+	resource schema.GroupVersionResource,
+	namespace, name string,
+	clientsets *Clientsets,
+	ctx context.Context,
+) (int32, error) {
+	scale, err := clientsets.Dynamic.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, scaleSubresource)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scale subresource of %s %s/%s: %w", resource.Resource, namespace, name, err)
+	}
+
+	val, found, err := unstructured.NestedFieldNoCopy(scale.Object, "spec", "replicas")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spec.replicas of scale subresource of %s %s/%s: %w", resource.Resource, namespace, name, err)
+	}
+
+	// the replicas of the scale subresource are omitted if they are zero
+	if !found {
+		return 0, nil
+	}
+
+	replicas, ok := unstructuredReplicasToInt32(val)
+	if !ok {
+		return 0, newUnexpectedReplicasTypeError(val, scale.GetKind(), namespace, name)
+	}
+
+	return replicas, nil
+}
+
+// scaleSubresourceWorkload is a custom resource which is scaled through its scale subresource.
+// Its replicas are read from and written to the scale subresource, while its annotations are patched on the custom resource.
+type scaleSubresourceWorkload struct {
+	*unstructured.Unstructured
+	resource schema.GroupVersionResource
+	replicas int32
+	// patchedReplicas are the replicas of the scale subresource on Kubernetes, used to only patch them if they changed
+	patchedReplicas int32
+}
+
+// getReplicas gets the current amount of replicas of the resource.
+func (s *scaleSubresourceWorkload) getReplicas() (values.Replicas, error) {
+	return values.AbsoluteReplicas(s.replicas), nil
+}
+
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the resource is patched.
+func (s *scaleSubresourceWorkload) setReplicas(replicas int32) error {
+	s.replicas = replicas
+	return nil
+}
+
+// getSavedResourcesRequests returns the saved CPU and memory requests.
+// The pods of custom resources aren't known to the downscaler, so no resources are reported.
+func (s *scaleSubresourceWorkload) getSavedResourcesRequests(_ int32) *metrics.SavedResources {
+	return metrics.NewSavedResources(0, 0)
+}
+
+// Copy creates a deep copy of the workload.
+func (s *scaleSubresourceWorkload) Copy() (Workload, error) {
+	if s.Object == nil {
+		return nil, newNilUnderlyingObjectError(s.GetKind())
+	}
+
+	return &replicaScaledWorkload{&scaleSubresourceWorkload{
+		Unstructured:    s.DeepCopy(),
+		resource:        s.resource,
+		replicas:        s.replicas,
+		patchedReplicas: s.patchedReplicas,
+	}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+// The custom resource is compared under /resource and its replicas under /scale, so Patch can split the changes again.
+func (s *scaleSubresourceWorkload) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	replicaScaledCopy, ok := workloadCopy.(*replicaScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*replicaScaledWorkload)(nil), workloadCopy)
+	}
+
+	scaleCopy, ok := replicaScaledCopy.replicaScaledResource.(*scaleSubresourceWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*scaleSubresourceWorkload)(nil), replicaScaledCopy.replicaScaledResource)
+	}
+
+	if s.Object == nil || scaleCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(s.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(s.getComparedDocument(), scaleCopy.getComparedDocument())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", s.GetKind(), err)
+	}
+
+	return diff, nil
+}
+
+// getComparedDocument gets the document the workload is compared as.
+func (s *scaleSubresourceWorkload) getComparedDocument() map[string]any {
+	return map[string]any{
+		strings.TrimPrefix(patchResourcePrefix, "/"): s.Object,
+		strings.TrimPrefix(patchScalePrefix, "/"):    map[string]any{"spec": map[string]any{"replicas": s.replicas}},
+	}
+}
+
+// Reget regets the custom resource and its scale subresource to ensure the latest state.
+func (s *scaleSubresourceWorkload) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(s.resource).Namespace(s.GetNamespace()).Get(ctx, s.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s/%s: %w", s.resource.Resource, s.GetNamespace(), s.GetName(), err)
+	}
+
+	replicas, err := getScaleReplicas(s.resource, s.GetNamespace(), s.GetName(), clientsets, ctx)
+	if err != nil {
+		return err
+	}
+
+	s.Unstructured = fresh
+	s.replicas = replicas
+	s.patchedReplicas = replicas
+
+	return nil
+}
+
+// Patch applies the json patch created by Compare. The changes to the custom resource are patched first,
+// so the original replicas are stored on it before the scale subresource is changed.
+// Both patches are locked to the resource version of the custom resource, as it is shared with its scale subresource.
+func (s *scaleSubresourceWorkload) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	var operations jsondiff.Patch
+
+	err := json.Unmarshal(patch, &operations)
+	if err != nil {
+		return fmt.Errorf("failed to parse patch: %w", err)
+	}
+
+	resourcePatch := make(jsondiff.Patch, 0, len(operations))
+
+	for _, operation := range operations {
+		if !strings.HasPrefix(operation.Path, patchResourcePrefix+"/") {
+			continue
+		}
+
+		operation.Path = strings.TrimPrefix(operation.Path, patchResourcePrefix)
+		operation.From = strings.TrimPrefix(operation.From, patchResourcePrefix)
+		resourcePatch = append(resourcePatch, operation)
+	}
+
+	if len(resourcePatch) != 0 {
+		resourcePatchBytes, err := json.Marshal(lockResourceVersion(resourcePatch, s.GetResourceVersion()))
+		if err != nil {
+			return fmt.Errorf("failed to marshal patch of %s: %w", s.resource.Resource, err)
+		}
+
+		patched, err := clientsets.Dynamic.Resource(s.resource).Namespace(s.GetNamespace()).
+			Patch(ctx, s.GetName(), types.JSONPatchType, resourcePatchBytes, getPatchOptions())
+		if err != nil {
+			return fmt.Errorf("failed to patch %s %s/%s: %w", s.resource.Resource, s.GetNamespace(), s.GetName(), err)
+		}
+
+		s.SetResourceVersion(patched.GetResourceVersion())
+	}
+
+	if s.replicas == s.patchedReplicas {
+		return nil
+	}
+
+	scalePatch := fmt.Appendf(nil, `{"spec":{"replicas":%d}}`, s.replicas)
+	if s.GetResourceVersion() != "" {
+		scalePatch = fmt.Appendf(nil, `{"metadata":{"resourceVersion":%q},"spec":{"replicas":%d}}`, s.GetResourceVersion(), s.replicas)
+	}
+
+	patched, err := clientsets.Dynamic.Resource(s.resource).Namespace(s.GetNamespace()).
+		Patch(ctx, s.GetName(), types.MergePatchType, scalePatch, getPatchOptions(), scaleSubresource)
+	if err != nil {
+		return fmt.Errorf("failed to patch scale subresource of %s %s/%s: %w", s.resource.Resource, s.GetNamespace(), s.GetName(), err)
+	}
+
+	s.SetResourceVersion(patched.GetResourceVersion())
+	s.patchedReplicas = s.replicas
+
+	return nil
+}
+
+// ParseScaleSubresourceWorkloadFromRawObject parses the admission review of a custom resource scaled through its scale subresource.
+// The scale subresource can't be used before the custom resource is admitted, so its replicas are set on the custom resource
+// directly, at the spec replicas path of the scale subresource in its CustomResourceDefinition.
+//
+//nolint:ireturn // this function should return an interface type
+func ParseScaleSubresourceWorkloadFromRawObject(
+	resource schema.GroupVersionResource,
+	rawObject []byte,
+	clientsets *Clientsets,
+	ctx context.Context,
+) (Workload, error) {
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", resource.Resource, err)
+	}
+
+	replicasPath, err := getSpecReplicasPath(resource, clientsets, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &replicaScaledWorkload{&specReplicasWorkload{Unstructured: &u, resource: resource, replicasPath: replicasPath}}, nil
+}
+
+// getSpecReplicasPath gets the path of the replicas in the custom resource from the scale subresource of its CustomResourceDefinition.
+func getSpecReplicasPath(resource schema.GroupVersionResource, clientsets *Clientsets, ctx context.Context) ([]string, error) {
+	crd, err := clientsets.Dynamic.Resource(customResourceDefinitionResource).
+		Get(ctx, resource.GroupResource().String(), metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customresourcedefinition of %s: %w", resource.GroupResource(), err)
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of customresourcedefinition %s: %w", crd.GetName(), err)
+	}
+
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]any)
+		if !ok || versionMap["name"] != resource.Version {
+			continue
+		}
+
+		specReplicasPath, found, err := unstructured.NestedString(versionMap, "subresources", "scale", "specReplicasPath")
+		if err != nil || !found {
+			break
+		}
+
+		return strings.Split(strings.TrimPrefix(specReplicasPath, "."), "."), nil
+	}
+
+	return nil, newNoScaleSubresourceError(resource)
+}
+
+//nolint:gochecknoglobals // package-level resource required for the dynamic client
+var customResourceDefinitionResource = schema.GroupVersionResource{
+	Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions",
+}
+
+// specReplicasWorkload is a custom resource whose replicas are set at a field path.
+// It is used for declared custom resources and for custom resources in admission reviews,
+// which can't be scaled through their scale subresource yet.
+type specReplicasWorkload struct {
+	*unstructured.Unstructured
+	resource     schema.GroupVersionResource
+	replicasPath []string
+	// requestsPath is the optional JSONPath to the resource requests of a single replica
+	requestsPath string
+}
+
+// getReplicas gets the current amount of replicas of the resource.
+func (s *specReplicasWorkload) getReplicas() (values.Replicas, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(s.Object, s.replicasPath...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicas for %s %s/%s: %w", s.GetKind(), s.GetNamespace(), s.GetName(), err)
+	}
+
+	if !found {
+		return nil, newNoReplicasError(s.GetKind(), s.GetName())
+	}
+
+	replicas, ok := unstructuredReplicasToInt32(val)
+	if !ok {
+		return nil, newUnexpectedReplicasTypeError(val, s.GetKind(), s.GetNamespace(), s.GetName())
+	}
+
+	return values.AbsoluteReplicas(replicas), nil
+}
+
+// setReplicas sets the amount of replicas on the resource.
+func (s *specReplicasWorkload) setReplicas(replicas int32) error {
+	if err := unstructured.SetNestedField(s.Object, int64(replicas), s.replicasPath...); err != nil {
+		return fmt.Errorf("failed to set replicas for %s %s/%s: %w", s.GetKind(), s.GetNamespace(), s.GetName(), err)
+	}
+
+	return nil
+}
+
+// getSavedResourcesRequests returns the saved CPU and memory requests, if the requests path of the resource is known.
+func (s *specReplicasWorkload) getSavedResourcesRequests(diffReplicas int32) *metrics.SavedResources {
+	cpu, memory := getCustomResourceRequests(s.Object, s.requestsPath)
+
+	return metrics.NewSavedResources(cpu*float64(diffReplicas), memory*float64(diffReplicas))
+}
+
+// Copy creates a deep copy of the workload.
+func (s *specReplicasWorkload) Copy() (Workload, error) {
+	if s.Object == nil {
+		return nil, newNilUnderlyingObjectError(s.GetKind())
+	}
+
+	return &replicaScaledWorkload{&specReplicasWorkload{
+		Unstructured: s.DeepCopy(),
+		resource:     s.resource,
+		replicasPath: s.replicasPath,
+		requestsPath: s.requestsPath,
+	}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (s *specReplicasWorkload) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	replicaScaledCopy, ok := workloadCopy.(*replicaScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*replicaScaledWorkload)(nil), workloadCopy)
+	}
+
+	specReplicasCopy, ok := replicaScaledCopy.replicaScaledResource.(*specReplicasWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*specReplicasWorkload)(nil), replicaScaledCopy.replicaScaledResource)
+	}
+
+	if s.Object == nil || specReplicasCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(s.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(s.Object, specReplicasCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", s.GetKind(), err)
+	}
+
+	return diff, nil
+}
+
+// Reget regets the workload to ensure the latest state.
+func (s *specReplicasWorkload) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(s.resource).Namespace(s.GetNamespace()).Get(ctx, s.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s/%s: %w", s.resource.Resource, s.GetNamespace(), s.GetName(), err)
+	}
+
+	s.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (s *specReplicasWorkload) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(s.resource).Namespace(s.GetNamespace()).
+		Patch(ctx, s.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s/%s: %w", s.resource.Resource, s.GetNamespace(), s.GetName(), err)
+	}
+
+	s.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
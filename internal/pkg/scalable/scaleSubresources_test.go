@@ -0,0 +1,313 @@
+package scalable
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+//nolint:gochecknoglobals // shared test resource
+var testWidgetResource = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+// newTestWidget builds a custom resource of the test widget resource.
+func newTestWidget(spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]any{
+			"name":      "test-widget",
+			"namespace": "default",
+		},
+		"spec": spec,
+	}}
+}
+
+func TestGetScaleSubresourceGroupResource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		resourceType      string
+		wantGroupResource schema.GroupResource
+		wantFound         bool
+	}{
+		{
+			name:              "custom resource",
+			resourceType:      "widgets.example.com",
+			wantGroupResource: schema.GroupResource{Group: "example.com", Resource: "widgets"},
+			wantFound:         true,
+		},
+		{
+			name:         "resource type without group",
+			resourceType: "deployments",
+			wantFound:    false,
+		},
+		{
+			name:         "empty resource",
+			resourceType: ".example.com",
+			wantFound:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			groupResource, found := getScaleSubresourceGroupResource(test.resourceType)
+			assert.Equal(t, test.wantFound, found)
+			assert.Equal(t, test.wantGroupResource, groupResource)
+		})
+	}
+}
+
+func TestScaleSubresourceWorkload_Patch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		scale           func(workload Workload) error
+		wantScalePatch  string
+		wantResourceOps int
+	}{
+		{
+			name: "scale down",
+			scale: func(workload Workload) error {
+				_, _, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+				return err
+			},
+			wantScalePatch:  `{"spec":{"replicas":0}}`,
+			wantResourceOps: 1,
+		},
+		{
+			name: "only annotations changed",
+			scale: func(workload Workload) error {
+				SetLastTransition(workload, time.Date(2024, time.March, 4, 8, 0, 0, 0, time.UTC))
+				return nil
+			},
+			wantScalePatch:  "",
+			wantResourceOps: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+			var resourcePatch, scalePatch []byte
+
+			dynamicClient.PrependReactor("patch", "widgets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				patchAction, _ := action.(clienttesting.PatchAction)
+				if patchAction.GetSubresource() == scaleSubresource {
+					scalePatch = patchAction.GetPatch()
+				} else {
+					resourcePatch = patchAction.GetPatch()
+				}
+
+				return true, &unstructured.Unstructured{}, nil
+			})
+
+			workload := &replicaScaledWorkload{&scaleSubresourceWorkload{
+				Unstructured:    newTestWidget(map[string]any{}),
+				resource:        testWidgetResource,
+				replicas:        3,
+				patchedReplicas: 3,
+			}}
+
+			original, err := workload.Copy()
+			require.NoError(t, err)
+
+			require.NoError(t, test.scale(workload))
+			require.NoError(t, PatchWorkload(original, workload, &Clientsets{Dynamic: dynamicClient}, t.Context()))
+
+			var operations []map[string]any
+			require.NoError(t, json.Unmarshal(resourcePatch, &operations))
+			assert.Len(t, operations, test.wantResourceOps)
+
+			for _, operation := range operations {
+				assert.NotContains(t, operation["path"], patchResourcePrefix)
+			}
+
+			if test.wantScalePatch == "" {
+				assert.Nil(t, scalePatch)
+				return
+			}
+
+			assert.JSONEq(t, test.wantScalePatch, string(scalePatch))
+		})
+	}
+}
+
+func TestGetSpecReplicasPath(t *testing.T) {
+	t.Parallel()
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "widgets.example.com"},
+		"spec": map[string]any{
+			"versions": []any{
+				map[string]any{"name": "v1alpha1"},
+				map[string]any{
+					"name":         "v1",
+					"subresources": map[string]any{"scale": map[string]any{"specReplicasPath": ".spec.size.replicas"}},
+				},
+			},
+		},
+	}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{customResourceDefinitionResource: "CustomResourceDefinitionList"},
+		crd,
+	)
+	clientsets := &Clientsets{Dynamic: dynamicClient}
+
+	replicasPath, err := getSpecReplicasPath(testWidgetResource, clientsets, t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spec", "size", "replicas"}, replicasPath)
+
+	alphaResource := schema.GroupVersionResource{Group: "example.com", Version: "v1alpha1", Resource: "widgets"}
+	_, err = getSpecReplicasPath(alphaResource, clientsets, t.Context())
+
+	var noScaleSubresourceErr *NoScaleSubresourceError
+	require.ErrorAs(t, err, &noScaleSubresourceErr)
+}
+
+func TestGetScaleSubresourceWorkloads(t *testing.T) {
+	t.Parallel()
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "widgets.example.com"},
+		"spec": map[string]any{
+			"versions": []any{map[string]any{
+				"name":         "v1",
+				"subresources": map[string]any{"scale": map[string]any{"specReplicasPath": ".spec.replicas"}},
+			}},
+		},
+	}}
+
+	newWidget := func(name string, replicas any) *unstructured.Unstructured {
+		widget := newTestWidget(map[string]any{"replicas": replicas})
+		widget.SetName(name)
+
+		return widget
+	}
+
+	tests := []struct {
+		name          string
+		withCRD       bool
+		wantReplicas  map[string]int32
+		wantScaleGets int
+	}{
+		{
+			name:          "replicas read from the spec replicas path",
+			withCRD:       true,
+			wantReplicas:  map[string]int32{"widget-a": 3},
+			wantScaleGets: 0,
+		},
+		{
+			name:          "replicas read from the scale subresources without the customresourcedefinition",
+			withCRD:       false,
+			wantReplicas:  map[string]int32{"widget-a": 3},
+			wantScaleGets: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			objects := []runtime.Object{newWidget("widget-a", int64(3)), newWidget("widget-b", "invalid")}
+			if test.withCRD {
+				objects = append(objects, crd)
+			}
+
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+				runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					testWidgetResource:               "WidgetList",
+					customResourceDefinitionResource: "CustomResourceDefinitionList",
+				},
+				objects...,
+			)
+
+			var scaleGets int
+
+			dynamicClient.PrependReactor("get", "widgets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				getAction, _ := action.(clienttesting.GetAction)
+				if getAction.GetSubresource() != scaleSubresource {
+					return false, nil, nil
+				}
+
+				scaleGets++
+
+				if getAction.GetName() != "widget-a" {
+					return true, nil, apierrors.NewNotFound(testWidgetResource.GroupResource(), getAction.GetName())
+				}
+
+				return true, &unstructured.Unstructured{Object: map[string]any{
+					"kind": "Scale",
+					"spec": map[string]any{"replicas": int64(3)},
+				}}, nil
+			})
+
+			workloads, err := getScaleSubresourceWorkloads(testWidgetResource, "default", &Clientsets{Dynamic: dynamicClient}, t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, test.wantScaleGets, scaleGets)
+
+			replicas := make(map[string]int32, len(workloads))
+
+			for _, workload := range workloads {
+				replicaScaled, ok := workload.(*replicaScaledWorkload)
+				require.True(t, ok)
+
+				scaleWorkload, ok := replicaScaled.replicaScaledResource.(*scaleSubresourceWorkload)
+				require.True(t, ok)
+
+				replicas[workload.GetName()] = scaleWorkload.replicas
+			}
+
+			assert.Equal(t, test.wantReplicas, replicas, "custom resources whose replicas can't be read should be skipped")
+		})
+	}
+}
+
+func TestSpecReplicasWorkload_ScaleDown(t *testing.T) {
+	t.Parallel()
+
+	workload := &replicaScaledWorkload{&specReplicasWorkload{
+		Unstructured: newTestWidget(map[string]any{"size": map[string]any{"replicas": int64(3)}}),
+		resource:     testWidgetResource,
+		replicasPath: []string{"spec", "size", "replicas"},
+	}}
+
+	original, err := workload.Copy()
+	require.NoError(t, err)
+
+	_, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	patch, err := original.Compare(workload)
+	require.NoError(t, err)
+
+	assert.Contains(t, patch.String(), `"path":"/spec/size/replicas"`)
+
+	originalReplicas, ok := GetOriginalReplicasValue(workload)
+	assert.True(t, ok)
+	assert.Equal(t, "3", originalReplicas)
+}
@@ -0,0 +1,161 @@
+//nolint:dupl // necessary to handle different workload types separately
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getScaledJobs is the getResourceFunc for Keda ScaledJobs.
+func getScaledJobs(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+	scaledjobs, err := clientsets.Keda.KedaV1alpha1().ScaledJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scaledjobs: %w", err)
+	}
+
+	results := make([]Workload, 0, len(scaledjobs.Items))
+	for i := range scaledjobs.Items {
+		setGroupVersionKindIfEmpty(&scaledjobs.Items[i], kedav1alpha1.SchemeGroupVersion.WithKind("ScaledJob"))
+		results = append(results, &replicaScaledWorkload{&scaledJob{&scaledjobs.Items[i]}})
+	}
+
+	return results, nil
+}
+
+// parseScaledJobFromBytes parses the admission review and returns the scaledJob.
+func parseScaledJobFromBytes(rawObject []byte) (Workload, error) {
+	var sj kedav1alpha1.ScaledJob
+	if err := json.Unmarshal(rawObject, &sj); err != nil {
+		return nil, fmt.Errorf("failed to decode ScaledJob: %w", err)
+	}
+
+	return &replicaScaledWorkload{&scaledJob{&sj}}, nil
+}
+
+// scaledJob is a wrapper for scaledjob.v1alpha1.keda.sh to implement the replicaScaledResource interface.
+// Its replicas are the maximum amount of jobs running at once. Scaling it to 0 pauses it instead,
+// so Keda stops creating jobs without touching its maxReplicaCount.
+type scaledJob struct {
+	*kedav1alpha1.ScaledJob
+}
+
+// setReplicas pauses the scaledJob for 0 replicas or sets its maxReplicaCount otherwise.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (s *scaledJob) setReplicas(replicas int32) error {
+	if replicas == 0 {
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+
+		s.Annotations[kedav1alpha1.PausedAnnotation] = strconv.FormatBool(true)
+
+		return nil
+	}
+
+	delete(s.Annotations, kedav1alpha1.PausedAnnotation)
+
+	if replicas == util.Undefined { // maxReplicaCount was not defined before workload was downscaled
+		s.Spec.MaxReplicaCount = nil
+		return nil
+	}
+
+	s.Spec.MaxReplicaCount = &replicas
+
+	return nil
+}
+
+// getReplicas gets 0 if the scaledJob is paused or its maxReplicaCount otherwise.
+func (s *scaledJob) getReplicas() (values.Replicas, error) {
+	if paused, _ := strconv.ParseBool(s.Annotations[kedav1alpha1.PausedAnnotation]); paused {
+		return values.AbsoluteReplicas(0), nil
+	}
+
+	if s.Spec.MaxReplicaCount == nil {
+		return values.AbsoluteReplicas(util.Undefined), nil
+	}
+
+	return values.AbsoluteReplicas(*s.Spec.MaxReplicaCount), nil
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (s *scaledJob) Reget(clientsets *Clientsets, ctx context.Context) error {
+	var err error
+
+	s.ScaledJob, err = clientsets.Keda.KedaV1alpha1().ScaledJobs(s.Namespace).Get(ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get scaledJob: %w", err)
+	}
+
+	setGroupVersionKindIfEmpty(s.ScaledJob, kedav1alpha1.SchemeGroupVersion.WithKind("ScaledJob"))
+
+	return nil
+}
+
+// getSavedResourcesRequests returns the total saved CPU and memory requests for the scaled job.
+// The jobs of a scaledJob only run on demand, so no resources are reported.
+func (s *scaledJob) getSavedResourcesRequests(_ int32) *metrics.SavedResources {
+	return metrics.NewSavedResources(0, 0)
+}
+
+// Patch applies the json patch to the resource.
+func (s *scaledJob) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Keda.KedaV1alpha1().ScaledJobs(s.Namespace).Patch(ctx, s.Name, types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch scaledJob: %w", err)
+	}
+
+	s.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the given Workload, which is expected to be a replicaScaledWorkload wrapping a scaledJob.
+func (s *scaledJob) Copy() (Workload, error) {
+	if s.ScaledJob == nil {
+		return nil, newNilUnderlyingObjectError(s.Kind)
+	}
+
+	copied := s.DeepCopy()
+
+	return &replicaScaledWorkload{
+		replicaScaledResource: &scaledJob{
+			ScaledJob: copied,
+		},
+	}, nil
+}
+
+// Compare compares two scaledJob resources and returns the differences as a jsondiff.Patch.
+//
+//nolint:varnamelen //required for interface-based workflow
+func (s *scaledJob) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	rswCopy, ok := workloadCopy.(*replicaScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*replicaScaledWorkload)(nil), workloadCopy)
+	}
+
+	sjCopy, ok := rswCopy.replicaScaledResource.(*scaledJob)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*scaledJob)(nil), rswCopy.replicaScaledResource)
+	}
+
+	if s.ScaledJob == nil || sjCopy.ScaledJob == nil {
+		return nil, newNilUnderlyingObjectError(s.Kind)
+	}
+
+	diff, err := jsondiff.Compare(s.ScaledJob, sjCopy.ScaledJob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare scaledJobs: %w", err)
+	}
+
+	return diff, nil
+}
@@ -13,6 +13,7 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/wI2L/jsondiff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -50,7 +51,8 @@ type scaledObject struct {
 	*kedav1alpha1.ScaledObject
 }
 
-// setReplicas sets the pausedReplicas annotation to the specified replicas. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the pausedReplicas annotation to the specified replicas.
+// Changes won't be made on Kubernetes until the workload is patched.
 func (s *scaledObject) setReplicas(replicas int32) error {
 	if replicas == util.Undefined { // pausedAnnotation was not defined before workload was downscaled
 		delete(s.Annotations, annotationKedaPausedReplicas)
@@ -104,13 +106,15 @@ func (s *scaledObject) getSavedResourcesRequests(_ int32) *metrics.SavedResource
 	return metrics.NewSavedResources(0, 0)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (s *scaledObject) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Keda.KedaV1alpha1().ScaledObjects(s.Namespace).Update(ctx, s.ScaledObject, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (s *scaledObject) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Keda.KedaV1alpha1().ScaledObjects(s.Namespace).Patch(ctx, s.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update scaledObject: %w", err)
+		return fmt.Errorf("failed to patch scaledObject: %w", err)
 	}
 
+	s.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -90,7 +91,7 @@ type service struct {
 	*corev1.Service
 }
 
-// setValue sets the value on the resource. Changes won't be made on Kubernetes until update() is called.
+// setValue sets the value on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (s *service) setValue(value values.Replicas) error {
 	// only allow LoadBalancer or ClusterIP
 	if value.String() != string(corev1.ServiceTypeLoadBalancer) && value.String() != string(corev1.ServiceTypeClusterIP) {
@@ -130,13 +131,15 @@ func (s *service) Reget(clientsets *Clientsets, ctx context.Context) error {
 	return nil
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (s *service) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.CoreV1().Services(s.Namespace).Update(ctx, s.Service, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (s *service) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.CoreV1().Services(s.Namespace).Patch(ctx, s.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update service: %w", err)
+		return fmt.Errorf("failed to patch service: %w", err)
 	}
 
+	s.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
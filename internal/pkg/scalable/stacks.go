@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	zalandov1 "github.com/zalando-incubator/stackset-controller/pkg/apis/zalando.org/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getStacks is the getResourceFunc for Zalando Stacks.
@@ -44,7 +45,7 @@ type stack struct {
 	*zalandov1.Stack
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (s *stack) setReplicas(replicas int32) error {
 	s.Spec.Replicas = &replicas
 	return nil
@@ -94,13 +95,15 @@ func (s *stack) getSavedResourcesRequests(diffReplicas int32) *metrics.SavedReso
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (s *stack) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Zalando.ZalandoV1().Stacks(s.Namespace).Update(ctx, s.Stack, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (s *stack) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Zalando.ZalandoV1().Stacks(s.Namespace).Patch(ctx, s.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update stack: %w", err)
+		return fmt.Errorf("failed to patch stack: %w", err)
 	}
 
+	s.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/wI2L/jsondiff"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // getStatefulSets is the getResourceFunc for StatefulSets.
@@ -44,7 +45,7 @@ type statefulSet struct {
 	*appsv1.StatefulSet
 }
 
-// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until update() is called.
+// setReplicas sets the amount of replicas on the resource. Changes won't be made on Kubernetes until the workload is patched.
 func (s *statefulSet) setReplicas(replicas int32) error {
 	s.Spec.Replicas = &replicas
 	return nil
@@ -60,6 +61,16 @@ func (s *statefulSet) getReplicas() (values.Replicas, error) {
 	return values.AbsoluteReplicas(*replicas), nil
 }
 
+// IsReady checks if all replicas of the latest generation of the statefulset are ready.
+func (s *statefulSet) IsReady() bool {
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	return s.Status.ObservedGeneration >= s.Generation && s.Status.UpdatedReplicas >= replicas && s.Status.ReadyReplicas >= replicas
+}
+
 // Reget regets the resource from the Kubernetes API.
 func (s *statefulSet) Reget(clientsets *Clientsets, ctx context.Context) error {
 	var err error
@@ -94,13 +105,15 @@ func (s *statefulSet) getSavedResourcesRequests(diffReplicas int32) *metrics.Sav
 	return metrics.NewSavedResources(totalSavedCPU, totalSavedMemory)
 }
 
-// Update updates the resource with all changes made to it. It should only be called once on a resource.
-func (s *statefulSet) Update(clientsets *Clientsets, ctx context.Context) error {
-	_, err := clientsets.Kubernetes.AppsV1().StatefulSets(s.Namespace).Update(ctx, s.StatefulSet, metav1.UpdateOptions{})
+// Patch applies the json patch to the resource.
+func (s *statefulSet) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Kubernetes.AppsV1().StatefulSets(s.Namespace).Patch(ctx, s.Name, types.JSONPatchType, patch, getPatchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to update statefulset: %w", err)
+		return fmt.Errorf("failed to patch statefulset: %w", err)
 	}
 
+	s.SetResourceVersion(patched.GetResourceVersion())
+
 	return nil
 }
 
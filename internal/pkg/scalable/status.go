@@ -0,0 +1,117 @@
+package scalable
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const annotationStatus = "downscaler/status"
+
+// StatusState is the state of a workload as seen by the downscaler.
+type StatusState string
+
+const (
+	// StatusScaledDown means the workload is scaled down.
+	StatusScaledDown StatusState = "scaled-down"
+	// StatusScaledUp means the workload is scaled up.
+	StatusScaledUp StatusState = "scaled-up"
+	// StatusExcluded means the workload is excluded and isn't scaled.
+	StatusExcluded StatusState = "excluded"
+	// StatusGracePeriod means the workload is in its grace period and isn't scaled yet.
+	StatusGracePeriod StatusState = "grace-period"
+	// StatusManualOverride means the replicas of the scaled down workload were changed manually and are respected.
+	StatusManualOverride StatusState = "manual-override"
+)
+
+// Status describes how the downscaler manages the workload. It is kept in an annotation on the workload for its users.
+type Status struct {
+	// State is the current state of the workload, it defaults to scaled-down or scaled-up depending on the workload.
+	State StatusState `json:"state"`
+	// Scope is the scope deciding the current scaling of the workload.
+	Scope string `json:"scope,omitempty"`
+	// NextTransition is the next time the scaling of the workload changes.
+	NextTransition *time.Time `json:"nextTransition,omitempty"`
+	// LastAction is the last time the workload was scaled down or up, it is taken from the workload.
+	LastAction *time.Time `json:"lastAction,omitempty"`
+	// LastError is the error of the last scan of the workload.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// GetStatus gets the status of the workload from its annotation. An empty status is returned if the annotation isn't set.
+func GetStatus(workload Workload) (Status, error) {
+	var status Status
+
+	statusString, ok := workload.GetAnnotations()[annotationStatus]
+	if !ok {
+		return status, nil
+	}
+
+	err := json.Unmarshal([]byte(statusString), &status)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to parse status annotation: %w", err)
+	}
+
+	return status, nil
+}
+
+// SetStatus sets the status annotation on the workload. Changes won't be made on Kubernetes until the workload is patched.
+// It returns true if the annotation changed.
+func SetStatus(workload Workload, status Status) (bool, error) {
+	statusString, err := getStatusString(workload, status)
+	if err != nil {
+		return false, err
+	}
+
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if annotations[annotationStatus] == statusString {
+		return false, nil
+	}
+
+	annotations[annotationStatus] = statusString
+	workload.SetAnnotations(annotations)
+
+	return true, nil
+}
+
+// IsStatusChanged checks if the status differs from the status annotation of the workload.
+func IsStatusChanged(workload Workload, status Status) (bool, error) {
+	statusString, err := getStatusString(workload, status)
+	if err != nil {
+		return false, err
+	}
+
+	return workload.GetAnnotations()[annotationStatus] != statusString, nil
+}
+
+// getStatusString completes the status with the state of the workload and marshals it.
+func getStatusString(workload Workload, status Status) (string, error) {
+	if status.State == "" {
+		status.State = StatusScaledUp
+		if IsScaledDown(workload) {
+			status.State = StatusScaledDown
+		}
+	}
+
+	lastTransition, err := GetLastTransition(workload)
+	if err == nil && !lastTransition.IsZero() {
+		lastTransition = lastTransition.UTC()
+		status.LastAction = &lastTransition
+	}
+
+	if status.NextTransition != nil {
+		nextTransition := status.NextTransition.UTC().Truncate(time.Second)
+		status.NextTransition = &nextTransition
+	}
+
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	return string(statusBytes), nil
+}
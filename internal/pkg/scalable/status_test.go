@@ -0,0 +1,99 @@
+package scalable
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatus(t *testing.T) {
+	t.Parallel()
+
+	nextTransition := time.Date(2024, time.March, 4, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		status          Status
+		wantChanged     bool
+		wantAnnotations map[string]string
+	}{
+		{
+			name:        "scaled down workload",
+			annotations: map[string]string{annotationOriginalReplicas: "3", annotationLastTransition: "2024-03-04T08:00:00Z"},
+			status:      Status{Scope: "namespace", NextTransition: &nextTransition},
+			wantChanged: true,
+			wantAnnotations: map[string]string{
+				annotationOriginalReplicas: "3",
+				annotationLastTransition:   "2024-03-04T08:00:00Z",
+				annotationStatus: `{"state":"scaled-down","scope":"namespace","nextTransition":"2024-03-04T18:00:00Z",` +
+					`"lastAction":"2024-03-04T08:00:00Z"}`,
+			},
+		},
+		{
+			name:            "excluded workload",
+			annotations:     nil,
+			status:          Status{State: StatusExcluded},
+			wantChanged:     true,
+			wantAnnotations: map[string]string{annotationStatus: `{"state":"excluded"}`},
+		},
+		{
+			name:            "unchanged status",
+			annotations:     map[string]string{annotationStatus: `{"state":"scaled-up","lastError":"failed"}`},
+			status:          Status{LastError: "failed"},
+			wantChanged:     false,
+			wantAnnotations: map[string]string{annotationStatus: `{"state":"scaled-up","lastError":"failed"}`},
+		},
+		{
+			name:            "error is cleared",
+			annotations:     map[string]string{annotationStatus: `{"state":"scaled-up","lastError":"failed"}`},
+			status:          Status{},
+			wantChanged:     true,
+			wantAnnotations: map[string]string{annotationStatus: `{"state":"scaled-up"}`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := &replicaScaledWorkload{&deployment{Deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: test.annotations},
+			}}}
+
+			isChanged, err := IsStatusChanged(workload, test.status)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantChanged, isChanged)
+
+			changed, err := SetStatus(workload, test.status)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantChanged, changed)
+			assert.Equal(t, test.wantAnnotations, workload.GetAnnotations())
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+
+	workload := &replicaScaledWorkload{&deployment{Deployment: &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "default",
+			Annotations: map[string]string{annotationStatus: `{"state":"excluded","lastError":"failed"}`},
+		},
+	}}}
+
+	status, err := GetStatus(workload)
+	require.NoError(t, err)
+	assert.Equal(t, Status{State: StatusExcluded, LastError: "failed"}, status)
+
+	workload.SetAnnotations(map[string]string{annotationStatus: "invalid"})
+
+	_, err = GetStatus(workload)
+	require.Error(t, err)
+}
@@ -14,8 +14,8 @@ import (
 // suspendScaledResource provides all the functions needed to scale a resource which is scaled by setting a suspend field.
 type suspendScaledResource interface {
 	scalableResource
-	// Update updates the resource with all changes made to it. It should only be called once on a resource
-	Update(clientsets *Clientsets, ctx context.Context) error
+	// Patch applies the json patch to the resource
+	Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error
 	// getSuspend gets the value of the suspend field on the workload
 	getSuspend() (values.Replicas, values.Replicas)
 	// setSuspend sets the value of the suspend field on the workload
@@ -48,6 +48,17 @@ func (r *suspendScaledWorkload) GetChildren(ctx context.Context, clientsets *Cli
 	return children, nil
 }
 
+// IsReady delegates the readiness check to the wrapped resource when it supports ReadyWorkload.
+// Resources which can't report their readiness are always considered ready.
+func (r *suspendScaledWorkload) IsReady() bool {
+	ready, ok := r.suspendScaledResource.(ReadyWorkload)
+	if !ok {
+		return true
+	}
+
+	return ready.IsReady()
+}
+
 // ScaleUp scales up the underlying suspendScaledResource.
 func (r *suspendScaledWorkload) ScaleUp() (bool, error) {
 	originalState, err := getOriginalReplicas(r)
@@ -11,8 +11,39 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// newTestArgoCDApplication builds an application with the given sync policy.
+func newTestArgoCDApplication(syncPolicy map[string]any) *argoCDApplication {
+	return &argoCDApplication{&unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]any{
+			"name":      "guestbook",
+			"namespace": "argocd",
+		},
+		"spec": map[string]any{"syncPolicy": syncPolicy},
+	}}}
+}
+
+// newTestCNPGCluster builds a cnpg cluster with the given annotations, instances and resource requests.
+func newTestCNPGCluster(annotations map[string]any, instances int64, requests map[string]any) *cnpgCluster {
+	return &cnpgCluster{&unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "postgresql.cnpg.io/v1",
+		"kind":       "Cluster",
+		"metadata": map[string]any{
+			"name":        "test-cluster",
+			"namespace":   "default",
+			"annotations": annotations,
+		},
+		"spec": map[string]any{
+			"instances": instances,
+			"resources": map[string]any{"requests": requests},
+		},
+	}}}
+}
+
 func TestSuspendScaledWorkload_ScaleUp(t *testing.T) {
 	t.Parallel()
 
@@ -182,6 +213,103 @@ func TestSuspendScaledWorkload_ScaleDown(t *testing.T) {
 	}
 }
 
+// TestSuspendScaledWorkload_ScaleDownAndUp scales the suspend scaled resources down and back up,
+// checking that scaling up restores the exact state they had before.
+func TestSuspendScaledWorkload_ScaleDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		newResource   func() suspendScaledResource
+		wantSuspended bool
+		wantUpdated   bool
+		wantCPU       float64
+		wantMemory    float64
+	}{
+		{
+			name: "argocd application with automated sync",
+			newResource: func() suspendScaledResource {
+				return newTestArgoCDApplication(map[string]any{
+					"automated":   map[string]any{"prune": true, "selfHeal": true},
+					"syncOptions": []any{"CreateNamespace=true"},
+				})
+			},
+			wantSuspended: false,
+			wantUpdated:   true,
+		},
+		{
+			name: "argocd application with manual sync",
+			newResource: func() suspendScaledResource {
+				return newTestArgoCDApplication(map[string]any{})
+			},
+			wantSuspended: true,
+			wantUpdated:   false,
+		},
+		{
+			name: "argocd application with disabled automated sync",
+			newResource: func() suspendScaledResource {
+				return newTestArgoCDApplication(map[string]any{"automated": map[string]any{"enabled": false}})
+			},
+			wantSuspended: true,
+			wantUpdated:   false,
+		},
+		{
+			name: "running cnpg cluster",
+			newResource: func() suspendScaledResource {
+				return newTestCNPGCluster(nil, 3, map[string]any{"cpu": "500m", "memory": "512Mi"})
+			},
+			wantSuspended: false,
+			wantUpdated:   true,
+			wantCPU:       1.5,
+			wantMemory:    3 * 512 * 1024 * 1024,
+		},
+		{
+			name: "cnpg cluster hibernated by its owner",
+			newResource: func() suspendScaledResource {
+				return newTestCNPGCluster(
+					map[string]any{annotationCNPGHibernation: cnpgHibernationOn}, 3, map[string]any{"cpu": "500m", "memory": "512Mi"},
+				)
+			},
+			wantSuspended: true,
+			wantUpdated:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := test.newResource()
+			workload := &suspendScaledWorkload{resource}
+
+			suspended, target := resource.getSuspend()
+			assert.Equal(t, values.BooleanReplicas(test.wantSuspended), suspended)
+
+			savedResources, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+			assert.InDelta(t, test.wantCPU, savedResources.TotalCPU(), 0.001)
+			assert.InDelta(t, test.wantMemory, savedResources.TotalMemory(), 0.001)
+
+			suspended, _ = resource.getSuspend()
+			assert.Equal(t, target, suspended)
+
+			_, updated, err = workload.ScaleDown(values.AbsoluteReplicas(0))
+			require.NoError(t, err)
+			assert.False(t, updated, "a scaled down workload shouldn't be scaled down again")
+
+			updated, err = workload.ScaleUp()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+
+			original := test.newResource()
+			clearEmptyMetadata(original)
+			clearEmptyMetadata(resource)
+			assert.Equal(t, original, resource, "scaling up should restore the state before scaling down")
+		})
+	}
+}
+
 // TestCronJobGetChildren verifies the GetChildren method.
 func TestCronJobGetChildren(t *testing.T) {
 	t.Parallel()
@@ -1,36 +1,56 @@
 package scalable
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	annotationOriginalReplicas          = "downscaler/original-replicas"
-	defaultKedaScaleTargetRefApiVersion = "apps/v1"
-	defaultKedaScaleTargetRefKind       = "Deployment"
-	kafkaStrimziGroup                   = "kafka.strimzi.io"
-	kafkaStrimziVersion                 = "v1"
+	annotationOriginalReplicas      = "downscaler/original-replicas"
+	annotationLastTransition        = "downscaler/last-transition"
+	annotationAppliedReplicas       = "downscaler/applied-replicas"
+	annotationManualOverrideSince   = "downscaler/manual-override-since"
+	defaultScaleTargetRefApiVersion = "apps/v1"
+	defaultScaleTargetRefKind       = "Deployment"
+	kafkaStrimziGroup               = "kafka.strimzi.io"
+	kafkaStrimziVersion             = "v1"
+	resourceVersionPath             = "/metadata/resourceVersion"
+
+	// FieldManager is the field manager all changes of the downscaler are made with.
+	FieldManager = "kube-downscaler"
 )
 
 // FilterExcluded filters the workloads to match the includeLabels, excludedNamespaces and excludedWorkloads.
+// Workloads scaled by one of the workloads or autoscalers are excluded, so autoscalers which aren't included are still respected.
 func FilterExcluded(
-	workloads []Workload,
+	workloads,
+	autoscalers []Workload,
 	includeLabels,
 	excludedNamespaces,
 	excludedWorkloads util.RegexList,
 	currentNamespaceToMetrics map[string]*metrics.NamespaceMetricsHolder,
 ) []Workload {
-	externallyScaled := getExternallyScaled(workloads)
+	externallyScaled := getExternallyScaled(slices.Concat(workloads, autoscalers))
 
 	results := make([]Workload, 0, len(workloads))
 
@@ -117,49 +137,90 @@ func setGroupVersionKindIfEmpty(obj runtime.Object, gvk schema.GroupVersionKind)
 	obj.GetObjectKind().SetGroupVersionKind(gvk)
 }
 
+// listDynamicWorkloads lists the resources in the namespace through the dynamic client and wraps them as workloads.
+// This way the CRDs of the resource don't have to be installed. If they aren't, the resource type is already reported as
+// not served by the resource type discovery, so it has no workloads.
+func listDynamicWorkloads(
+	resource schema.GroupVersionResource,
+	namespace string,
+	clientsets *Clientsets,
+	ctx context.Context,
+	newWorkload func(item *unstructured.Unstructured) Workload,
+) ([]Workload, error) {
+	list, err := clientsets.Dynamic.Resource(resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			slog.Debug("resource is not served by the cluster, skipping", "resource", resource.GroupResource().String(), "error", err)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s: %w", resource.GroupResource(), err)
+	}
+
+	results := make([]Workload, 0, len(list.Items))
+	for i := range list.Items {
+		results = append(results, newWorkload(&list.Items[i]))
+	}
+
+	return results, nil
+}
+
 // getExternallyScaled returns identifiers for workloads which are being scaled externally and should therefore be excluded.
+// Workloads are scaled externally if they are the scale target of a Keda ScaledObject or a HorizontalPodAutoscaler.
 func getExternallyScaled(workloads []Workload) []workloadIdentifier {
 	externallyScaled := make([]workloadIdentifier, 0, len(workloads))
 
 	for _, workload := range workloads {
-		scaledobject := getWorkloadAsScaledObject(workload)
-		if scaledobject == nil {
+		if scaledobject := getWorkloadAsScaledObject(workload); scaledobject != nil {
+			externallyScaled = append(externallyScaled, newScaleTargetIdentifier(
+				scaledobject.Spec.ScaleTargetRef.APIVersion,
+				scaledobject.Spec.ScaleTargetRef.Kind,
+				scaledobject.Spec.ScaleTargetRef.Name,
+				scaledobject.Namespace,
+			))
+
 			continue
 		}
 
-		var version, group string
-		apiVersion := scaledobject.Spec.ScaleTargetRef.APIVersion
-		kind := scaledobject.Spec.ScaleTargetRef.Kind
-
-		if apiVersion == "" {
-			apiVersion = defaultKedaScaleTargetRefApiVersion
+		if hpa := getWorkloadAsHorizontalPodAutoscaler(workload); hpa != nil {
+			externallyScaled = append(externallyScaled, getHorizontalPodAutoscalerTarget(hpa))
 		}
+	}
 
-		if kind == "" {
-			kind = defaultKedaScaleTargetRefKind
-		}
+	return slices.Clip(externallyScaled)
+}
 
-		apiVersionSlice := strings.SplitN(apiVersion, "/", 2)
-		if len(apiVersionSlice) < 2 {
-			group = ""
-			version = apiVersionSlice[0]
-		} else {
-			group = apiVersionSlice[0]
-			version = apiVersionSlice[1]
-		}
+// newScaleTargetIdentifier creates the identifier of the scale target referenced by an autoscaler.
+// The API version and kind default to the ones of a Deployment.
+func newScaleTargetIdentifier(apiVersion, kind, name, namespace string) workloadIdentifier {
+	var version, group string
 
-		externallyScaled = append(externallyScaled, workloadIdentifier{
-			gvk: schema.GroupVersionKind{
-				Kind:    kind,
-				Group:   group,
-				Version: version,
-			},
-			name:      scaledobject.Spec.ScaleTargetRef.Name,
-			namespace: scaledobject.Namespace,
-		})
+	if apiVersion == "" {
+		apiVersion = defaultScaleTargetRefApiVersion
 	}
 
-	return slices.Clip(externallyScaled)
+	if kind == "" {
+		kind = defaultScaleTargetRefKind
+	}
+
+	apiVersionSlice := strings.SplitN(apiVersion, "/", 2)
+	if len(apiVersionSlice) < 2 {
+		group = ""
+		version = apiVersionSlice[0]
+	} else {
+		group = apiVersionSlice[0]
+		version = apiVersionSlice[1]
+	}
+
+	return workloadIdentifier{
+		gvk: schema.GroupVersionKind{
+			Kind:    kind,
+			Group:   group,
+			Version: version,
+		},
+		name:      name,
+		namespace: namespace,
+	}
 }
 
 // isExternallyScaled checks if the workload matches any of the given workload identifiers.
@@ -230,6 +291,21 @@ func getWorkloadAsScaledObject(workload Workload) *scaledObject {
 	return scaledObject
 }
 
+// getWorkloadAsHorizontalPodAutoscaler tries to get the given workload as a horizontal pod autoscaler.
+func getWorkloadAsHorizontalPodAutoscaler(workload Workload) *horizontalPodAutoscaler {
+	replicaScaled, isReplicaScaled := workload.(*replicaScaledWorkload)
+	if !isReplicaScaled {
+		return nil
+	}
+
+	hpa, isHPA := replicaScaled.replicaScaledResource.(*horizontalPodAutoscaler)
+	if !isHPA {
+		return nil
+	}
+
+	return hpa
+}
+
 // isMatchingLabels check if the workload is matching any of the specified labels.
 func isMatchingLabels(workload Workload, includeLabels util.RegexList) bool {
 	if includeLabels == nil {
@@ -285,14 +361,7 @@ func isManagedByOwnerReference(workload Workload) bool {
 
 // setOriginalReplicas sets the original replicas annotation on the workload.
 func setOriginalReplicas(replicaCount values.Replicas, workload Workload) {
-	annotations := workload.GetAnnotations()
-	if annotations == nil {
-		annotations = map[string]string{}
-	}
-
-	annotations[annotationOriginalReplicas] = replicaCount.String()
-
-	workload.SetAnnotations(annotations)
+	SetOriginalReplicasValue(workload, replicaCount.String())
 }
 
 // getOriginalReplicas gets the original replicas annotation on the workload. nil is undefined.
@@ -321,6 +390,273 @@ func removeOriginalReplicas(workload Workload) {
 	workload.SetAnnotations(annotations)
 }
 
+// GetOriginalReplicasValue gets the unparsed original replicas of the workload and if they are set.
+func GetOriginalReplicasValue(workload Workload) (string, bool) {
+	originalReplicas, ok := workload.GetAnnotations()[annotationOriginalReplicas]
+	return originalReplicas, ok
+}
+
+// SetOriginalReplicasValue sets the unparsed original replicas on the workload.
+// Changes won't be made on Kubernetes until the workload is patched.
+func SetOriginalReplicasValue(workload Workload, originalReplicas string) {
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[annotationOriginalReplicas] = originalReplicas
+
+	workload.SetAnnotations(annotations)
+}
+
+// RemoveOriginalReplicasValue removes the original replicas from the workload.
+// Changes won't be made on Kubernetes until the workload is patched.
+func RemoveOriginalReplicasValue(workload Workload) {
+	removeOriginalReplicas(workload)
+}
+
+// originalStateAnnotations keep the state of scaled down workloads which is restored besides their original replicas.
+//
+//nolint:gochecknoglobals // read-only list of the state annotations
+var originalStateAnnotations = []string{
+	annotationOriginalHPAReplicas,
+	annotationOriginalKnativeScale,
+	annotationOriginalKnativeVisibility,
+	annotationOriginalAutomatedSyncPolicy,
+}
+
+// GetOriginalStateValues gets the unparsed state which is restored besides the original replicas, keyed by its annotation.
+func GetOriginalStateValues(workload Workload) map[string]string {
+	annotations := workload.GetAnnotations()
+	state := map[string]string{}
+
+	for _, annotation := range originalStateAnnotations {
+		if value, ok := annotations[annotation]; ok {
+			state[annotation] = value
+		}
+	}
+
+	return state
+}
+
+// SetOriginalStateValues sets the unparsed state which is restored besides the original replicas on the workload.
+// Changes won't be made on Kubernetes until the workload is patched.
+func SetOriginalStateValues(workload Workload, state map[string]string) {
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, annotation := range originalStateAnnotations {
+		if value, ok := state[annotation]; ok {
+			annotations[annotation] = value
+		}
+	}
+
+	workload.SetAnnotations(annotations)
+}
+
+// RemoveOriginalStateValues removes the state which is restored besides the original replicas from the workload.
+// Changes won't be made on Kubernetes until the workload is patched.
+func RemoveOriginalStateValues(workload Workload) {
+	annotations := workload.GetAnnotations()
+	for _, annotation := range originalStateAnnotations {
+		delete(annotations, annotation)
+	}
+
+	workload.SetAnnotations(annotations)
+}
+
+// GetReplicas gets the current replicas of the workload. The bool is false if the workload isn't scaled by its replicas.
+func GetReplicas(workload Workload) (values.Replicas, bool) {
+	if redirected, ok := workload.(*hpaRedirectedWorkload); ok {
+		workload = redirected.Workload
+	}
+
+	replicaScaled, ok := workload.(*replicaScaledWorkload)
+	if !ok {
+		return nil, false
+	}
+
+	replicas, err := replicaScaled.getReplicas()
+	if err != nil {
+		return nil, false
+	}
+
+	return replicas, true
+}
+
+// ScalesUpChildrenFirst checks if the children of the workload have to be scaled up before the workload itself.
+// This is the case for GitOps resources, which would revert the scaled down state of their children when they are scaled up.
+func ScalesUpChildrenFirst(workload Workload) bool {
+	suspendScaled, ok := workload.(*suspendScaledWorkload)
+	if !ok {
+		return false
+	}
+
+	switch suspendScaled.suspendScaledResource.(type) {
+	case *fluxResource, *argoCDApplication:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsScaledDown checks if the workload is in a scaled down state.
+func IsScaledDown(workload Workload) bool {
+	_, ok := workload.GetAnnotations()[annotationOriginalReplicas]
+	return ok
+}
+
+// SetLastTransition sets the last transition annotation on the workload.
+func SetLastTransition(workload Workload, transitionTime time.Time) {
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[annotationLastTransition] = transitionTime.UTC().Format(time.RFC3339)
+
+	workload.SetAnnotations(annotations)
+}
+
+// GetLastTransition gets the time the workload was last scaled up or down. The zero time is returned if it wasn't scaled yet.
+func GetLastTransition(workload Workload) (time.Time, error) {
+	lastTransitionString, ok := workload.GetAnnotations()[annotationLastTransition]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	lastTransition, err := time.Parse(time.RFC3339, lastTransitionString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse last transition annotation on workload: %w", err)
+	}
+
+	return lastTransition, nil
+}
+
+// setAppliedReplicas sets the replicas the downscaler scaled the workload down to.
+func setAppliedReplicas(replicas int32, workload Workload) {
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[annotationAppliedReplicas] = strconv.Itoa(int(replicas))
+
+	workload.SetAnnotations(annotations)
+}
+
+// getAppliedReplicas gets the replicas the downscaler scaled the workload down to and if they are set.
+func getAppliedReplicas(workload Workload) (int32, bool, error) {
+	appliedReplicasString, ok := workload.GetAnnotations()[annotationAppliedReplicas]
+	if !ok {
+		return 0, false, nil
+	}
+
+	appliedReplicas, err := strconv.ParseInt(appliedReplicasString, 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse applied replicas annotation on workload: %w", err)
+	}
+
+	return int32(appliedReplicas), true, nil
+}
+
+// removeScaledDownState removes the applied replicas and the manual override from the workload.
+func removeScaledDownState(workload Workload) {
+	annotations := workload.GetAnnotations()
+	delete(annotations, annotationAppliedReplicas)
+	delete(annotations, annotationManualOverrideSince)
+	workload.SetAnnotations(annotations)
+}
+
+// SetManualOverrideSince sets the time a manual override of the workloads replicas was first detected.
+// Changes won't be made on Kubernetes until the workload is patched.
+func SetManualOverrideSince(workload Workload, since time.Time) {
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[annotationManualOverrideSince] = since.UTC().Format(time.RFC3339)
+
+	workload.SetAnnotations(annotations)
+}
+
+// RemoveManualOverrideSince removes the time a manual override was first detected from the workload.
+// Changes won't be made on Kubernetes until the workload is patched.
+func RemoveManualOverrideSince(workload Workload) {
+	annotations := workload.GetAnnotations()
+	delete(annotations, annotationManualOverrideSince)
+	workload.SetAnnotations(annotations)
+}
+
+// GetManualOverrideSince gets the time a manual override of the workloads replicas was first detected.
+// The zero time is returned if no manual override was detected.
+func GetManualOverrideSince(workload Workload) (time.Time, error) {
+	sinceString, ok := workload.GetAnnotations()[annotationManualOverrideSince]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, sinceString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse manual override annotation on workload: %w", err)
+	}
+
+	return since, nil
+}
+
+// PatchWorkload applies all changes made to the workload since original was copied as a minimal json patch.
+// The patch is locked to the resource version of original, so the Kubernetes API rejects it with a conflict
+// if the workload was changed concurrently, instead of applying the changes on top of a state they weren't based on.
+func PatchWorkload(original, workload Workload, clientsets *Clientsets, ctx context.Context) error {
+	patch, err := original.Compare(workload)
+	if err != nil {
+		return fmt.Errorf("failed to compare workload with its original: %w", err)
+	}
+
+	if len(patch) == 0 {
+		slog.Debug("workload has no changes, skipping patch", "workload", workload.GetName(), "namespace", workload.GetNamespace())
+		return nil
+	}
+
+	patchBytes, err := json.Marshal(lockResourceVersion(patch, original.GetResourceVersion()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	err = workload.Patch(patchBytes, clientsets, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to patch workload: %w", err)
+	}
+
+	return nil
+}
+
+// lockResourceVersion prepends an operation setting the resource version the patch is based on.
+// The Kubernetes API only accepts the patched resource if its resource version still matches, otherwise it returns a conflict.
+// Without a resource version, e.g. before a resource was created, the patch is returned unchanged.
+func lockResourceVersion(patch jsondiff.Patch, resourceVersion string) jsondiff.Patch {
+	if resourceVersion == "" {
+		return patch
+	}
+
+	lock := jsondiff.Operation{Type: jsondiff.OperationReplace, Path: resourceVersionPath, Value: resourceVersion}
+
+	return append(jsondiff.Patch{lock}, patch...)
+}
+
+// getPatchOptions gets the options for patches sent through the typed clientsets.
+func getPatchOptions() metav1.PatchOptions {
+	return metav1.PatchOptions{FieldManager: FieldManager}
+}
+
+// getPatchOwner gets the option setting the field manager for patches sent through the controller runtime client.
+func getPatchOwner() ctrlclient.FieldOwner {
+	return ctrlclient.FieldOwner(FieldManager)
+}
+
 // derefInt32 safely dereference int32, if not present a default value is set instead.
 func derefInt32(p *int32, def int32) int32 {
 	if p != nil {
@@ -357,3 +693,129 @@ func unstructuredReplicasToInt32(val any) (int32, bool) {
 		return 0, false
 	}
 }
+
+// isStrimziResourceReady checks if the Strimzi operator reconciled the latest generation of the resource
+// and reports it as ready through its Ready condition.
+func isStrimziResourceReady(resource *unstructured.Unstructured) bool {
+	observedGeneration, _, err := unstructured.NestedFieldNoCopy(resource.Object, "status", "observedGeneration")
+	if err != nil {
+		return false
+	}
+
+	// the Kubernetes JSON decoder returns numbers as float64, int64 is accepted for robustness
+	switch generation := observedGeneration.(type) {
+	case int64:
+		if generation < resource.GetGeneration() {
+			return false
+		}
+	case float64:
+		if generation < float64(resource.GetGeneration()) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	conditions, _, err := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	if err != nil {
+		return false
+	}
+
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]any)
+		if !ok || conditionMap["type"] != "Ready" {
+			continue
+		}
+
+		return conditionMap["status"] == "True"
+	}
+
+	return false
+}
+
+// childReference references a child of a workload by its namespace, name and resource type.
+type childReference struct {
+	namespace    string
+	name         string
+	resourceType string
+}
+
+// managedResourceTypes maps the kinds of resources managed by GitOps tools to the resource types they are scaled as.
+//
+//nolint:gochecknoglobals // package-level lookup table for the kinds of managed resources
+var managedResourceTypes = map[schema.GroupKind]string{
+	{Group: "apps", Kind: "Deployment"}:                         "deployments",
+	{Group: "apps", Kind: "StatefulSet"}:                        "statefulsets",
+	{Group: "apps", Kind: "DaemonSet"}:                          "daemonsets",
+	{Group: "batch", Kind: "CronJob"}:                           "cronjobs",
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}:     "horizontalpodautoscalers",
+	{Group: "keda.sh", Kind: "ScaledObject"}:                    "scaledobjects",
+	{Group: "keda.sh", Kind: "ScaledJob"}:                       "scaledjobs",
+	{Group: "argoproj.io", Kind: "Rollout"}:                     "rollouts",
+	{Group: "argoproj.io", Kind: "Application"}:                 "applications",
+	{Group: "monitoring.coreos.com", Kind: "Prometheus"}:        "prometheuses",
+	{Group: fluxHelmGroup, Kind: "HelmRelease"}:                 "helmreleases",
+	{Group: fluxKustomizeGroup, Kind: "Kustomization"}:          "kustomizations",
+	{Group: "actions.github.com", Kind: "AutoscalingRunnerSet"}: "autoscalingrunnersets",
+}
+
+// getReferencedWorkloads gets the workloads of the referenced children, skipping the parent with the given uid.
+func getReferencedWorkloads(
+	children []childReference,
+	parentUID types.UID,
+	clientsets *Clientsets,
+	ctx context.Context,
+) ([]Workload, error) {
+	wanted := make(map[childReference]struct{}, len(children))
+	for _, child := range children {
+		wanted[child] = struct{}{}
+	}
+
+	listed := make(map[childReference]struct{}, len(children))
+
+	var results []Workload
+
+	for _, child := range children {
+		list := childReference{namespace: child.namespace, resourceType: child.resourceType}
+		if _, ok := listed[list]; ok {
+			continue
+		}
+
+		listed[list] = struct{}{}
+
+		workloads, err := getChildWorkloads(child.resourceType, child.namespace, clientsets, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, workload := range workloads {
+			if workload.GetUID() == parentUID {
+				continue
+			}
+
+			reference := childReference{namespace: workload.GetNamespace(), name: workload.GetName(), resourceType: child.resourceType}
+			if _, ok := wanted[reference]; ok {
+				results = append(results, workload)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// getChildWorkloads gets the workloads of the resource type in the namespace to search them for children.
+// Resource types which aren't served or which the downscaler isn't allowed to list have no children,
+// so they don't stop the children of the other resource types from being scaled.
+func getChildWorkloads(resourceType, namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+	workloads, err := GetWorkloads(resourceType, namespace, clientsets, ctx)
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			slog.Warn("failed to get children of resource type, skipping it", "resourceType", resourceType, "namespace", namespace, "error", err)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s in namespace %s: %w", resourceType, namespace, err)
+	}
+
+	return workloads, nil
+}
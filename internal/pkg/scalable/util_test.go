@@ -1,17 +1,32 @@
 package scalable
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
 	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
 	"github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 func TestFilterExcluded(t *testing.T) { //nolint: maintidx// fine to read and understand
@@ -334,6 +349,7 @@ func TestFilterExcluded(t *testing.T) { //nolint: maintidx// fine to read and un
 
 			got := FilterExcluded(
 				test.workloads,
+				nil,
 				test.includeLabels,
 				test.excludedNamespaces,
 				test.excludedWorkloads,
@@ -390,3 +406,268 @@ func TestIsExternallyScaled(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchWorkload(t *testing.T) {
+	t.Parallel()
+
+	var gotContentType, gotFieldManager string
+
+	var gotPatch []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotContentType = req.Header.Get("Content-Type")
+		gotFieldManager = req.URL.Query().Get("fieldManager")
+
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &gotPatch))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	kubernetesClientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	replicas := int32(3)
+	workload := &replicaScaledWorkload{&deployment{Deployment: &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{"owner": "team-a"},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+	}}}
+
+	original, err := workload.Copy()
+	require.NoError(t, err)
+
+	_, _, err = workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+
+	err = PatchWorkload(original, workload, &Clientsets{Kubernetes: kubernetesClientset}, t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, string(types.JSONPatchType), gotContentType)
+	assert.Equal(t, FieldManager, gotFieldManager)
+	assert.Equal(t, map[string]any{"op": "replace", "path": "/metadata/resourceVersion", "value": "1"}, gotPatch[0])
+	assert.ElementsMatch(t, []map[string]any{
+		{"op": "replace", "path": "/metadata/resourceVersion", "value": "1"},
+		{"op": "replace", "path": "/spec/replicas", "value": float64(0)},
+		{"op": "add", "path": "/metadata/annotations/downscaler~1original-replicas", "value": "3"},
+		{"op": "add", "path": "/metadata/annotations/downscaler~1applied-replicas", "value": "0"},
+	}, gotPatch)
+}
+
+func TestPatchWorkload_ConflictsWithConcurrentChange(t *testing.T) {
+	t.Parallel()
+
+	stored := newTestWidget(map[string]any{"replicas": int64(3)})
+	stored.SetResourceVersion("1")
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), stored)
+
+	// the fake client doesn't check resource versions, so the optimistic locking of the Kubernetes API is emulated
+	dynamicClient.PrependReactor("patch", "widgets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction, _ := action.(clienttesting.PatchAction)
+
+		var operations []map[string]any
+		require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &operations))
+
+		current, err := dynamicClient.Tracker().Get(testWidgetResource, "default", "test-widget")
+		require.NoError(t, err)
+
+		currentWidget, _ := current.(*unstructured.Unstructured)
+		if operations[0]["path"] == resourceVersionPath && operations[0]["value"] != currentWidget.GetResourceVersion() {
+			return true, nil, apierrors.NewConflict(testWidgetResource.GroupResource(), "test-widget", errors.New("object was modified"))
+		}
+
+		return false, nil, nil
+	})
+
+	clientsets := &Clientsets{Dynamic: dynamicClient}
+	workload := &replicaScaledWorkload{&specReplicasWorkload{
+		Unstructured: stored.DeepCopy(),
+		resource:     testWidgetResource,
+		replicasPath: []string{"spec", "replicas"},
+	}}
+
+	// the replicas are changed after the workload was fetched
+	changed := newTestWidget(map[string]any{"replicas": int64(5)})
+	changed.SetResourceVersion("2")
+	_, err := dynamicClient.Resource(testWidgetResource).Namespace("default").Update(t.Context(), changed, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	original, err := workload.Copy()
+	require.NoError(t, err)
+
+	_, _, err = workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+
+	err = PatchWorkload(original, workload, clientsets, t.Context())
+	require.Error(t, err)
+	assert.True(t, apierrors.IsConflict(err))
+
+	// the retry is based on the concurrently changed replicas
+	require.NoError(t, workload.Reget(clientsets, t.Context()))
+
+	original, err = workload.Copy()
+	require.NoError(t, err)
+
+	_, _, err = workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+
+	require.NoError(t, PatchWorkload(original, workload, clientsets, t.Context()))
+
+	patched, err := dynamicClient.Resource(testWidgetResource).Namespace("default").Get(t.Context(), "test-widget", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	replicas, _, err := unstructured.NestedInt64(patched.Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), replicas)
+	assert.Equal(t, "5", patched.GetAnnotations()[annotationOriginalReplicas])
+}
+
+func TestIsStrimziResourceReady(t *testing.T) {
+	t.Parallel()
+
+	readyCondition := map[string]any{"type": "Ready", "status": "True"}
+	notReadyCondition := map[string]any{"type": "Ready", "status": "False"}
+
+	tests := []struct {
+		name       string
+		generation int64
+		status     map[string]any
+		wantReady  bool
+	}{
+		{
+			name:       "ready",
+			generation: 2,
+			status:     map[string]any{"observedGeneration": int64(2), "conditions": []any{readyCondition}},
+			wantReady:  true,
+		},
+		{
+			name:       "ready with float64 observed generation",
+			generation: 2,
+			status:     map[string]any{"observedGeneration": float64(2), "conditions": []any{readyCondition}},
+			wantReady:  true,
+		},
+		{
+			name:       "ready condition is false",
+			generation: 2,
+			status:     map[string]any{"observedGeneration": int64(2), "conditions": []any{notReadyCondition}},
+			wantReady:  false,
+		},
+		{
+			name:       "latest generation not observed",
+			generation: 3,
+			status:     map[string]any{"observedGeneration": int64(2), "conditions": []any{readyCondition}},
+			wantReady:  false,
+		},
+		{
+			name:       "no ready condition",
+			generation: 2,
+			status:     map[string]any{"observedGeneration": int64(2), "conditions": []any{}},
+			wantReady:  false,
+		},
+		{
+			name:       "no status",
+			generation: 1,
+			status:     nil,
+			wantReady:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := &unstructured.Unstructured{Object: map[string]any{}}
+			resource.SetGeneration(test.generation)
+
+			if test.status != nil {
+				resource.Object["status"] = test.status
+			}
+
+			assert.Equal(t, test.wantReady, isStrimziResourceReady(resource))
+		})
+	}
+}
+
+func TestGetReplicas(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(3)
+	deploymentWorkload := &replicaScaledWorkload{&deployment{&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}}}
+
+	tests := []struct {
+		name         string
+		workload     Workload
+		wantReplicas values.Replicas
+		wantFound    bool
+	}{
+		{
+			name:         "replica scaled workload",
+			workload:     deploymentWorkload,
+			wantReplicas: values.AbsoluteReplicas(3),
+			wantFound:    true,
+		},
+		{
+			name:         "workload redirected to its horizontalpodautoscaler",
+			workload:     &hpaRedirectedWorkload{Workload: deploymentWorkload},
+			wantReplicas: values.AbsoluteReplicas(3),
+			wantFound:    true,
+		},
+		{
+			name:         "suspend scaled workload",
+			workload:     &suspendScaledWorkload{&cronJob{&v1.CronJob{}}},
+			wantReplicas: nil,
+			wantFound:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			replicas, found := GetReplicas(test.workload)
+			assert.Equal(t, test.wantFound, found)
+			assert.Equal(t, test.wantReplicas, replicas)
+		})
+	}
+}
+
+func TestListDynamicWorkloads(t *testing.T) {
+	t.Parallel()
+
+	newWorkload := func(item *unstructured.Unstructured) Workload {
+		return &suspendScaledWorkload{&cnpgCluster{item}}
+	}
+
+	cluster := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "postgresql.cnpg.io/v1",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": "db", "namespace": "test"},
+	}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cnpgClusterResource: "ClusterList"},
+		cluster,
+	)
+
+	workloads, err := listDynamicWorkloads(cnpgClusterResource, "test", &Clientsets{Dynamic: dynamicClient}, t.Context(), newWorkload)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "db", workloads[0].GetName())
+
+	dynamicClient.PrependReactor("list", "clusters", func(_ clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(cnpgClusterResource.GroupResource(), "")
+	})
+
+	workloads, err = listDynamicWorkloads(cnpgClusterResource, "test", &Clientsets{Dynamic: dynamicClient}, t.Context(), newWorkload)
+	require.NoError(t, err, "resources which aren't served should have no workloads")
+	assert.Empty(t, workloads)
+}
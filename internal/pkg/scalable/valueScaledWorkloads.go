@@ -14,8 +14,8 @@ import (
 // valueScaledResource provides all the functions needed to scale a resource by setting a field to a particular value.
 type valueScaledResource interface {
 	scalableResource
-	// Update updates the resource with all changes made to it. It should only be called once on a resource
-	Update(clientsets *Clientsets, ctx context.Context) error
+	// Patch applies the json patch to the resource
+	Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error
 	// setValue sets the value of the key where downscaling is performed
 	setValue(value values.Replicas) error
 	// getValue gets the current value of the key where downscaling is performed and the value used for downscaling
@@ -79,7 +79,8 @@ func (v *valueScaledWorkload) ScaleDown(_ values.Replicas) (*metrics.SavedResour
 
 		slog.Debug("workload is already scaled down, skipping", "workload", v.GetName(), "namespace", v.GetNamespace())
 
-		return metrics.NewSavedResources(0, 0), false, nil
+		// the resources are still saved while the workload stays scaled down
+		return v.getSavedResourcesRequests(), false, nil
 	}
 
 	savedResources := v.getSavedResourcesRequests()
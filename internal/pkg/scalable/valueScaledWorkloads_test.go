@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -47,6 +48,21 @@ func buildValueScaledResourceForTest(t *testing.T, kind string, initial any) (va
 	}
 }
 
+// newTestVirtualMachine builds a virtual machine with the given spec fields and domain.
+func newTestVirtualMachine(spec, domain map[string]any) *virtualMachine {
+	spec["template"] = map[string]any{"spec": map[string]any{"domain": domain}}
+
+	return &virtualMachine{&unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kubevirt.io/v1",
+		"kind":       "VirtualMachine",
+		"metadata": map[string]any{
+			"name":      "test-vm",
+			"namespace": "default",
+		},
+		"spec": spec,
+	}}}
+}
+
 // Table-driven tests for valueScaledWorkload ScaleDown behavior for services, ingresses and gateways.
 func TestValueScaledWorkload_ScaleDown(t *testing.T) {
 	t.Parallel()
@@ -161,6 +177,89 @@ func TestValueScaledWorkload_ScaleDown(t *testing.T) {
 	}
 }
 
+// Table-driven tests for ScaleDown behavior on workloads which are already scaled down by the downscaler.
+func TestValueScaledWorkload_ScaleDownAlreadyScaledDown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		newWorkload  func(t *testing.T) *valueScaledWorkload
+		wantCPU      float64
+		wantMemory   float64
+		wantOriginal values.Replicas
+	}{
+		{
+			name: "service",
+			newWorkload: func(t *testing.T) *valueScaledWorkload {
+				t.Helper()
+				resource, _ := buildValueScaledResourceForTest(t, ServiceKind, corev1.ServiceTypeClusterIP)
+
+				return &valueScaledWorkload{resource}
+			},
+			wantOriginal: values.StringReplicas(corev1.ServiceTypeLoadBalancer),
+		},
+		{
+			name: "ingress",
+			newWorkload: func(t *testing.T) *valueScaledWorkload {
+				t.Helper()
+				resource, _ := buildValueScaledResourceForTest(t, IngressKind, downscalerIngressClassConst)
+
+				return &valueScaledWorkload{resource}
+			},
+			wantOriginal: values.StringReplicas("nginx"),
+		},
+		{
+			name: "gateway",
+			newWorkload: func(t *testing.T) *valueScaledWorkload {
+				t.Helper()
+				resource, _ := buildValueScaledResourceForTest(t, GatewayKind, gatewayv1.ObjectName(downscalerGatewayClassConst))
+
+				return &valueScaledWorkload{resource}
+			},
+			wantOriginal: values.StringReplicas("nginx"),
+		},
+		{
+			name: "declared custom resource with requests",
+			newWorkload: func(t *testing.T) *valueScaledWorkload {
+				t.Helper()
+
+				return &valueScaledWorkload{&fieldValueWorkload{
+					Unstructured: newTestWidget(map[string]any{
+						"suspend":  true,
+						"requests": map[string]any{"cpu": "500m", "memory": "1Gi"},
+					}),
+					resource:       testWidgetResource,
+					valuePath:      []string{"spec", "suspend"},
+					downscaleValue: values.BooleanReplicas(true),
+					requestsPath:   ".spec.requests",
+				}}
+			},
+			wantCPU:      0.5,
+			wantMemory:   1024 * 1024 * 1024,
+			wantOriginal: values.BooleanReplicas(false),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := test.newWorkload(t)
+			setOriginalReplicas(test.wantOriginal, workload)
+
+			saved, updateNeeded, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+			require.NoError(t, err)
+			assert.False(t, updateNeeded)
+			assert.InDelta(t, test.wantCPU, saved.TotalCPU(), 0.001)
+			assert.InDelta(t, test.wantMemory, saved.TotalMemory(), 0.001)
+
+			original, err := getOriginalReplicas(workload)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantOriginal, original)
+		})
+	}
+}
+
 // Table-driven tests for ScaleUp behavior.
 func TestValueScaledWorkload_ScaleUp(t *testing.T) {
 	t.Parallel()
@@ -230,3 +329,77 @@ func TestValueScaledWorkload_ScaleUp(t *testing.T) {
 		})
 	}
 }
+
+// TestValueScaledWorkload_ScaleDownAndUp scales the value scaled resources down and back up,
+// checking that scaling up restores the exact state they had before.
+func TestValueScaledWorkload_ScaleDownAndUp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		newResource func() valueScaledResource
+		wantValue   values.Replicas
+		wantUpdated bool
+		wantCPU     float64
+		wantMemory  float64
+	}{
+		{
+			name: "virtual machine with run strategy",
+			newResource: func() valueScaledResource {
+				return newTestVirtualMachine(
+					map[string]any{"runStrategy": "RerunOnFailure"},
+					map[string]any{"resources": map[string]any{"requests": map[string]any{"cpu": "2", "memory": "4Gi"}}},
+				)
+			},
+			wantValue:   values.StringReplicas("RerunOnFailure"),
+			wantUpdated: true,
+			wantCPU:     2,
+			wantMemory:  4 * 1024 * 1024 * 1024,
+		},
+		{
+			name: "virtual machine without run strategy",
+			newResource: func() valueScaledResource {
+				return newTestVirtualMachine(map[string]any{}, map[string]any{})
+			},
+			wantValue:   values.StringReplicas(runStrategyHalted),
+			wantUpdated: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := test.newResource()
+			workload := &valueScaledWorkload{resource}
+
+			value, downscalingValue, err := resource.getValue()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantValue, value)
+
+			savedResources, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+			assert.InDelta(t, test.wantCPU, savedResources.TotalCPU(), 0.001)
+			assert.InDelta(t, test.wantMemory, savedResources.TotalMemory(), 0.001)
+
+			value, _, err = resource.getValue()
+			require.NoError(t, err)
+			assert.Equal(t, downscalingValue, value)
+
+			savedResources, updated, err = workload.ScaleDown(values.AbsoluteReplicas(0))
+			require.NoError(t, err)
+			assert.False(t, updated, "a scaled down workload shouldn't be scaled down again")
+			assert.InDelta(t, test.wantMemory, savedResources.TotalMemory(), 0.001, "a scaled down workload should still report its savings")
+
+			updated, err = workload.ScaleUp()
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUpdated, updated)
+
+			original := test.newResource()
+			clearEmptyMetadata(original)
+			clearEmptyMetadata(resource)
+			assert.Equal(t, original, resource, "scaling up should restore the state before scaling down")
+		})
+	}
+}
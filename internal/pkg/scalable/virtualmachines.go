@@ -0,0 +1,158 @@
+package scalable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/metrics"
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	runStrategyAlways = "Always"
+	runStrategyHalted = "Halted"
+)
+
+//nolint:gochecknoglobals // package-level resources required for the dynamic client
+var virtualMachineResource = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachines"}
+
+// getVirtualMachines is the getResourceFunc for KubeVirt VirtualMachines.
+func getVirtualMachines(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
+	return listDynamicWorkloads(virtualMachineResource, namespace, clientsets, ctx, func(item *unstructured.Unstructured) Workload {
+		return &valueScaledWorkload{&virtualMachine{item}}
+	})
+}
+
+// parseVirtualMachineFromBytes parses the admission review and returns the KubeVirt VirtualMachine.
+func parseVirtualMachineFromBytes(rawObject []byte) (Workload, error) {
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(rawObject, &u); err != nil {
+		return nil, fmt.Errorf("failed to decode virtualmachine: %w", err)
+	}
+
+	return &valueScaledWorkload{&virtualMachine{&u}}, nil
+}
+
+// virtualMachine is a KubeVirt VirtualMachine, which is scaled by setting its run strategy to Halted.
+// The original run strategy is kept as the original replicas, so it is restored when scaling up.
+type virtualMachine struct {
+	*unstructured.Unstructured
+}
+
+// getValue gets the current run strategy of the virtual machine and the run strategy used for downscaling.
+// The deprecated running field is translated to its run strategy, while a virtual machine without either is halted.
+//
+//nolint:nonamedreturns // required to better understand the function
+func (v *virtualMachine) getValue() (currentValue, downscalingValue values.Replicas, err error) {
+	downscalingValue = values.StringReplicas(runStrategyHalted)
+
+	runStrategy, found, err := unstructured.NestedString(v.Object, "spec", "runStrategy")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get run strategy of virtualmachine %s/%s: %w", v.GetNamespace(), v.GetName(), err)
+	}
+
+	if found {
+		return values.StringReplicas(runStrategy), downscalingValue, nil
+	}
+
+	running, _, err := unstructured.NestedBool(v.Object, "spec", "running")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get running field of virtualmachine %s/%s: %w", v.GetNamespace(), v.GetName(), err)
+	}
+
+	if running {
+		return values.StringReplicas(runStrategyAlways), downscalingValue, nil
+	}
+
+	return downscalingValue, downscalingValue, nil
+}
+
+// setValue sets the run strategy of the virtual machine, replacing the deprecated running field as both can't be set at once.
+// Changes won't be made on Kubernetes until the workload is patched.
+func (v *virtualMachine) setValue(value values.Replicas) error {
+	unstructured.RemoveNestedField(v.Object, "spec", "running")
+
+	err := unstructured.SetNestedField(v.Object, value.String(), "spec", "runStrategy")
+	if err != nil {
+		return fmt.Errorf("failed to set run strategy of virtualmachine %s/%s: %w", v.GetNamespace(), v.GetName(), err)
+	}
+
+	return nil
+}
+
+// getSavedResourcesRequests calculates the resource requests saved by halting the virtual machine.
+// They are taken from the resources of the domain of its instance, falling back to the guest memory if no memory is requested.
+func (v *virtualMachine) getSavedResourcesRequests() *metrics.SavedResources {
+	cpu, memory := getCustomResourceRequests(v.Object, "{.spec.template.spec.domain.resources.requests}")
+
+	if memory == 0 {
+		guestMemory, _, _ := unstructured.NestedFieldNoCopy(v.Object, "spec", "template", "spec", "domain", "memory", "guest")
+		memory = parseQuantity(guestMemory)
+	}
+
+	return metrics.NewSavedResources(cpu, memory)
+}
+
+// Reget regets the resource from the Kubernetes API.
+func (v *virtualMachine) Reget(clientsets *Clientsets, ctx context.Context) error {
+	fresh, err := clientsets.Dynamic.Resource(virtualMachineResource).Namespace(v.GetNamespace()).Get(ctx, v.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get virtualmachine %s/%s: %w", v.GetNamespace(), v.GetName(), err)
+	}
+
+	v.Unstructured = fresh
+
+	return nil
+}
+
+// Patch applies the json patch to the resource.
+func (v *virtualMachine) Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error {
+	patched, err := clientsets.Dynamic.Resource(virtualMachineResource).Namespace(v.GetNamespace()).
+		Patch(ctx, v.GetName(), types.JSONPatchType, patch, getPatchOptions())
+	if err != nil {
+		return fmt.Errorf("failed to patch virtualmachine %s/%s: %w", v.GetNamespace(), v.GetName(), err)
+	}
+
+	v.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// Copy creates a deep copy of the workload.
+func (v *virtualMachine) Copy() (Workload, error) {
+	if v.Object == nil {
+		return nil, newNilUnderlyingObjectError(v.GetKind())
+	}
+
+	return &valueScaledWorkload{&virtualMachine{v.DeepCopy()}}, nil
+}
+
+// Compare compares the workload with another workload and returns the differences as a jsondiff.Patch.
+func (v *virtualMachine) Compare(workloadCopy Workload) (jsondiff.Patch, error) {
+	valueScaledCopy, ok := workloadCopy.(*valueScaledWorkload)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*valueScaledWorkload)(nil), workloadCopy)
+	}
+
+	virtualMachineCopy, ok := valueScaledCopy.valueScaledResource.(*virtualMachine)
+	if !ok {
+		return nil, newExpectTypeGotTypeError((*virtualMachine)(nil), valueScaledCopy.valueScaledResource)
+	}
+
+	if v.Object == nil || virtualMachineCopy.Object == nil {
+		return nil, newNilUnderlyingObjectError(v.GetKind())
+	}
+
+	diff, err := jsondiff.Compare(v.Object, virtualMachineCopy.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare virtualmachines: %w", err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,38 @@
+package scalable
+
+import (
+	"testing"
+
+	"github.com/caas-team/gokubedownscaler/internal/pkg/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestVirtualMachine_ScaleDownAndUpRunningField(t *testing.T) {
+	t.Parallel()
+
+	vm := newTestVirtualMachine(map[string]any{"running": true}, map[string]any{"memory": map[string]any{"guest": "2Gi"}})
+	workload := &valueScaledWorkload{vm}
+
+	savedResources, updated, err := workload.ScaleDown(values.AbsoluteReplicas(0))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.InDelta(t, 2*1024*1024*1024, savedResources.TotalMemory(), 0.001, "the guest memory should be saved without requests")
+
+	runStrategy, _, err := unstructured.NestedString(vm.Object, "spec", "runStrategy")
+	require.NoError(t, err)
+	assert.Equal(t, runStrategyHalted, runStrategy)
+
+	_, found, err := unstructured.NestedFieldNoCopy(vm.Object, "spec", "running")
+	require.NoError(t, err)
+	assert.False(t, found, "the running field can't be set together with the run strategy")
+
+	updated, err = workload.ScaleUp()
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	runStrategy, _, err = unstructured.NestedString(vm.Object, "spec", "runStrategy")
+	require.NoError(t, err)
+	assert.Equal(t, runStrategyAlways, runStrategy, "the running field should be replaced by its run strategy")
+}
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapi "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
@@ -23,6 +24,8 @@ import (
 type getResourceFunc func(namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error)
 
 // GetWorkloads gets all workloads of the given resource in the cluster.
+// Resource types declared as custom resources are scaled at their path, while resource types given as "<resource>.<group>"
+// are custom resources scaled through their scale subresource.
 func GetWorkloads(resource, namespace string, clientsets *Clientsets, ctx context.Context) ([]Workload, error) {
 	resourceFuncMap := map[string]getResourceFunc{
 		"deployments":              getDeployments,
@@ -33,6 +36,7 @@ func GetWorkloads(resource, namespace string, clientsets *Clientsets, ctx contex
 		"poddisruptionbudgets":     getPodDisruptionBudgets,
 		"horizontalpodautoscalers": getHorizontalPodAutoscalers,
 		"scaledobjects":            getScaledObjects,
+		"scaledjobs":               getScaledJobs,
 		"rollouts":                 getRollouts,
 		"stacks":                   getStacks,
 		"prometheuses":             getPrometheuses,
@@ -46,11 +50,27 @@ func GetWorkloads(resource, namespace string, clientsets *Clientsets, ctx contex
 		"kafkaconnects":            getKafkaConnects,
 		"kafkamirrormaker2s":       getKafkaMirrorMaker2s,
 		"kafkabridges":             getKafkaBridges,
+		"knativeservices":          getKnativeWorkloadsFunc(knativeServiceResource),
+		"knativerevisions":         getKnativeWorkloadsFunc(knativeRevisionResource),
+		"helmreleases":             getFluxWorkloadsFunc(fluxHelmReleaseResource),
+		"kustomizations":           getFluxWorkloadsFunc(fluxKustomizationResource),
+		"applications":             getArgoCDApplications,
+		"cnpgclusters":             getCNPGClusters,
+		"virtualmachines":          getVirtualMachines,
 	}
 
 	resourceFunc, exists := resourceFuncMap[resource]
+	if customResource, isCustomResource := customResources[resource]; !exists && isCustomResource {
+		resourceFunc, exists = getCustomResourceWorkloadsFunc(customResource), true
+	}
+
 	if !exists {
-		return nil, newInvalidResourceError(resource)
+		groupResource, isScaleSubresource := getScaleSubresourceGroupResource(resource)
+		if !isScaleSubresource {
+			return nil, newInvalidResourceError(resource)
+		}
+
+		resourceFunc = getScaleSubresourceWorkloadsFunc(groupResource)
 	}
 
 	workloads, err := resourceFunc(namespace, clientsets, ctx)
@@ -61,6 +81,57 @@ func GetWorkloads(resource, namespace string, clientsets *Clientsets, ctx contex
 	return workloads, nil
 }
 
+// GetGroupResource gets the API group and resource name the given resource type is served as by the API server.
+func GetGroupResource(resource string) (schema.GroupResource, error) {
+	groupResourceMap := map[string]schema.GroupResource{
+		"deployments":              {Group: "apps", Resource: "deployments"},
+		"statefulsets":             {Group: "apps", Resource: "statefulsets"},
+		"cronjobs":                 {Group: "batch", Resource: "cronjobs"},
+		"jobs":                     {Group: "batch", Resource: "jobs"},
+		"daemonsets":               {Group: "apps", Resource: "daemonsets"},
+		"poddisruptionbudgets":     {Group: "policy", Resource: "poddisruptionbudgets"},
+		"horizontalpodautoscalers": {Group: "autoscaling", Resource: "horizontalpodautoscalers"},
+		"scaledobjects":            {Group: "keda.sh", Resource: "scaledobjects"},
+		"scaledjobs":               {Group: "keda.sh", Resource: "scaledjobs"},
+		"rollouts":                 {Group: "argoproj.io", Resource: "rollouts"},
+		"stacks":                   {Group: "zalando.org", Resource: "stacks"},
+		"prometheuses":             {Group: "monitoring.coreos.com", Resource: "prometheuses"},
+		"autoscalingrunnersets":    {Group: "actions.github.com", Resource: "autoscalingrunnersets"},
+		"services":                 {Group: "", Resource: "services"},
+		"awsnlbservices":           {Group: "", Resource: "services"},
+		"awselbservices":           {Group: "", Resource: "services"},
+		"ingresses":                {Group: "networking.k8s.io", Resource: "ingresses"},
+		"gateways":                 {Group: "gateway.networking.k8s.io", Resource: "gateways"},
+		"postgresqls":              {Group: "acid.zalan.do", Resource: "postgresqls"},
+		"kafkaconnects":            {Group: kafkaStrimziGroup, Resource: "kafkaconnects"},
+		"kafkamirrormaker2s":       {Group: kafkaStrimziGroup, Resource: "kafkamirrormaker2s"},
+		"kafkabridges":             {Group: kafkaStrimziGroup, Resource: "kafkabridges"},
+		"knativeservices":          knativeServiceResource.GroupResource(),
+		"knativerevisions":         knativeRevisionResource.GroupResource(),
+		"helmreleases":             fluxHelmReleaseResource.GroupResource(),
+		"kustomizations":           fluxKustomizationResource.GroupResource(),
+		"applications":             argoCDApplicationResource.GroupResource(),
+		"cnpgclusters":             cnpgClusterResource.GroupResource(),
+		"virtualmachines":          virtualMachineResource.GroupResource(),
+	}
+
+	groupResource, exists := groupResourceMap[resource]
+	if exists {
+		return groupResource, nil
+	}
+
+	if customResource, isCustomResource := customResources[resource]; isCustomResource {
+		return customResource.groupVersionResource().GroupResource(), nil
+	}
+
+	groupResource, exists = getScaleSubresourceGroupResource(resource)
+	if !exists {
+		return schema.GroupResource{}, newInvalidResourceError(resource)
+	}
+
+	return groupResource, nil
+}
+
 // parseWorkloadFunc is a function that parses a specific admission review as a Workload.
 type parseWorkloadFunc func(rawObject []byte) (Workload, error)
 
@@ -77,6 +148,7 @@ func ParseWorkloadFromRawObject(resource string, rawObject []byte) (Workload, er
 		"poddisruptionbudget":     parsePodDisruptionBudgetFromBytes,
 		"horizontalpodautoscaler": parseHorizontalPodAutoscalerFromBytes,
 		"scaledobject":            parseScaledObjectFromBytes,
+		"scaledjob":               parseScaledJobFromBytes,
 		"rollout":                 parseRolloutFromBytes,
 		"stack":                   parseStackFromBytes,
 		"prometheus":              parsePrometheusFromBytes,
@@ -90,6 +162,10 @@ func ParseWorkloadFromRawObject(resource string, rawObject []byte) (Workload, er
 		"kafkaconnect":            parseKafkaConnectFromBytes,
 		"kafkamirrormaker2":       parseKafkaMirrorMaker2FromBytes,
 		"kafkabridge":             parseKafkaBridgeFromBytes,
+		"helmrelease":             parseHelmReleaseFromBytes,
+		"kustomization":           parseKustomizationFromBytes,
+		"application":             parseArgoCDApplicationFromBytes,
+		"virtualmachine":          parseVirtualMachineFromBytes,
 	}
 
 	parseFunc, exists := parseWorkloadFuncMap[resource]
@@ -113,6 +189,34 @@ type PercentageWorkload interface {
 	AllowPercentageReplicas() bool
 }
 
+// ReadyWorkload is a workload which can report if it is ready after being scaled up.
+type ReadyWorkload interface {
+	IsReady() bool
+}
+
+// CanReportReadiness checks if the resource behind the workload can report its readiness.
+// The wrappers always implement ReadyWorkload, so the wrapped resource has to be checked instead.
+func CanReportReadiness(workload Workload) bool {
+	switch wrapper := workload.(type) {
+	case *replicaScaledWorkload:
+		_, ok := wrapper.replicaScaledResource.(ReadyWorkload)
+		return ok
+	case *suspendScaledWorkload:
+		_, ok := wrapper.suspendScaledResource.(ReadyWorkload)
+		return ok
+	case *hpaRedirectedWorkload:
+		return CanReportReadiness(wrapper.Workload)
+	default:
+		_, ok := workload.(ReadyWorkload)
+		return ok
+	}
+}
+
+// OverridableWorkload is a workload which can detect if its replicas were changed manually while it was scaled down.
+type OverridableWorkload interface {
+	IsManuallyOverridden() (bool, error)
+}
+
 // scalableResource provides all functions needed to scale any type of resource.
 type scalableResource interface {
 	// GetAnnotations gets the annotations of the resource
@@ -127,12 +231,16 @@ type scalableResource interface {
 	GetLabels() map[string]string
 	// GetCreationTimestamp gets the creation timestamp of the workload
 	GetCreationTimestamp() metav1.Time
-	// SetAnnotations sets the annotations on the resource. Changes won't be made on Kubernetes until update() is called
+	// SetAnnotations sets the annotations on the resource. Changes won't be made on Kubernetes until the workload is patched
 	SetAnnotations(annotations map[string]string)
 	// GroupVersionKind gets the group version kind of the workload
 	GroupVersionKind() schema.GroupVersionKind
 	// GetOwnerReferences gets the owner references of the workload
 	GetOwnerReferences() []metav1.OwnerReference
+	// GetResourceVersion gets the resource version of the workload
+	GetResourceVersion() string
+	// SetResourceVersion sets the resource version of the workload. Patches of the workload are locked to it
+	SetResourceVersion(version string)
 	// Reget regets the workload to ensure the latest state
 	Reget(clientsets *Clientsets, ctx context.Context) error
 }
@@ -140,8 +248,8 @@ type scalableResource interface {
 // Workload provides all functions needed to scale the workload.
 type Workload interface {
 	scalableResource
-	// Update updates the resource with all changes made to it. It should only be called once on a resource
-	Update(clientsets *Clientsets, ctx context.Context) error
+	// Patch applies the json patch to the resource and takes over the resource version of the patched resource
+	Patch(patch []byte, clientsets *Clientsets, ctx context.Context) error
 	// ScaleUp scales up the workload
 	ScaleUp() (bool, error)
 	// ScaleDown scales down the workload
@@ -160,4 +268,5 @@ type Clientsets struct {
 	Monitoring *monitoring.Clientset
 	Gateway    *gatewayapi.Clientset
 	Client     ctrlclient.Client
+	Dynamic    dynamic.Interface
 }
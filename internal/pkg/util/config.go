@@ -6,6 +6,13 @@ import (
 	"regexp"
 )
 
+const (
+	// defaultAuditFileMaxSize is the default size in megabytes at which the audit file is rotated.
+	defaultAuditFileMaxSize = 100
+	// defaultAuditFileMaxBackups is the default amount of rotated audit files which are kept.
+	defaultAuditFileMaxBackups = 3
+)
+
 // CommonRuntimeConfiguration contains fields shared among different runtime configurations.
 type CommonRuntimeConfiguration struct {
 	// DryRun sets if the downscaler should take actions or just print them out.
@@ -34,21 +41,32 @@ type CommonRuntimeConfiguration struct {
 	Burst int
 	// Kubeconfig sets an optional kubeconfig to use for testing purposes instead of the in-cluster config.
 	Kubeconfig string
+	// AuditSink sets where records of all scaling decisions are written to.
+	AuditSink string
+	// AuditFileMaxSize sets the size in megabytes at which the audit file is rotated.
+	AuditFileMaxSize int
+	// AuditFileMaxBackups sets how many rotated audit files are kept.
+	AuditFileMaxBackups int
+	// CustomResourcesConfig sets the file declaring custom resources which are scaled at a JSONPath.
+	CustomResourcesConfig string
 }
 
 func GetDefaultConfig() *CommonRuntimeConfiguration {
 	return &CommonRuntimeConfiguration{
-		DryRun:            false,
-		Debug:             false,
-		IncludeNamespaces: nil,
-		IncludeResources:  []string{"deployments"},
-		ExcludeNamespaces: RegexList{regexp.MustCompile("kube-system"), regexp.MustCompile("kube-downscaler")},
-		ExcludeWorkloads:  nil,
-		IncludeLabels:     nil,
-		TimeAnnotation:    "",
-		Kubeconfig:        "",
-		MetricsEnabled:    false,
-		JsonLogs:          false,
+		DryRun:              false,
+		Debug:               false,
+		IncludeNamespaces:   nil,
+		IncludeResources:    []string{"deployments"},
+		ExcludeNamespaces:   RegexList{regexp.MustCompile("kube-system"), regexp.MustCompile("kube-downscaler")},
+		ExcludeWorkloads:    nil,
+		IncludeLabels:       nil,
+		TimeAnnotation:      "",
+		Kubeconfig:          "",
+		MetricsEnabled:      false,
+		JsonLogs:            false,
+		AuditSink:           "",
+		AuditFileMaxSize:    defaultAuditFileMaxSize,
+		AuditFileMaxBackups: defaultAuditFileMaxBackups,
 	}
 }
 
@@ -126,6 +144,30 @@ func (c *CommonRuntimeConfiguration) ParseCommonFlags() {
 		"",
 		"kubeconfig to use instead of the in-cluster config (optional)",
 	)
+	flag.StringVar(
+		&c.AuditSink,
+		"audit-sink",
+		"",
+		`where to write records of all scaling decisions to. either "stdout", "file:<path>" or a http(s) webhook url (default: disabled)`,
+	)
+	flag.IntVar(
+		&c.AuditFileMaxSize,
+		"audit-file-max-size",
+		defaultAuditFileMaxSize,
+		"size in megabytes at which the audit file is rotated. 0 disables the rotation (default: 100)",
+	)
+	flag.IntVar(
+		&c.AuditFileMaxBackups,
+		"audit-file-max-backups",
+		defaultAuditFileMaxBackups,
+		"how many rotated audit files are kept (default: 3)",
+	)
+	flag.StringVar(
+		&c.CustomResourcesConfig,
+		"custom-resources-config",
+		"",
+		"yaml file declaring custom resources which are scaled by setting the field at a JSONPath (optional)",
+	)
 }
 
 func (c *CommonRuntimeConfiguration) ParseConfigEnvVars() error {
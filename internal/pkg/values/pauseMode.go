@@ -0,0 +1,62 @@
+package values
+
+const annotationPause = "downscaler/pause"
+
+// PauseMode represents how the downscaler is paused at runtime.
+type PauseMode string
+
+const (
+	// PauseModeNone means the downscaler isn't paused.
+	PauseModeNone PauseMode = ""
+	// PauseModeFreeze means no workloads are changed.
+	PauseModeFreeze PauseMode = "freeze"
+	// PauseModeUpscaleAll means all workloads are scaled back up and none are scaled down.
+	PauseModeUpscaleAll PauseMode = "upscale-all"
+)
+
+// PauseModes are all pause modes which actually pause the downscaler.
+var PauseModes = []PauseMode{PauseModeFreeze, PauseModeUpscaleAll}
+
+// ParsePauseMode parses the pause mode. An empty string or "none" means the downscaler isn't paused.
+func ParsePauseMode(value string) (PauseMode, error) {
+	switch PauseMode(value) {
+	case PauseModeNone, "none":
+		return PauseModeNone, nil
+	case PauseModeFreeze:
+		return PauseModeFreeze, nil
+	case PauseModeUpscaleAll:
+		return PauseModeUpscaleAll, nil
+	default:
+		return PauseModeNone, newInvalidValueError(`pause mode has to be either "freeze", "upscale-all" or "none"`, value)
+	}
+}
+
+// GetPauseModeFromAnnotations gets the pause mode from the annotations of the downscaler's namespace.
+func GetPauseModeFromAnnotations(annotations map[string]string) (PauseMode, error) {
+	return ParsePauseMode(annotations[annotationPause])
+}
+
+// GetPauseAnnotations gets the annotations which pause the downscaler with the given mode.
+// A nil value removes the annotation when used in a merge patch.
+func GetPauseAnnotations(mode PauseMode) map[string]*string {
+	if mode == PauseModeNone {
+		return map[string]*string{annotationPause: nil}
+	}
+
+	value := string(mode)
+
+	return map[string]*string{annotationPause: &value}
+}
+
+// IsPaused checks if the pause mode pauses the downscaler.
+func (p PauseMode) IsPaused() bool {
+	return p != PauseModeNone
+}
+
+func (p PauseMode) String() string {
+	if p == PauseModeNone {
+		return "none"
+	}
+
+	return string(p)
+}
@@ -0,0 +1,69 @@
+package values
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePauseMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		want      PauseMode
+		expectErr bool
+	}{
+		{
+			name:  "not paused",
+			input: "",
+			want:  PauseModeNone,
+		},
+		{
+			name:  "explicitly not paused",
+			input: "none",
+			want:  PauseModeNone,
+		},
+		{
+			name:  "freeze",
+			input: "freeze",
+			want:  PauseModeFreeze,
+		},
+		{
+			name:  "upscale all",
+			input: "upscale-all",
+			want:  PauseModeUpscaleAll,
+		},
+		{
+			name:      "invalid mode",
+			input:     "stop",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePauseMode(test.input)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestGetPauseAnnotations(t *testing.T) {
+	t.Parallel()
+
+	mode, err := GetPauseModeFromAnnotations(map[string]string{annotationPause: *GetPauseAnnotations(PauseModeFreeze)[annotationPause]})
+	require.NoError(t, err)
+	require.Equal(t, PauseModeFreeze, mode)
+
+	require.Nil(t, GetPauseAnnotations(PauseModeNone)[annotationPause])
+}
@@ -3,12 +3,17 @@ package values
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/caas-team/gokubedownscaler/internal/pkg/util"
 )
 
+// nextScalingChangeHorizon is how far into the future the next scaling change is searched for.
+// It covers a full week, so the boundaries of all relative timespans are included.
+const nextScalingChangeHorizon = 8 * 24 * time.Hour
+
 // Scaling is an enum that describes the current Scaling.
 type Scaling int
 
@@ -43,11 +48,24 @@ func (s ScopeID) String() string {
 	}[s]
 }
 
+// Name gets the name of the scope as it is shown to users, e.g. "workload" or "namespace".
+func (s ScopeID) Name() string {
+	return map[ScopeID]string{
+		ScopeWorkload:    "workload",
+		ScopeNamespace:   "namespace",
+		ScopeCli:         "cli",
+		ScopeEnvironment: "env",
+		ScopeDefault:     "default",
+	}[s]
+}
+
 // NewScope gets a new scope with all values in an unset state.
 func NewScope() *Scope {
 	return &Scope{
 		DownscaleReplicas: nil,
 		GracePeriod:       util.Undefined,
+		MinStateDuration:  util.Undefined,
+		ManualOverride:    util.Undefined,
 	}
 }
 
@@ -63,6 +81,11 @@ type Scope struct {
 	ForceDowntime     timeSpans       // force workload into a downtime state when in one of the timespans
 	DownscaleReplicas Replicas        // the replicas to scale down to
 	GracePeriod       time.Duration   // grace period until new workloads will be scaled down
+	MinStateDuration  time.Duration   // minimum time a workload stays in a scaled state before it is scaled again
+	ManualOverride    time.Duration   // how long manual changes to the replicas of a scaled down workload are respected
+	ManualScaling     Scaling         // scaling requested manually, e.g. through the admin api
+	ManualScalingEnd  *time.Time      // until when the manual scaling is active
+	AcknowledgedUntil *time.Time      // until when mass downscaling is acknowledged and not held back by the circuit breaker
 	ScaleChildren     triStateBool    // ownerReference will immediately trigger scaling of children workloads, when applicable
 	UpscaleExcluded   triStateBool    // excluded workloads will be upscaled
 	DefaultTimezone   *time.Location  // default timezone to use when not specified in a timespan, defaults to nil
@@ -81,6 +104,8 @@ func GetDefaultScope() *Scope {
 		ForceDowntime:     nil,
 		DownscaleReplicas: AbsoluteReplicas(0),
 		GracePeriod:       15 * time.Minute,
+		MinStateDuration:  0,
+		ManualOverride:    0,
 		ScaleChildren:     triStateBool{isSet: false, value: false},
 		UpscaleExcluded:   triStateBool{isSet: false, value: false},
 		DefaultTimezone:   nil,
@@ -104,10 +129,10 @@ func (s *Scope) CheckForIncompatibleFields() error {
 }
 
 // getCurrentScaling gets the current scaling, not checking for incompatibility.
-func (s *Scope) getCurrentScaling(scopes Scopes) Scaling {
+func (s *Scope) getCurrentScaling(scopes Scopes, at time.Time) Scaling {
 	// check times
 	if s.DownTime != nil {
-		inTimeSpans, err := s.DownTime.inTimeSpans(scopes)
+		inTimeSpans, err := s.DownTime.inTimeSpans(scopes, at)
 		if err != nil {
 			return ScalingIncomplete
 		}
@@ -120,7 +145,7 @@ func (s *Scope) getCurrentScaling(scopes Scopes) Scaling {
 	}
 
 	if s.UpTime != nil {
-		inTimeSpans, err := s.UpTime.inTimeSpans(scopes)
+		inTimeSpans, err := s.UpTime.inTimeSpans(scopes, at)
 		if err != nil {
 			return ScalingIncomplete
 		}
@@ -134,19 +159,34 @@ func (s *Scope) getCurrentScaling(scopes Scopes) Scaling {
 
 	// check periods
 	if s.DownscalePeriod != nil || s.UpscalePeriod != nil {
-		return s.getScalingFromPeriods(scopes)
+		return s.getScalingFromPeriods(scopes, at)
 	}
 
 	return ScalingNone
 }
 
-func (s *Scope) getScalingFromPeriods(scopes Scopes) Scaling {
-	inDowntime, errInDowntime := s.DownscalePeriod.inTimeSpans(scopes)
+// getBoundaries gets all times within (from, to] at which one of the timespans or the manual scaling of the scope begins or ends.
+func (s *Scope) getBoundaries(scopes Scopes, from, to time.Time) []time.Time {
+	var boundaries []time.Time
+
+	for _, spans := range []timeSpans{s.DownTime, s.UpTime, s.DownscalePeriod, s.UpscalePeriod, s.ForceDowntime, s.ForceUptime} {
+		boundaries = append(boundaries, spans.getBoundaries(scopes, from, to)...)
+	}
+
+	if s.ManualScalingEnd != nil && isInInterval(*s.ManualScalingEnd, from, to) {
+		boundaries = append(boundaries, *s.ManualScalingEnd)
+	}
+
+	return boundaries
+}
+
+func (s *Scope) getScalingFromPeriods(scopes Scopes, at time.Time) Scaling {
+	inDowntime, errInDowntime := s.DownscalePeriod.inTimeSpans(scopes, at)
 	if errInDowntime != nil {
 		return ScalingIncomplete
 	}
 
-	inUptime, errInUptime := s.UpscalePeriod.inTimeSpans(scopes)
+	inUptime, errInUptime := s.UpscalePeriod.inTimeSpans(scopes, at)
 	if errInUptime != nil {
 		return ScalingIncomplete
 	}
@@ -166,13 +206,22 @@ func (s *Scope) getScalingFromPeriods(scopes Scopes) Scaling {
 	return ScalingIgnore
 }
 
-func (s *Scope) getForceScaling(scopes Scopes) Scaling {
-	forceDowntime, errForceDowntime := s.ForceDowntime.inTimeSpans(scopes)
+// getManualScaling gets the manually requested scaling if it is still active.
+func (s *Scope) getManualScaling(at time.Time) Scaling {
+	if s.ManualScalingEnd == nil || !s.ManualScalingEnd.After(at) {
+		return ScalingNone
+	}
+
+	return s.ManualScaling
+}
+
+func (s *Scope) getForceScaling(scopes Scopes, at time.Time) Scaling {
+	forceDowntime, errForceDowntime := s.ForceDowntime.inTimeSpans(scopes, at)
 	if errForceDowntime != nil {
 		return ScalingIncomplete
 	}
 
-	forceUptime, errForceUptime := s.ForceUptime.inTimeSpans(scopes)
+	forceUptime, errForceUptime := s.ForceUptime.inTimeSpans(scopes, at)
 	if errForceUptime != nil {
 		return ScalingIncomplete
 	}
@@ -247,32 +296,148 @@ func (s Scopes) GetDefaultWeekdayTo() *time.Weekday {
 
 // GetCurrentScaling gets the current scaling of the first scope that implements scaling.
 func (s Scopes) GetCurrentScaling() Scaling {
-	var result Scaling
+	return s.getScalingAt(time.Now())
+}
+
+// HasScalingChangedWithin checks if the scaling of the scopes changed within the window, e.g. because a downtime began.
+func (s Scopes) HasScalingChangedWithin(window time.Duration) bool {
+	now := time.Now()
+	return s.getScalingAt(now.Add(-window)) != s.getScalingAt(now)
+}
+
+// GetScalingScope gets the scope which decides the current scaling. The bool is false if no scope sets the scaling.
+func (s Scopes) GetScalingScope() (ScopeID, bool) {
+	_, scopeID, found := s.getScalingAndScopeAt(time.Now())
+	return scopeID, found
+}
+
+// GetNextScalingChange gets the next time the scaling of the scopes changes, e.g. because a downtime begins or ends.
+// Only changes within the next week are searched for, the bool is false if there is none.
+func (s Scopes) GetNextScalingChange() (time.Time, bool) {
+	now := time.Now()
+	current := s.getScalingAt(now)
 
+	var boundaries []time.Time
 	for _, scope := range s {
-		forcedScaling := scope.getForceScaling(s)
+		boundaries = append(boundaries, scope.getBoundaries(s, now, now.Add(nextScalingChangeHorizon))...)
+	}
+
+	slices.SortFunc(boundaries, time.Time.Compare)
+
+	for _, boundary := range boundaries {
+		if s.getScalingAt(boundary) != current {
+			return boundary, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// getScalingAt gets the scaling of the scopes at the given time.
+func (s Scopes) getScalingAt(at time.Time) Scaling {
+	scaling, _, _ := s.getScalingAndScopeAt(at)
+	return scaling
+}
+
+// getScalingAndScopeAt gets the scaling of the scopes at the given time and the scope deciding it.
+// The bool is false if no scope sets the scaling.
+func (s Scopes) getScalingAndScopeAt(at time.Time) (Scaling, ScopeID, bool) {
+	var result Scaling
+
+	var resultScope ScopeID
+
+	var found bool
+
+	for i, scope := range s {
+		manualScaling := scope.getManualScaling(at)
+		if manualScaling != ScalingNone {
+			return manualScaling, ScopeID(i), true
+		}
+	}
+
+	for i, scope := range s {
+		forcedScaling := scope.getForceScaling(s, at)
 		if forcedScaling == ScalingNone {
 			continue // scope doesnt implement forced scaling; falling through
 		}
 
 		if forcedScaling == ScalingIgnore {
 			result = ScalingIgnore // default to ScalingIgnore instead of ScalingNone for correct log message
-			break                  // break out since forced scaling is set, but just inactive
+			resultScope, found = ScopeID(i), true
+
+			break // break out since forced scaling is set, but just inactive
 		}
 
-		return forcedScaling
+		return forcedScaling, ScopeID(i), true
 	}
 
-	for _, scope := range s {
-		scopeScaling := scope.getCurrentScaling(s)
+	for i, scope := range s {
+		scopeScaling := scope.getCurrentScaling(s, at)
 		if scopeScaling == ScalingNone {
 			continue // scope doesnt implement scaling; falling through
 		}
 
-		return scopeScaling
+		return scopeScaling, ScopeID(i), true
+	}
+
+	return result, resultScope, found
+}
+
+// IsScalingForced checks if the current scaling is set manually or by force-uptime or force-downtime.
+func (s Scopes) IsScalingForced() bool {
+	for _, scope := range s {
+		if scope.getManualScaling(time.Now()) != ScalingNone {
+			return true
+		}
+	}
+
+	for _, scope := range s {
+		forcedScaling := scope.getForceScaling(s, time.Now())
+		if forcedScaling == ScalingNone {
+			continue
+		}
+
+		return forcedScaling == ScalingUp || forcedScaling == ScalingDown
+	}
+
+	return false
+}
+
+// IsMassDownscalingAcknowledged checks if any scope acknowledges mass downscaling at the moment.
+func (s Scopes) IsMassDownscalingAcknowledged() bool {
+	for _, scope := range s {
+		if scope.AcknowledgedUntil != nil && scope.AcknowledgedUntil.After(time.Now()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMinStateDuration gets the minimum state duration of the first scope that implements it.
+func (s Scopes) GetMinStateDuration() time.Duration {
+	for _, scope := range s {
+		if scope.MinStateDuration == util.Undefined {
+			continue
+		}
+
+		return scope.MinStateDuration
+	}
+
+	return 0
+}
+
+// GetManualOverrideDuration gets the manual override duration of the first scope that implements it.
+func (s Scopes) GetManualOverrideDuration() time.Duration {
+	for _, scope := range s {
+		if scope.ManualOverride == util.Undefined {
+			continue
+		}
+
+		return scope.ManualOverride
 	}
 
-	return result
+	return 0
 }
 
 // GetDownscaleReplicas gets the downscale replicas of the first scope that implements downscale replicas.
@@ -307,7 +472,7 @@ func (s Scopes) GetExcluded(scopes Scopes) bool {
 			continue
 		}
 
-		exclude, err := scope.Exclude.inTimeSpans(scopes)
+		exclude, err := scope.Exclude.inTimeSpans(scopes, time.Now())
 		if err != nil {
 			return false
 		}
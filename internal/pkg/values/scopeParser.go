@@ -22,6 +22,11 @@ const (
 	annotationForceDowntime     = "downscaler/force-downtime"
 	annotationDownscaleReplicas = "downscaler/downscale-replicas"
 	annotationGracePeriod       = "downscaler/grace-period"
+	annotationMinStateDuration  = "downscaler/min-state-duration"
+	annotationManualOverride    = "downscaler/manual-override-duration"
+	annotationManualScaling     = "downscaler/manual-scaling"
+	annotationManualUntil       = "downscaler/manual-scaling-until"
+	annotationAcknowledgeUntil  = "downscaler/acknowledge-downscaling-until"
 	annotationScaleChildren     = "downscaler/scale-children"
 	annotationExclusionUpscale  = "downscaler/upscale-excluded"
 
@@ -86,6 +91,16 @@ func (s *Scope) ParseScopeFlags() {
 		"grace-period",
 		"the grace period between creation of workload until first downscale (default: 15min)",
 	)
+	flag.Var(
+		(*util.DurationValue)(&s.MinStateDuration),
+		"min-state-duration",
+		"the minimum time a workload stays scaled up or down before it is scaled again, unless forced (default: 0)",
+	)
+	flag.Var(
+		(*util.DurationValue)(&s.ManualOverride),
+		"manual-override-duration",
+		"how long manual changes to the replicas of a scaled down workload are respected before it is scaled down again (default: disabled)",
+	)
 	flag.Var(
 		&s.ScaleChildren,
 		"scale-children",
@@ -253,6 +268,50 @@ func (s *Scope) GetScopeFromAnnotations( //nolint: funlen,gocognit,cyclop,gocycl
 		}
 	}
 
+	if minStateDuration, ok := annotations[annotationMinStateDuration]; ok {
+		err = (*util.DurationValue)(&s.MinStateDuration).Set(minStateDuration)
+		if err != nil {
+			err = fmt.Errorf("failed to parse %q annotation: %w", annotationMinStateDuration, err)
+			logEvent.ErrorInvalidAnnotation(annotationMinStateDuration, err.Error(), ctx)
+
+			return err
+		}
+	}
+
+	if manualOverride, ok := annotations[annotationManualOverride]; ok {
+		err = (*util.DurationValue)(&s.ManualOverride).Set(manualOverride)
+		if err != nil {
+			err = fmt.Errorf("failed to parse %q annotation: %w", annotationManualOverride, err)
+			logEvent.ErrorInvalidAnnotation(annotationManualOverride, err.Error(), ctx)
+
+			return err
+		}
+	}
+
+	if manualScaling, ok := annotations[annotationManualScaling]; ok {
+		err = s.setManualScalingFromAnnotations(manualScaling, annotations[annotationManualUntil])
+		if err != nil {
+			err = fmt.Errorf("failed to parse %q annotation: %w", annotationManualScaling, err)
+			logEvent.ErrorInvalidAnnotation(annotationManualScaling, err.Error(), ctx)
+
+			return err
+		}
+	}
+
+	if acknowledgedUntil, ok := annotations[annotationAcknowledgeUntil]; ok {
+		var until time.Time
+
+		until, err = time.Parse(time.RFC3339, acknowledgedUntil)
+		if err != nil {
+			err = fmt.Errorf("failed to parse %q annotation: %w", annotationAcknowledgeUntil, err)
+			logEvent.ErrorInvalidAnnotation(annotationAcknowledgeUntil, err.Error(), ctx)
+
+			return err
+		}
+
+		s.AcknowledgedUntil = &until
+	}
+
 	if scaleChildrenString, ok := annotations[annotationScaleChildren]; ok {
 		err = s.ScaleChildren.Set(scaleChildrenString)
 		if err != nil {
@@ -281,6 +340,63 @@ func (s *Scope) GetScopeFromAnnotations( //nolint: funlen,gocognit,cyclop,gocycl
 	return nil
 }
 
+// setManualScalingFromAnnotations sets the manual scaling from the values of its annotations.
+func (s *Scope) setManualScalingFromAnnotations(manualScaling, manualScalingUntil string) error {
+	switch manualScaling {
+	case "up":
+		s.ManualScaling = ScalingUp
+	case "down":
+		s.ManualScaling = ScalingDown
+	default:
+		return newInvalidValueError("manual scaling has to be either up or down", manualScaling)
+	}
+
+	until, err := time.Parse(time.RFC3339, manualScalingUntil)
+	if err != nil {
+		return fmt.Errorf("failed to parse %q annotation: %w", annotationManualUntil, err)
+	}
+
+	s.ManualScalingEnd = &until
+
+	return nil
+}
+
+// GetManualScalingAnnotations gets the annotations which set the manual scaling until the given time.
+func GetManualScalingAnnotations(scaling Scaling, until time.Time) map[string]string {
+	manualScaling := "down"
+	if scaling == ScalingUp {
+		manualScaling = "up"
+	}
+
+	return map[string]string{
+		annotationManualScaling: manualScaling,
+		annotationManualUntil:   until.UTC().Format(time.RFC3339),
+	}
+}
+
+// GetAcknowledgeAnnotations gets the annotations which acknowledge mass downscaling until the given time.
+func GetAcknowledgeAnnotations(until time.Time) map[string]string {
+	return map[string]string{
+		annotationAcknowledgeUntil: until.UTC().Format(time.RFC3339),
+	}
+}
+
+// GetAcknowledgedUntilFromAnnotations gets until when mass downscaling is acknowledged from the annotations of the downscaler's namespace.
+// The zero time is returned if mass downscaling isn't acknowledged.
+func GetAcknowledgedUntilFromAnnotations(annotations map[string]string) (time.Time, error) {
+	acknowledgedUntil, ok := annotations[annotationAcknowledgeUntil]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	until, err := time.Parse(time.RFC3339, acknowledgedUntil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %q annotation: %w", annotationAcknowledgeUntil, err)
+	}
+
+	return until, nil
+}
+
 //nolint:nonamedreturns //required for function clarity
 func InitScopes() (scopeDefault, scopeCli, scopeEnv *Scope) {
 	scopeDefault = GetDefaultScope()
@@ -3,6 +3,7 @@ package values
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -132,7 +133,7 @@ func TestScopeGetScopeFromEnv_DefaultTimezoneAppliesToDowntimeWithoutTimezone(t
 				GetDefaultScope(),
 			}
 
-			_, err = scopeEnv.DownTime.inTimeSpans(scopes)
+			_, err = scopeEnv.DownTime.inTimeSpans(scopes, time.Now())
 
 			if test.wantErr {
 				require.Error(t, err)
@@ -192,7 +193,7 @@ func TestScopeGetScopeFromEnv_DefaultWeekFrameAppliesToDowntimeWithoutWeekdays(t
 				GetDefaultScope(),
 			}
 
-			_, err = scopeEnv.DownTime.inTimeSpans(scopes)
+			_, err = scopeEnv.DownTime.inTimeSpans(scopes, time.Now())
 
 			if test.wantErr {
 				require.Error(t, err)
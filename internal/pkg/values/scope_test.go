@@ -228,7 +228,7 @@ func TestScope_getCurrentScaling(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			scaling := test.scope.getCurrentScaling(test.scopes)
+			scaling := test.scope.getCurrentScaling(test.scopes, time.Now())
 			assert.Equal(t, test.wantScaling, scaling)
 		})
 	}
@@ -377,7 +377,7 @@ func TestScope_getForceScaling(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			scaling := test.scope.getForceScaling(test.scopes)
+			scaling := test.scope.getForceScaling(test.scopes, time.Now())
 			assert.Equal(t, test.wantScaling, scaling)
 		})
 	}
@@ -424,6 +424,28 @@ func TestScopes_GetCurrentScaling(t *testing.T) {
 			},
 			wantScaling: ScalingDown,
 		},
+		{
+			name: "manual scaling overrides forced scaling",
+			scopes: Scopes{
+				&Scope{ForceDowntime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{ManualScaling: ScalingUp, ManualScalingEnd: ptr(time.Now().Add(time.Hour))},
+				&Scope{},
+				&Scope{},
+				&Scope{},
+			},
+			wantScaling: ScalingUp,
+		},
+		{
+			name: "expired manual scaling is ignored",
+			scopes: Scopes{
+				&Scope{ManualScaling: ScalingUp, ManualScalingEnd: ptr(time.Now().Add(-time.Hour))},
+				&Scope{},
+				&Scope{},
+				&Scope{DownTime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{},
+			},
+			wantScaling: ScalingDown,
+		},
 		{
 			name: "none set",
 			scopes: Scopes{
@@ -458,6 +480,317 @@ func TestScopes_GetCurrentScaling(t *testing.T) {
 	}
 }
 
+func TestScopes_IsScalingForced(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		scopes     Scopes
+		wantForced bool
+	}{
+		{
+			name: "active force downtime",
+			scopes: Scopes{
+				&Scope{},
+				&Scope{ForceDowntime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{},
+				&Scope{UpTime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{},
+			},
+			wantForced: true,
+		},
+		{
+			name: "inactive force uptime",
+			scopes: Scopes{
+				&Scope{ForceUptime: timeSpans{booleanTimeSpan(false)}},
+				&Scope{},
+				&Scope{ForceDowntime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{},
+				&Scope{},
+			},
+			wantForced: false,
+		},
+		{
+			name: "none set",
+			scopes: Scopes{
+				&Scope{},
+				&Scope{},
+				&Scope{},
+				&Scope{DownTime: timeSpans{booleanTimeSpan(true)}},
+				&Scope{},
+			},
+			wantForced: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.wantForced, test.scopes.IsScalingForced())
+		})
+	}
+}
+
+func TestScopes_GetMinStateDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                 string
+		scopes               Scopes
+		wantMinStateDuration time.Duration
+	}{
+		{
+			name: "uppermost scope wins",
+			scopes: Scopes{
+				NewScope(),
+				&Scope{MinStateDuration: 30 * time.Minute},
+				NewScope(),
+				&Scope{MinStateDuration: time.Hour},
+				GetDefaultScope(),
+			},
+			wantMinStateDuration: 30 * time.Minute,
+		},
+		{
+			name: "defaults to zero",
+			scopes: Scopes{
+				NewScope(),
+				NewScope(),
+				NewScope(),
+				NewScope(),
+				GetDefaultScope(),
+			},
+			wantMinStateDuration: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.wantMinStateDuration, test.scopes.GetMinStateDuration())
+		})
+	}
+}
+
+func TestScopes_GetManualOverrideDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                       string
+		scopes                     Scopes
+		wantManualOverrideDuration time.Duration
+	}{
+		{
+			name: "uppermost scope wins",
+			scopes: Scopes{
+				&Scope{ManualOverride: 0},
+				NewScope(),
+				&Scope{ManualOverride: 2 * time.Hour},
+				NewScope(),
+				GetDefaultScope(),
+			},
+			wantManualOverrideDuration: 0,
+		},
+		{
+			name: "disabled by default",
+			scopes: Scopes{
+				NewScope(),
+				NewScope(),
+				NewScope(),
+				NewScope(),
+				GetDefaultScope(),
+			},
+			wantManualOverrideDuration: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.wantManualOverrideDuration, test.scopes.GetManualOverrideDuration())
+		})
+	}
+}
+
+func TestScopes_HasScalingChangedWithin(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		downtime    timeSpans
+		wantChanged bool
+	}{
+		{
+			name:        "downtime began within the window",
+			downtime:    timeSpans{absoluteTimeSpan{from: now.Add(-5 * time.Minute), to: now.Add(time.Hour)}},
+			wantChanged: true,
+		},
+		{
+			name:        "downtime began before the window",
+			downtime:    timeSpans{absoluteTimeSpan{from: now.Add(-time.Hour), to: now.Add(time.Hour)}},
+			wantChanged: false,
+		},
+		{
+			name:        "always in downtime",
+			downtime:    timeSpans{booleanTimeSpan(true)},
+			wantChanged: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			scopes := Scopes{&Scope{DownTime: test.downtime}, &Scope{}, &Scope{}, &Scope{}, &Scope{}}
+
+			assert.Equal(t, test.wantChanged, scopes.HasScalingChangedWithin(15*time.Minute))
+		})
+	}
+}
+
+func TestScopes_GetNextScalingChange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Truncate(time.Second)
+	monday, sunday := time.Monday, time.Sunday
+	eight, twenty := 8*Hour, 20*Hour
+
+	nextRelativeChange := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 8, 0, 0, 0, time.UTC)
+	if !nextRelativeChange.After(now) {
+		nextRelativeChange = nextRelativeChange.Add(12 * time.Hour)
+	}
+
+	if !nextRelativeChange.After(now) {
+		nextRelativeChange = nextRelativeChange.Add(12 * time.Hour)
+	}
+
+	tests := []struct {
+		name       string
+		downtime   timeSpans
+		wantChange time.Time
+		wantFound  bool
+	}{
+		{
+			name:       "downtime ends",
+			downtime:   timeSpans{absoluteTimeSpan{from: now.Add(-time.Hour), to: now.Add(time.Hour)}},
+			wantChange: now.Add(time.Hour),
+			wantFound:  true,
+		},
+		{
+			name: "downtime begins",
+			downtime: timeSpans{
+				absoluteTimeSpan{from: now.Add(2 * time.Hour), to: now.Add(3 * time.Hour)},
+				absoluteTimeSpan{from: now.Add(time.Hour), to: now.Add(2 * time.Hour)},
+			},
+			wantChange: now.Add(time.Hour),
+			wantFound:  true,
+		},
+		{
+			name: "relative downtime",
+			downtime: timeSpans{relativeTimeSpan{
+				timezone:    time.UTC,
+				weekdayFrom: &monday,
+				weekdayTo:   &sunday,
+				timeFrom:    &eight,
+				timeTo:      &twenty,
+			}},
+			wantChange: nextRelativeChange,
+			wantFound:  true,
+		},
+		{
+			name:      "downtime beyond the horizon",
+			downtime:  timeSpans{absoluteTimeSpan{from: now.Add(30 * 24 * time.Hour), to: now.Add(31 * 24 * time.Hour)}},
+			wantFound: false,
+		},
+		{
+			name:      "always in downtime",
+			downtime:  timeSpans{booleanTimeSpan(true)},
+			wantFound: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			scopes := Scopes{&Scope{DownTime: test.downtime}, &Scope{}, &Scope{}, &Scope{}, &Scope{}}
+
+			change, found := scopes.GetNextScalingChange()
+			assert.Equal(t, test.wantFound, found)
+
+			if test.wantFound {
+				assert.True(t, test.wantChange.Equal(change), "expected %s, got %s", test.wantChange, change)
+			}
+		})
+	}
+}
+
+func TestScopes_GetScalingScope(t *testing.T) {
+	t.Parallel()
+
+	manualScalingEnd := time.Now().Add(time.Hour)
+	downtime := timeSpans{booleanTimeSpan(true)}
+
+	tests := []struct {
+		name      string
+		scopes    Scopes
+		wantScope ScopeID
+		wantFound bool
+	}{
+		{
+			name:      "namespace downtime",
+			scopes:    Scopes{&Scope{}, &Scope{DownTime: downtime}, &Scope{}, &Scope{}, &Scope{DownTime: downtime}},
+			wantScope: ScopeNamespace,
+			wantFound: true,
+		},
+		{
+			name: "manual scaling takes precedence",
+			scopes: Scopes{
+				&Scope{DownTime: downtime},
+				&Scope{},
+				&Scope{ManualScaling: ScalingUp, ManualScalingEnd: &manualScalingEnd},
+				&Scope{},
+				&Scope{},
+			},
+			wantScope: ScopeCli,
+			wantFound: true,
+		},
+		{
+			name:      "no scaling",
+			scopes:    Scopes{&Scope{}, &Scope{}, &Scope{}, &Scope{}, &Scope{}},
+			wantFound: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			scope, found := test.scopes.GetScalingScope()
+			assert.Equal(t, test.wantFound, found)
+
+			if test.wantFound {
+				assert.Equal(t, test.wantScope, scope)
+			}
+		})
+	}
+}
+
+func TestScopes_IsMassDownscalingAcknowledged(t *testing.T) {
+	t.Parallel()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	assert.True(t, Scopes{&Scope{}, &Scope{AcknowledgedUntil: &future}, &Scope{}, &Scope{}, &Scope{}}.IsMassDownscalingAcknowledged())
+	assert.False(t, Scopes{&Scope{}, &Scope{AcknowledgedUntil: &past}, &Scope{}, &Scope{}, &Scope{}}.IsMassDownscalingAcknowledged())
+	assert.False(t, Scopes{&Scope{}, &Scope{}, &Scope{}, &Scope{}, &Scope{}}.IsMassDownscalingAcknowledged())
+}
+
 func TestScopes_GetUpscaleExcluded(t *testing.T) {
 	t.Parallel()
 
@@ -35,14 +35,16 @@ var (
 type TimeSpan interface {
 	// isTimeInSpan checks if time is in the timespan or not
 	isTimeInSpan(time time.Time, scopes Scopes) (bool, error)
+	// getBoundaries gets all times within (from, to] at which the timespan begins or ends
+	getBoundaries(from, to time.Time, scopes Scopes) []time.Time
 }
 
 type timeSpans []TimeSpan
 
-// inTimeSpans checks if current time is in one of the timespans or not.
-func (t *timeSpans) inTimeSpans(scopes Scopes) (bool, error) {
+// inTimeSpans checks if the time is in one of the timespans or not.
+func (t *timeSpans) inTimeSpans(scopes Scopes, at time.Time) (bool, error) {
 	for _, timespan := range *t {
-		isTimeInSpan, err := timespan.isTimeInSpan(time.Now(), scopes)
+		isTimeInSpan, err := timespan.isTimeInSpan(at, scopes)
 		if err != nil {
 			return false, fmt.Errorf("failed to check timespan: %w", err)
 		}
@@ -57,6 +59,21 @@ func (t *timeSpans) inTimeSpans(scopes Scopes) (bool, error) {
 	return false, nil
 }
 
+// getBoundaries gets all times within (from, to] at which one of the timespans begins or ends.
+func (t *timeSpans) getBoundaries(scopes Scopes, from, to time.Time) []time.Time {
+	var boundaries []time.Time
+	for _, timespan := range *t {
+		boundaries = append(boundaries, timespan.getBoundaries(from, to, scopes)...)
+	}
+
+	return boundaries
+}
+
+// isInInterval checks if the time is within (from, to].
+func isInInterval(at, from, to time.Time) bool {
+	return at.After(from) && !at.After(to)
+}
+
 // String implementation for timeSpans.
 func (t *timeSpans) String() string {
 	if *t != nil {
@@ -265,6 +282,31 @@ func (t relativeTimeSpan) isTimeInSpan(targetTime time.Time, scopes Scopes) (boo
 	return defaultedTimeSpan.isTimeOfDayInRange(timeOfDay) && defaultedTimeSpan.isWeekdayInRange(weekday), nil
 }
 
+// getBoundaries gets all times within (from, to] at which the timespan begins or ends.
+// Besides the times of day, the start of each day is included, since the weekday range might begin or end there.
+func (t relativeTimeSpan) getBoundaries(from, to time.Time, scopes Scopes) []time.Time {
+	defaultedTimeSpan, err := t.defaultTimeSpan(scopes)
+	if err != nil {
+		return nil
+	}
+
+	var boundaries []time.Time
+
+	year, month, startDay := from.In(defaultedTimeSpan.timezone).Date()
+
+	for day := startDay; !time.Date(year, month, day, 0, 0, 0, 0, defaultedTimeSpan.timezone).After(to); day++ {
+		for _, minutes := range []dayTime{0, *defaultedTimeSpan.timeFrom, *defaultedTimeSpan.timeTo} {
+			// time.Date normalizes the minutes, so daylight saving time changes and 24:00 are handled correctly
+			boundary := time.Date(year, month, day, 0, int(minutes), 0, 0, defaultedTimeSpan.timezone)
+			if isInInterval(boundary, from, to) {
+				boundaries = append(boundaries, boundary)
+			}
+		}
+	}
+
+	return boundaries
+}
+
 // String implementation for relativeTimeSpan.
 func (t relativeTimeSpan) String() string {
 	return fmt.Sprintf(
@@ -287,6 +329,19 @@ func (t absoluteTimeSpan) isTimeInSpan(targetTime time.Time, _ Scopes) (bool, er
 	return (t.from.Before(targetTime) || t.from.Equal(targetTime)) && t.to.After(targetTime), nil
 }
 
+// getBoundaries gets all times within (from, to] at which the timespan begins or ends.
+func (t absoluteTimeSpan) getBoundaries(from, to time.Time, _ Scopes) []time.Time {
+	var boundaries []time.Time
+
+	for _, boundary := range []time.Time{t.from, t.to} {
+		if isInInterval(boundary, from, to) {
+			boundaries = append(boundaries, boundary)
+		}
+	}
+
+	return boundaries
+}
+
 // String implementation for absoluteTimeSpan.
 func (t absoluteTimeSpan) String() string {
 	return fmt.Sprintf(
@@ -368,6 +423,15 @@ func (s directionalTimeSpan) isTimeInSpan(targetTime time.Time, _ Scopes) (bool,
 	return false, newIsTimeInSpanError("unknown timespan mode")
 }
 
+// getBoundaries gets the time within (from, to] at which the timespan begins or ends.
+func (s directionalTimeSpan) getBoundaries(from, to time.Time, _ Scopes) []time.Time {
+	if !isInInterval(s.time, from, to) {
+		return nil
+	}
+
+	return []time.Time{s.time}
+}
+
 // String implementation for directionalTimeSpan.
 func (s directionalTimeSpan) String() string {
 	return fmt.Sprintf(
@@ -436,6 +500,8 @@ type booleanTimeSpan bool
 
 func (b booleanTimeSpan) isTimeInSpan(_ time.Time, _ Scopes) (bool, error) { return bool(b), nil }
 
+func (b booleanTimeSpan) getBoundaries(_, _ time.Time, _ Scopes) []time.Time { return nil }
+
 // parseBooleanTimeSpan tries to parse the given timespan string to a booleanTimespan.
 func parseBooleanTimeSpan(timespanString string) (booleanTimeSpan, bool) {
 	switch strings.ToLower(timespanString) {